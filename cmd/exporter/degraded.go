@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/config"
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// degradedCollector serves only the exporter health metrics, for when the token file couldn't be read
+// It never reports a successful scrape, so /ready stays unready
+type degradedCollector struct {
+	exporterMetrics *metrics.ExporterMetrics
+	separate        bool // The exporter health metrics are served from /metrics/exporter instead
+}
+
+// RegisterWith registers the exporter health metrics, unless they are served separately
+func (c *degradedCollector) RegisterWith(registerer prometheus.Registerer) error {
+	if c.separate {
+		return nil
+	}
+	return c.exporterMetrics.RegisterWith(registerer)
+}
+
+// RegisterWithScrapeTimeout registers like RegisterWith; no Tado API calls are made to time out
+func (c *degradedCollector) RegisterWithScrapeTimeout(registerer prometheus.Registerer, _ time.Duration) error {
+	return c.RegisterWith(registerer)
+}
+
+// RegisterLastValuesWith registers like RegisterWith; the exporter health metrics are never fetched
+func (c *degradedCollector) RegisterLastValuesWith(registerer prometheus.Registerer) error {
+	return c.RegisterWith(registerer)
+}
+
+// RefreshIfStale does nothing, as nothing is fetched from Tado
+func (c *degradedCollector) RefreshIfStale(context.Context, time.Duration) error {
+	return nil
+}
+
+// LastSuccessfulScrape returns the zero time, as nothing is ever scraped from Tado
+func (c *degradedCollector) LastSuccessfulScrape() time.Time {
+	return time.Time{}
+}
+
+// serveDegraded serves /metrics, /health and /ready without a Tado collector until ctx is cancelled,
+// so tado_exporter_token_file_error can be scraped and alerted on instead of the exporter exiting
+// Per-home endpoints are not served, as no homes are known
+func serveDegraded(ctx context.Context, cfg *config.Config, exporterMetrics *metrics.ExporterMetrics, logs *subsystemLoggers) error {
+	degradedCfg := *cfg
+	degradedCfg.PerHomeMetrics = false
+
+	degraded := &degradedCollector{exporterMetrics: exporterMetrics, separate: cfg.SeparateExporterMetrics}
+	return StartServer(ctx, &degradedCfg, degraded, nil, logs.server, exporterMetrics)
+}
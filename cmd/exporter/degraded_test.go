@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/config"
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestServeDegraded tests that an unreadable token file can be scraped from /metrics, or /metrics/exporter
+// when served separately, while /ready stays unready
+func TestServeDegraded(t *testing.T) {
+	tests := []struct {
+		name     string
+		separate bool
+		path     string
+	}{
+		{name: "shared registry", path: "/metrics"},
+		{name: "separate exporter metrics", separate: true, path: "/metrics/exporter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Port:                    findFreePort(),
+				ScrapeTimeout:           5,
+				SeparateExporterMetrics: tt.separate,
+				PerHomeMetrics:          true,
+			}
+			logs, err := newSubsystemLoggers(cfg, getTestLogger())
+			require.NoError(t, err)
+
+			exporterMetrics := metrics.NewExporterMetricsUnregistered()
+			exporterMetrics.SetTokenFileError(true)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			done := make(chan error, 1)
+			go func() {
+				done <- serveDegraded(ctx, cfg, exporterMetrics, logs)
+			}()
+
+			baseURL := fmt.Sprintf("http://localhost:%d", cfg.Port)
+			var body string
+			require.Eventually(t, func() bool {
+				resp, err := http.Get(baseURL + tt.path)
+				if err != nil {
+					return false
+				}
+				defer func() { _ = resp.Body.Close() }()
+				data, _ := io.ReadAll(resp.Body)
+				body = string(data)
+				return resp.StatusCode == http.StatusOK
+			}, 2*time.Second, 20*time.Millisecond)
+			assert.Contains(t, body, "tado_exporter_token_file_error 1")
+
+			resp, err := http.Get(baseURL + "/ready")
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+			assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+			cancel()
+			assert.NoError(t, <-done)
+		})
+	}
+}
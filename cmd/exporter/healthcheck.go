@@ -0,0 +1,50 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/config"
+)
+
+// healthCheckTimeout bounds how long -health-check waits for the /ready response
+const healthCheckTimeout = 5 * time.Second
+
+// runHealthCheck probes the /ready endpoint of the exporter listening on cfg.Port and
+// returns the process exit code: 0 if the exporter is ready, 1 otherwise
+// This lets container health checks reuse the exporter binary instead of needing curl
+func runHealthCheck(cfg *config.Config, stderr io.Writer) int {
+	if err := checkReady(cfg); err != nil {
+		_, _ = fmt.Fprintf(stderr, "Health check failed: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// checkReady performs a GET against the local /ready endpoint, failing unless it returns 200
+func checkReady(cfg *config.Config) error {
+	client := &http.Client{Timeout: healthCheckTimeout}
+
+	scheme := "http"
+	if cfg.TLSCertFile != "" {
+		scheme = "https"
+		// The probe always targets localhost, which the serving certificate rarely names
+		client.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS12}, //nolint:gosec
+		}
+	}
+
+	resp, err := client.Get(fmt.Sprintf("%s://localhost:%d/ready", scheme, cfg.Port))
+	if err != nil {
+		return fmt.Errorf("request to /ready failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("exporter not ready: /ready returned status code %d", resp.StatusCode)
+	}
+	return nil
+}
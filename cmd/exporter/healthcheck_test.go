@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/collector"
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/collector/mocks"
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/config"
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/metrics"
+	"github.com/clambin/tado/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newReadyTestCollector returns a collector whose scrapes always succeed
+func newReadyTestCollector(t *testing.T) *collector.TadoCollector {
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]tado.HomeId{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	return collector.NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", getTestLogger())
+}
+
+// scrapeOnce runs a single collection so the collector records its outcome
+func scrapeOnce(tadoCollector *collector.TadoCollector) {
+	ch := make(chan prometheus.Metric, 100)
+	tadoCollector.Collect(ch)
+	close(ch)
+}
+
+// TestHandleReady tests that /ready reflects how recently a scrape succeeded
+func TestHandleReady(t *testing.T) {
+	tadoCollector := newReadyTestCollector(t)
+
+	status := func(maxAge time.Duration) int {
+		recorder := httptest.NewRecorder()
+		handleReady(tadoCollector, maxAge, nil).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusServiceUnavailable, status(0), "not ready before the first successful scrape")
+
+	scrapeOnce(tadoCollector)
+	assert.Equal(t, http.StatusOK, status(time.Minute))
+	assert.Equal(t, http.StatusOK, status(0), "0 accepts a successful scrape of any age")
+
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, http.StatusServiceUnavailable, status(time.Millisecond), "not ready once the last success is too old")
+}
+
+// TestHandleReadyCircuitBreaker tests that /ready is not ready while the circuit breaker is open
+func TestHandleReadyCircuitBreaker(t *testing.T) {
+	tadoCollector := newReadyTestCollector(t)
+	scrapeOnce(tadoCollector)
+
+	exporterMetrics := metrics.NewExporterMetricsUnregistered()
+	handler := handleReady(tadoCollector, time.Minute, exporterMetrics.CircuitBreakerOpen)
+
+	status := func() int {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		return recorder.Code
+	}
+
+	exporterMetrics.SetCircuitBreakerOpen(true)
+	assert.Equal(t, http.StatusServiceUnavailable, status(), "not ready while the breaker is open, despite a recent scrape")
+
+	exporterMetrics.SetCircuitBreakerOpen(false)
+	assert.Equal(t, http.StatusOK, status())
+}
+
+// TestRunHealthCheck tests the -health-check exit code against a running exporter and with none listening
+func TestRunHealthCheck(t *testing.T) {
+	cfg := &config.Config{
+		Port:            findFreePort(),
+		ScrapeTimeout:   5,
+		TokenPassphrase: "test",
+		TokenPath:       "/tmp/test-token.json",
+		ReadyMaxAge:     time.Minute,
+	}
+
+	var stderr bytes.Buffer
+	assert.Equal(t, 1, runHealthCheck(cfg, &stderr), "no exporter is listening")
+	assert.Contains(t, stderr.String(), "Health check failed")
+
+	tadoCollector := newReadyTestCollector(t)
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- StartServer(ctx, cfg, tadoCollector, metricDescs, getTestLogger(), nil)
+	}()
+
+	// Give server time to start
+	time.Sleep(100 * time.Millisecond)
+
+	stderr.Reset()
+	assert.Equal(t, 1, runHealthCheck(cfg, &stderr), "running but no scrape has succeeded yet")
+	assert.Contains(t, stderr.String(), "status code 503")
+
+	resp, err := http.Get(fmt.Sprintf("http://localhost:%d/metrics", cfg.Port))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, 0, runHealthCheck(cfg, &stderr), "running with a recent successful scrape")
+
+	cancel()
+	assert.NoError(t, <-done)
+}
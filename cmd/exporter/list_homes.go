@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/auth"
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/collector"
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/config"
+)
+
+// authenticateClient is the clientLoader used by -list-homes, starting the device flow when no token is stored
+// yet, as the exporter itself does, so new users can find their home IDs before the first real start
+func authenticateClient(cfg *config.Config) clientLoader {
+	return func(ctx context.Context, tokenPath, tokenPassphrase string) (collector.TadoAPI, error) {
+		client, err := auth.NewAuthenticatedTadoClient(ctx, tokenPath, tokenPassphrase, cfg.ServerURL, cfg.AuthURLFile, cfg.DeviceFlowTimeout, nil)
+		if err != nil {
+			return nil, err
+		}
+		return collector.NewTadoClientAdapter(client), nil
+	}
+}
+
+// runListHomes authenticates each account and writes the ID and name of each of its homes to out, for
+// configuring -home-id and -exclude-home-ids. Errors go to errOut. It returns the process exit code
+func runListHomes(ctx context.Context, cfg *config.Config, out, errOut io.Writer, load clientLoader) int {
+	if err := cfg.Validate(); err != nil {
+		_, _ = fmt.Fprintf(errOut, "Configuration error: %v\n", err)
+		return 1
+	}
+
+	accounts := cfg.Accounts
+	if len(accounts) == 0 {
+		accounts = []config.Account{{TokenPath: cfg.TokenPath, TokenPassphrase: cfg.TokenPassphrase}}
+	}
+	for i, account := range accounts {
+		if account.Name != "" {
+			if i > 0 {
+				_, _ = fmt.Fprintln(out)
+			}
+			_, _ = fmt.Fprintf(out, "Account %s:\n", account.Name)
+		}
+
+		api, err := load(ctx, account.TokenPath, account.TokenPassphrase)
+		if err != nil {
+			_, _ = fmt.Fprintf(errOut, "Authentication failed: %v\n", err)
+			return 1
+		}
+		if err := printHomes(ctx, api, out); err != nil {
+			_, _ = fmt.Fprintf(errOut, "Failed to list homes: %v\n", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// printHomes calls GetMe and writes the ID and name of each home as an aligned table
+func printHomes(ctx context.Context, api collector.TadoAPI, out io.Writer) error {
+	ctx, cancel := context.WithTimeout(ctx, validateAPITimeout)
+	defer cancel()
+
+	user, err := api.GetMe(ctx)
+	if err != nil {
+		return err
+	}
+	if user == nil || user.Homes == nil {
+		return errors.New("response has no homes field")
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "HOME ID\tNAME")
+	for _, home := range *user.Homes {
+		if home.Id == nil {
+			continue
+		}
+		name := ""
+		if home.Name != nil {
+			name = *home.Name
+		}
+		_, _ = fmt.Fprintf(w, "%d\t%s\n", *home.Id, name)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/collector"
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/collector/mocks"
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/config"
+	"github.com/clambin/tado/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestRunListHomes tests that the ID and name of each home are printed, per account when several are configured
+func TestRunListHomes(t *testing.T) {
+	namedHomes := func(names map[tado.HomeId]string, order ...tado.HomeId) *mocks.MockTadoAPI {
+		homes := make([]tado.HomeBase, 0, len(order))
+		for _, id := range order {
+			name := names[id]
+			homes = append(homes, tado.HomeBase{Id: &id, Name: &name})
+		}
+		mockAPI := &mocks.MockTadoAPI{}
+		mockAPI.On("GetMe", mock.Anything).Return(&tado.User{Homes: &homes}, nil)
+		return mockAPI
+	}
+
+	t.Run("single account", func(t *testing.T) {
+		mockAPI := namedHomes(map[tado.HomeId]string{123456: "Main House", 234567: "Holiday Flat"}, 123456, 234567)
+		load := func(context.Context, string, string) (collector.TadoAPI, error) { return mockAPI, nil }
+
+		var out, errOut bytes.Buffer
+		code := runListHomes(context.Background(), validConfig(), &out, &errOut, load)
+		assert.Equal(t, 0, code)
+		assert.Empty(t, errOut.String())
+		assert.Equal(t, "HOME ID  NAME\n123456   Main House\n234567   Holiday Flat\n", out.String())
+	})
+
+	t.Run("several accounts", func(t *testing.T) {
+		apis := map[string]collector.TadoAPI{
+			"/tmp/home.json":  namedHomes(map[tado.HomeId]string{1: "Home"}, 1),
+			"/tmp/cabin.json": namedHomes(map[tado.HomeId]string{2: "Cabin"}, 2),
+		}
+		load := func(_ context.Context, tokenPath, _ string) (collector.TadoAPI, error) { return apis[tokenPath], nil }
+
+		cfg := validConfig()
+		cfg.Accounts = []config.Account{
+			{Name: "home", TokenPath: "/tmp/home.json", TokenPassphrase: "secret"},
+			{Name: "cabin", TokenPath: "/tmp/cabin.json", TokenPassphrase: "secret"},
+		}
+
+		var out, errOut bytes.Buffer
+		code := runListHomes(context.Background(), cfg, &out, &errOut, load)
+		assert.Equal(t, 0, code, errOut.String())
+		assert.Equal(t, "Account home:\nHOME ID  NAME\n1        Home\n\nAccount cabin:\nHOME ID  NAME\n2        Cabin\n", out.String())
+	})
+
+	t.Run("authentication failure", func(t *testing.T) {
+		load := func(context.Context, string, string) (collector.TadoAPI, error) {
+			return nil, errors.New("device code expired")
+		}
+
+		var out, errOut bytes.Buffer
+		code := runListHomes(context.Background(), validConfig(), &out, &errOut, load)
+		assert.Equal(t, 1, code)
+		assert.Contains(t, errOut.String(), "Authentication failed: device code expired")
+	})
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/config"
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/metrics"
+)
+
+// printMetricList writes every Tado and exporter health metric, named as configured by
+// -metric-compat, to w as an indented JSON array
+func printMetricList(w io.Writer, cfg *config.Config) error {
+	metricCompat, err := metrics.ParseMetricCompat(cfg.MetricCompat)
+	if err != nil {
+		return err
+	}
+	metricDescs, err := metrics.NewMetricDescriptorsUnregisteredWithCompat(metricCompat)
+	if err != nil {
+		return fmt.Errorf("failed to create metric descriptors: %w", err)
+	}
+
+	infos, err := metrics.ListMetrics(metricDescs, metrics.NewExporterMetricsUnregistered())
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(infos)
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/config"
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPrintMetricList tests that the JSON listing includes tado_temperature_measured_celsius with its labels
+func TestPrintMetricList(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, printMetricList(&out, &config.Config{MetricCompat: "v1"}))
+
+	var infos []metrics.MetricInfo
+	require.NoError(t, json.Unmarshal(out.Bytes(), &infos))
+
+	var found bool
+	for _, info := range infos {
+		if info.Name == "tado_temperature_measured_celsius" {
+			found = true
+			assert.Equal(t, "gauge", info.Type)
+			assert.Equal(t, []string{"home_id", "zone_id", "zone_name", "zone_type"}, info.Labels)
+		}
+	}
+	assert.True(t, found, "tado_temperature_measured_celsius should be listed")
+
+	assert.Error(t, printMetricList(&out, &config.Config{MetricCompat: "v9"}))
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -16,6 +17,36 @@ import (
 func main() {
 	cfg := config.Load()
 
+	// Version mode only reports how the binary was built, before any validation or authentication
+	if cfg.Version {
+		printVersion(os.Stdout)
+		os.Exit(0)
+	}
+
+	// Metric listing mode only describes the metrics, before any validation or authentication
+	if cfg.ListMetrics {
+		if err := printMetricList(os.Stdout, cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to list metrics: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Health check mode only probes the running exporter, so it needs no passphrase or validation
+	if cfg.HealthCheck {
+		os.Exit(runHealthCheck(cfg, os.Stderr))
+	}
+
+	// Validation mode checks the configuration itself, and never starts the device flow
+	if cfg.ValidateOnly {
+		os.Exit(runValidation(context.Background(), cfg, os.Stdout, loadStoredClient(cfg)))
+	}
+
+	// Home listing mode authenticates like the exporter does, but only reports the account's homes
+	if cfg.ListHomes {
+		os.Exit(runListHomes(context.Background(), cfg, os.Stdout, os.Stderr, authenticateClient(cfg)))
+	}
+
 	if err := cfg.Validate(); err != nil {
 		fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
 		os.Exit(1)
@@ -27,62 +58,257 @@ func main() {
 		os.Exit(1)
 	}
 
-	log.Info("tado-prometheus-exporter starting", "config", cfg.String())
+	logs, err := newSubsystemLoggers(cfg, log)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Logger initialization error: %v\n", err)
+		os.Exit(1)
+	}
+
+	log.Info("tado-prometheus-exporter starting", cfg.LogFields()...)
 
 	ctx := SetupGracefulShutdown()
 
-	tadoClient, metricDescs, err := initializeAuth(context.Background(), cfg, log)
-	if err != nil {
-		log.Error("Authentication failed", "error", err.Error())
+	// Metrics are exposed through the Tado collector's registry, so nothing is registered globally
+	exporterMetrics := metrics.NewExporterMetricsUnregisteredWithLabels(cfg.ConstantLabels)
+	if err := exporterMetrics.Validate(); err != nil {
+		log.Error("Exporter health metrics initialization failed", "error", err.Error())
 		os.Exit(1)
 	}
+	recordFeatures(cfg, exporterMetrics)
+	log.Info("Exporter health metrics initialized")
 
-	exporterMetrics, err := metrics.NewExporterMetrics()
+	tadoClient, metricDescs, err := initializeCollector(context.Background(), cfg, exporterMetrics, logs)
+	if errors.Is(err, auth.ErrTokenFileUnreadable) {
+		// Keep serving tado_exporter_token_file_error, so the broken token can be alerted on, until stopped
+		log.Error("Authentication failed, serving exporter metrics only until restarted", "error", err.Error())
+		if err := serveDegraded(ctx, cfg, exporterMetrics, logs); err != nil {
+			log.Error("Server initialization failed", "error", err.Error())
+		}
+		os.Exit(1)
+	}
 	if err != nil {
-		log.Error("Exporter metrics initialization failed", "error", err.Error())
+		log.Error("Authentication failed", "error", err.Error())
 		os.Exit(1)
 	}
-	log.Info("Exporter health metrics initialized")
 
-	if err := initializeMetricsAndServer(ctx, cfg, tadoClient, metricDescs, exporterMetrics, log); err != nil {
+	if err := initializeMetricsAndServer(ctx, cfg, tadoClient, metricDescs, exporterMetrics, logs); err != nil {
 		log.Error("Server initialization failed", "error", err.Error())
 		os.Exit(1)
 	}
 }
 
-// initializeAuth handles OAuth authentication and returns authenticated Tado client and metrics descriptors
-func initializeAuth(ctx context.Context, cfg *config.Config, log *logger.Logger) (*collector.TadoCollector, *metrics.MetricDescriptors, error) {
-	metricDescs, err := metrics.NewMetricDescriptors()
+// recordFeatures exposes which optional features the configuration enables on tado_exporter_feature_enabled
+func recordFeatures(cfg *config.Config, exporterMetrics *metrics.ExporterMetrics) {
+	for _, feature := range cfg.Features() {
+		exporterMetrics.SetFeatureEnabled(feature.Name, feature.Enabled)
+	}
+}
+
+// subsystemLoggers holds the logger for each subsystem, derived from the base logger so that
+// TADO_LOG_LEVEL_<SUBSYSTEM> can make one subsystem more or less verbose than the rest
+type subsystemLoggers struct {
+	base      *logger.Logger
+	collector *logger.Logger
+	auth      *logger.Logger
+	server    *logger.Logger
+}
+
+// newSubsystemLoggers derives the subsystem loggers from base; subsystems without a level inherit base
+func newSubsystemLoggers(cfg *config.Config, base *logger.Logger) (*subsystemLoggers, error) {
+	collectorLog, err := base.WithLevel(cfg.LogLevelCollector)
+	if err != nil {
+		return nil, fmt.Errorf("collector logger: %w", err)
+	}
+	authLog, err := base.WithLevel(cfg.LogLevelAuth)
+	if err != nil {
+		return nil, fmt.Errorf("auth logger: %w", err)
+	}
+	serverLog, err := base.WithLevel(cfg.LogLevelServer)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create metric descriptors: %w", err)
+		return nil, fmt.Errorf("server logger: %w", err)
 	}
 
+	return &subsystemLoggers{base: base, collector: collectorLog, auth: authLog, server: serverLog}, nil
+}
+
+// initializeCollector authenticates the configured Tado account, or each of several accounts, and
+// returns the collector to serve. The metric descriptors are nil with several accounts, which each have their own
+func initializeCollector(ctx context.Context, cfg *config.Config, exporterMetrics *metrics.ExporterMetrics, logs *subsystemLoggers) (metricsCollector, *metrics.MetricDescriptors, error) {
+	if len(cfg.Accounts) > 0 {
+		multiAccountCollector, err := initializeAccounts(ctx, cfg, exporterMetrics, logs)
+		exporterMetrics.SetTokenFileError(errors.Is(err, auth.ErrTokenFileUnreadable))
+		return multiAccountCollector, nil, err
+	}
+
+	metricDescs, err := newMetricDescriptors(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tadoCollector, tokenTracker, err := initializeAuth(ctx, cfg, cfg.TokenPath, cfg.TokenPassphrase, metricDescs, exporterMetrics, logs)
+	exporterMetrics.SetTokenFileError(errors.Is(err, auth.ErrTokenFileUnreadable))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Restore the last known metric values so /metrics has data before the first scrape completes
+	if cfg.SnapshotPath != "" {
+		restored, err := metricDescs.LoadSnapshot(cfg.SnapshotPath, cfg.SnapshotMaxAge)
+		if err != nil {
+			logs.base.Warn("Failed to load metrics snapshot, starting empty", "path", cfg.SnapshotPath, "error", err.Error())
+		} else {
+			logs.base.Info("Metrics snapshot loaded", "path", cfg.SnapshotPath, "series_restored", restored)
+		}
+	}
+
+	tadoCollector.WithTokenExpiry(tokenTracker.Expiry)
+	if cfg.SeparateExporterMetrics {
+		tadoCollector.WithSeparateExporterMetrics(exporterMetrics)
+	} else {
+		tadoCollector.WithExporterMetrics(exporterMetrics)
+	}
+
+	return tadoCollector, metricDescs, nil
+}
+
+// initializeAccounts authenticates each configured account and returns a collector labelling
+// every account's metrics with its name
+func initializeAccounts(ctx context.Context, cfg *config.Config, exporterMetrics *metrics.ExporterMetrics, logs *subsystemLoggers) (*collector.MultiAccountCollector, error) {
+	accounts := make([]collector.Account, 0, len(cfg.Accounts))
+	for _, account := range cfg.Accounts {
+		metricDescs, err := newMetricDescriptors(cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		// Each account records its own scrape and Tado API health, exposed with its account label
+		accountMetrics := metrics.NewExporterMetricsUnregisteredWithLabels(cfg.ConstantLabels)
+
+		logs.auth.Info("Authenticating Tado account", "account", account.Name)
+		tadoCollector, tokenTracker, err := initializeAuth(ctx, cfg, account.TokenPath, account.TokenPassphrase, metricDescs, accountMetrics, logs)
+		if err != nil {
+			return nil, fmt.Errorf("account %s: %w", account.Name, err)
+		}
+		tadoCollector.WithTokenExpiry(tokenTracker.Expiry)
+
+		accounts = append(accounts, collector.Account{Name: account.Name, Collector: tadoCollector, ExporterMetrics: accountMetrics})
+	}
+
+	multiAccountCollector := collector.NewMultiAccountCollector(accounts)
+	if cfg.SeparateExporterMetrics {
+		return multiAccountCollector.WithSeparateExporterMetrics(exporterMetrics), nil
+	}
+	return multiAccountCollector.WithExporterMetrics(exporterMetrics), nil
+}
+
+// newMetricDescriptors creates unregistered metric descriptors using the configured metric names
+// The descriptors are validated so naming mistakes fail startup before the HTTP server binds its port
+func newMetricDescriptors(cfg *config.Config) (*metrics.MetricDescriptors, error) {
+	// Validated with the rest of the configuration, so parsing can't fail here
+	metricCompat, _ := metrics.ParseMetricCompat(cfg.MetricCompat)
+	metricDescs, err := metrics.NewMetricDescriptorsUnregisteredWithLabels(metricCompat, cfg.ConstantLabels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metric descriptors: %w", err)
+	}
+	if err := metricDescs.Validate(); err != nil {
+		return nil, err
+	}
+	return metricDescs, nil
+}
+
+// initializeAuth handles OAuth authentication for the account stored at tokenPath and returns a collector
+// for it along with the tracker observing its tokens
+func initializeAuth(ctx context.Context, cfg *config.Config, tokenPath, tokenPassphrase string, metricDescs *metrics.MetricDescriptors, exporterMetrics *metrics.ExporterMetrics, logs *subsystemLoggers) (*collector.TadoCollector, *auth.TokenTracker, error) {
 	// Create authenticated Tado client with encrypted token storage
 	// This handles:
 	// - Loading existing token if valid
 	// - Performing device code OAuth flow if no valid token
 	// - Storing encrypted token with passphrase
-	log.Info("Initializing Tado authentication...")
-	tadoClientRaw, err := auth.NewAuthenticatedTadoClient(ctx, cfg.TokenPath, cfg.TokenPassphrase)
+	logs.auth.Info("Initializing Tado authentication...")
+	tokenTracker := auth.NewTokenTracker(exporterMetrics.IncrementTokenRefreshes)
+	tadoClientRaw, err := auth.NewAuthenticatedTadoClient(ctx, tokenPath, tokenPassphrase, cfg.ServerURL, cfg.AuthURLFile, cfg.DeviceFlowTimeout, tokenTracker)
 	if err != nil {
 		return nil, nil, fmt.Errorf("authentication failed: %w", err)
 	}
 
-	log.Info("Successfully authenticated", "token_path", cfg.TokenPath)
+	logs.auth.Info("Successfully authenticated", "token_path", tokenPath)
+
+	var tadoClient collector.TadoAPI = collector.NewTadoClientAdapterWithLogger(tadoClientRaw, logs.collector, cfg.SlowCallThreshold).
+		WithOnUnavailable(exporterMetrics.IncrementAPIUnavailable)
 
-	tadoClient := collector.NewTadoClientAdapter(tadoClientRaw)
+	tadoClient = wrapTadoAPI(cfg, tadoClient, exporterMetrics, logs.collector)
 
+	// Validated with the rest of the configuration, so parsing can't fail here
+	temperatureUnits, _ := metrics.ParseTemperatureUnits(cfg.TemperatureUnits)
+	deviceMetrics, _ := metrics.ParseDeviceMetricsLevel(cfg.DeviceMetrics)
 	scrapeTimeout := time.Duration(cfg.ScrapeTimeout) * time.Second
-	tadoCollector := collector.NewTadoCollectorWithLogger(tadoClient, metricDescs, scrapeTimeout, cfg.HomeID, log)
+	tadoCollector := collector.NewTadoCollectorWithLogger(tadoClient, metricDescs, scrapeTimeout, cfg.HomeID, logs.collector).
+		WithStrictMode(cfg.StrictMode).
+		WithSkipWeather(cfg.SkipWeather).
+		WithAllowNoHomes(cfg.AllowNoHomes).
+		WithTemperatureOffsets(cfg.TemperatureOffsets).
+		WithZoneDeviceCalls(cfg.ZoneDeviceWorkers, cfg.ZoneDeviceTimeout).
+		WithTemperatureUnits(temperatureUnits).
+		WithTemperaturePrecision(cfg.TemperaturePrecision).
+		WithDeviceMetrics(deviceMetrics).
+		WithExposeAccountEmail(cfg.ExposeEmail).
+		WithMaxLabelLength(cfg.MaxLabelLength).
+		WithExcludedHomeIDs(cfg.ExcludeHomeIDs).
+		WithHomeFailureSkip(cfg.HomeFailureThreshold, cfg.HomeFailureCooldown)
 
-	return tadoCollector, metricDescs, nil
+	return tadoCollector, tokenTracker, nil
+}
+
+// wrapTadoAPI wraps api, the client adapter, with the configured rate limiter, circuit breaker and retries
+// The breaker sits outside the rate limiter so calls it fails fast take no request budget, and retries
+// sit outermost so every attempt is seen by the breaker and rate limited
+func wrapTadoAPI(cfg *config.Config, api collector.TadoAPI, exporterMetrics *metrics.ExporterMetrics, log *logger.Logger) collector.TadoAPI {
+	// Keep calls under the configured rate
+	if cfg.MaxRequestsPerMinute > 0 {
+		api = collector.NewRateLimitedAPI(api, collector.RateLimiterSettings{
+			RequestsPerMinute: cfg.MaxRequestsPerMinute,
+			OnThrottle:        exporterMetrics.IncrementThrottledRequests,
+		})
+	}
+
+	// Stop calling Tado during prolonged outages so scrapes fail fast instead of timing out
+	if cfg.CircuitBreakerMaxFailures > 0 {
+		api = collector.NewCircuitBreakerAPI(api, collector.CircuitBreakerSettings{
+			MaxFailures: cfg.CircuitBreakerMaxFailures,
+			OpenTimeout: cfg.CircuitBreakerOpenTimeout,
+			OnStateChange: func(from, to collector.CircuitState) {
+				log.Warn("Tado API circuit breaker state changed", "from", from.String(), "to", to.String())
+				exporterMetrics.SetCircuitBreakerOpen(to != collector.CircuitClosed)
+			},
+		})
+	}
+
+	// Retry transient failures outermost
+	if cfg.MaxRetries > 0 {
+		api = collector.NewRetryingAPI(api, collector.RetrySettings{
+			MaxRetries: cfg.MaxRetries,
+			Backoff:    cfg.RetryBackoff,
+			OnRetry:    exporterMetrics.IncrementRetries,
+		})
+	}
+
+	return api
 }
 
 // initializeMetricsAndServer initializes metrics and starts the HTTP server
-func initializeMetricsAndServer(ctx context.Context, cfg *config.Config, tadoCollector *collector.TadoCollector, metricDescs *metrics.MetricDescriptors, exporterMetrics *metrics.ExporterMetrics, log *logger.Logger) error {
-	tadoCollector.WithExporterMetrics(exporterMetrics)
+func initializeMetricsAndServer(ctx context.Context, cfg *config.Config, tadoCollector metricsCollector, metricDescs *metrics.MetricDescriptors, exporterMetrics *metrics.ExporterMetrics, logs *subsystemLoggers) error {
+	logs.base.Info("Prometheus metrics registered successfully")
 
-	log.Info("Prometheus metrics registered successfully")
+	serverErr := StartServer(ctx, cfg, tadoCollector, metricDescs, logs.server, exporterMetrics)
+
+	// Save metric values so the next start can serve them immediately
+	if cfg.SnapshotPath != "" {
+		if err := metricDescs.SaveSnapshot(cfg.SnapshotPath); err != nil {
+			logs.base.Warn("Failed to save metrics snapshot", "path", cfg.SnapshotPath, "error", err.Error())
+		} else {
+			logs.base.Info("Metrics snapshot saved", "path", cfg.SnapshotPath)
+		}
+	}
 
-	return StartServer(ctx, cfg, tadoCollector, metricDescs, log, exporterMetrics)
+	return serverErr
 }
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/collector"
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/collector/mocks"
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/config"
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWrapTadoAPI_OpenBreakerUsesNoRequestBudget tests that calls failed fast by an open circuit breaker
+// take no rate limiter tokens, so they neither wait for a token nor leave fewer for the recovered API
+func TestWrapTadoAPI_OpenBreakerUsesNoRequestBudget(t *testing.T) {
+	cfg := &config.Config{
+		MaxRequestsPerMinute:      2,
+		CircuitBreakerMaxFailures: 1,
+		CircuitBreakerOpenTimeout: time.Hour,
+	}
+	exporterMetrics := metrics.NewExporterMetricsUnregistered()
+	registry := prometheus.NewRegistry()
+	require.NoError(t, exporterMetrics.RegisterWith(registry))
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.On("GetMe", mock.Anything).Return(nil, errors.New("tado unavailable"))
+
+	api := wrapTadoAPI(cfg, mockAPI, exporterMetrics, getTestLogger())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// The first failure opens the breaker, using one of the two tokens
+	_, err := api.GetMe(ctx)
+	require.Error(t, err)
+
+	// Far more calls than the budget allows are failed fast by the breaker, not rejected by the limiter
+	for i := 0; i < 10; i++ {
+		_, err = api.GetMe(ctx)
+		assert.ErrorIs(t, err, collector.ErrCircuitOpen)
+	}
+	mockAPI.AssertNumberOfCalls(t, "GetMe", 1)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() == "tado_exporter_throttled_requests_total" {
+			assert.Equal(t, 0.0, family.Metric[0].GetCounter().GetValue(), "no call waited for a token")
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/logger"
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/metrics"
+)
+
+// runOTLPExport pushes metrics with exporter every interval until ctx is cancelled
+// Before each push, refresh fetches from Tado unless a scrape did within the interval, so the pushed
+// values stay current without Prometheus scraping /metrics. Failures are logged and retried at the next interval
+func runOTLPExport(ctx context.Context, exporter *metrics.OTLPExporter, refresh func(context.Context, time.Duration) error, interval time.Duration, log *logger.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := refresh(ctx, interval); err != nil {
+				log.Warn("Failed to collect Tado metrics for OTLP push, pushing last known values", "error", err.Error())
+			}
+			if err := exporter.Push(ctx); err != nil {
+				log.Warn("OTLP metrics push failed", "error", err.Error())
+			} else {
+				log.Debug("OTLP metrics pushed")
+			}
+		}
+	}
+}
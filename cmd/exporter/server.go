@@ -2,10 +2,17 @@ package main
 
 import (
 	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -14,14 +21,42 @@ import (
 	"github.com/andreweacott/tado-prometheus-exporter/pkg/logger"
 	"github.com/andreweacott/tado-prometheus-exporter/pkg/metrics"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
 )
 
+// scrapeTimeoutHeader lets a /metrics request ask for a longer scrape timeout, in seconds
+const scrapeTimeoutHeader = "X-Scrape-Timeout"
+
+// shutdownTimeout bounds how long shutdown waits for in-flight requests and background tasks
+const shutdownTimeout = 10 * time.Second
+
+// writeTimeoutMargin is added to the longest scrape timeout, leaving a slow scrape time to write its response
+const writeTimeoutMargin = 5 * time.Second
+
+// metricsCollector is the collector served on /metrics: a TadoCollector, or a MultiAccountCollector
+// when several Tado accounts are configured
+type metricsCollector interface {
+	RegisterWith(registerer prometheus.Registerer) error
+	RegisterWithScrapeTimeout(registerer prometheus.Registerer, scrapeTimeout time.Duration) error
+	RegisterLastValuesWith(registerer prometheus.Registerer) error
+	RefreshIfStale(ctx context.Context, maxAge time.Duration) error
+	LastSuccessfulScrape() time.Time
+}
+
+// accountsCollector is implemented by a metricsCollector keeping exporter metrics for each of several
+// accounts, which /metrics/exporter and /ready then take from it instead of the process-wide ones
+type accountsCollector interface {
+	RegisterExporterMetricsWith(registerer prometheus.Registerer) error
+	CircuitBreakerOpen() bool
+}
+
 // StartServer starts the HTTP server with Prometheus endpoints
 func StartServer(
 	ctx context.Context,
 	cfg *config.Config,
-	tadoCollector *collector.TadoCollector,
+	tadoCollector metricsCollector,
 	metricDescriptors *metrics.MetricDescriptors,
 	log *logger.Logger,
 	exporterMetrics *metrics.ExporterMetrics,
@@ -30,38 +65,144 @@ func StartServer(
 
 	// Register the Tado collector
 	// The collector includes both Tado metrics and exporter health metrics (if provided)
-	if err := registry.Register(tadoCollector); err != nil {
+	if err := tadoCollector.RegisterWith(registry); err != nil {
 		return fmt.Errorf("failed to register Tado collector: %w", err)
 	}
+	if err := registerGoMetrics(cfg, registry); err != nil {
+		return err
+	}
 
 	mux := http.NewServeMux()
 
 	// Register /metrics endpoint with our custom registry
-	metricsHandler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{
-		EnableOpenMetrics: true,
-		Timeout:           time.Duration(cfg.ScrapeTimeout) * time.Second,
-	})
-	mux.Handle("/metrics", metricsHandler)
+	mux.Handle("/metrics", protectMetricsHandler(cfg, exporterMetrics, overrideScrapeTimeout(cfg, tadoCollector, newPromHandler(cfg, registry))))
+
+	// Metrics pushed over OTLP: the values last fetched, by a /metrics scrape or by the push itself when
+	// /metrics isn't scraped, plus /metrics/exporter when served separately
+	var otlpGatherers prometheus.Gatherers
+	if cfg.OTLPEndpoint != "" {
+		otlpRegistry := prometheus.NewRegistry()
+		if err := tadoCollector.RegisterLastValuesWith(otlpRegistry); err != nil {
+			return fmt.Errorf("failed to register OTLP collector: %w", err)
+		}
+		if err := registerGoMetrics(cfg, otlpRegistry); err != nil {
+			return err
+		}
+		otlpGatherers = append(otlpGatherers, otlpRegistry)
+	}
+
+	// Register /metrics/exporter, serving the exporter health metrics from their own registry
+	// The collector must then record into exporterMetrics without exposing them itself
+	if cfg.SeparateExporterMetrics && exporterMetrics != nil {
+		exporterRegistry := prometheus.NewRegistry()
+		otlpGatherers = append(otlpGatherers, exporterRegistry)
+		registerExporterMetrics := exporterMetrics.RegisterWith
+		if accounts, ok := tadoCollector.(accountsCollector); ok {
+			registerExporterMetrics = accounts.RegisterExporterMetricsWith
+		}
+		if err := registerExporterMetrics(exporterRegistry); err != nil {
+			return fmt.Errorf("failed to register exporter metrics: %w", err)
+		}
+		mux.Handle("/metrics/exporter", newMetricsHandler(cfg, exporterRegistry, exporterMetrics))
+	}
+
+	// Register /metrics/<home_id> endpoints, each backed by an isolated per-home registry
+	if cfg.PerHomeMetrics {
+		singleAccountCollector, ok := tadoCollector.(*collector.TadoCollector)
+		if !ok {
+			return fmt.Errorf("per-home metrics are not supported with multiple accounts")
+		}
+		if err := registerPerHomeMetrics(ctx, cfg, mux, singleAccountCollector, exporterMetrics, log); err != nil {
+			return err
+		}
+	}
 
 	// Register /health endpoint
 	mux.HandleFunc("/health", handleHealth)
 
+	// Register /ready endpoint, reporting whether a scrape has succeeded recently and the
+	// Tado API circuit breaker is closed
+	var circuitOpen func() bool
+	if accounts, ok := tadoCollector.(accountsCollector); ok {
+		circuitOpen = accounts.CircuitBreakerOpen
+	} else if exporterMetrics != nil {
+		circuitOpen = exporterMetrics.CircuitBreakerOpen
+	}
+	mux.Handle("/ready", handleReady(tadoCollector, cfg.ReadyMaxAge, circuitOpen))
+
+	// Register /scrape admin endpoint (only when an admin token is configured)
+	if cfg.AdminToken != "" {
+		scrapeHandler := handleScrape(registry, cfg.AdminToken)
+		if cfg.TLSClientCA != "" {
+			scrapeHandler = requireClientCert(scrapeHandler)
+		}
+		mux.Handle("/scrape", scrapeHandler)
+
+		valuesHandler := handleDebugValues(registry, cfg.AdminToken)
+		if cfg.TLSClientCA != "" {
+			valuesHandler = requireClientCert(valuesHandler)
+		}
+		mux.Handle("/debug/values", valuesHandler)
+	}
+
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%d", cfg.Port),
 		Handler:      mux,
 		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 10 * time.Second,
+		WriteTimeout: serverWriteTimeout(cfg),
 		IdleTimeout:  65 * time.Second,
 	}
 
+	// Track open connections so leaking scrapers show up in tado_exporter_http_connections_active
+	if exporterMetrics != nil {
+		server.ConnState = exporterMetrics.TrackConnState
+	}
+
+	if cfg.TLSClientCA != "" {
+		tlsConfig, err := newClientCATLSConfig(cfg.TLSClientCA)
+		if err != nil {
+			return err
+		}
+		server.TLSConfig = tlsConfig
+	}
+
+	scheme := "http"
+	if cfg.TLSCertFile != "" {
+		scheme = "https"
+	}
+
+	// Background goroutines are tracked so shutdown can wait for them to finish
+	tasks := &backgroundTasks{}
+
 	// Start server in background
 	serverErrors := make(chan error, 1)
-	go func() {
+	tasks.Go(func() {
 		log.Info("Starting HTTP server", "address", server.Addr, "port", cfg.Port)
-		log.Info("Metrics endpoint available", "url", fmt.Sprintf("http://localhost:%d/metrics", cfg.Port))
-		log.Info("Health endpoint available", "url", fmt.Sprintf("http://localhost:%d/health", cfg.Port))
-		serverErrors <- server.ListenAndServe()
-	}()
+		log.Info("Metrics endpoint available", "url", fmt.Sprintf("%s://localhost:%d/metrics", scheme, cfg.Port), "client_cert_required", cfg.TLSClientCA != "")
+		if cfg.SeparateExporterMetrics && exporterMetrics != nil {
+			log.Info("Exporter metrics endpoint available", "url", fmt.Sprintf("%s://localhost:%d/metrics/exporter", scheme, cfg.Port))
+		}
+		log.Info("Health endpoint available", "url", fmt.Sprintf("%s://localhost:%d/health", scheme, cfg.Port))
+		log.Info("Ready endpoint available", "url", fmt.Sprintf("%s://localhost:%d/ready", scheme, cfg.Port))
+		if cfg.AdminToken != "" {
+			log.Info("Scrape endpoint available", "url", fmt.Sprintf("%s://localhost:%d/scrape", scheme, cfg.Port))
+			log.Info("Metric values endpoint available", "url", fmt.Sprintf("%s://localhost:%d/debug/values", scheme, cfg.Port))
+		}
+		if cfg.TLSCertFile != "" {
+			serverErrors <- server.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+		} else {
+			serverErrors <- server.ListenAndServe()
+		}
+	})
+
+	// Push metrics over OTLP alongside serving them
+	if cfg.OTLPEndpoint != "" {
+		otlpExporter := metrics.NewOTLPExporter(cfg.OTLPEndpoint, otlpGatherers).WithResource(version, cfg.HomeID)
+		tasks.Go(func() {
+			log.Info("Pushing metrics over OTLP", "endpoint", cfg.OTLPEndpoint, "interval", cfg.OTLPInterval.String())
+			runOTLPExport(ctx, otlpExporter, tadoCollector.RefreshIfStale, cfg.OTLPInterval, log)
+		})
+	}
 
 	// Wait for context cancellation or server error
 	select {
@@ -73,23 +214,353 @@ func StartServer(
 
 	case <-ctx.Done():
 		log.Info("Shutting down HTTP server...")
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-
-		if err := server.Shutdown(shutdownCtx); err != nil {
-			return fmt.Errorf("HTTP server shutdown error: %w", err)
+		start := time.Now()
+		if err := shutdownServer(server, tasks, shutdownTimeout); err != nil {
+			log.Warn("HTTP server shutdown did not complete", "shutdown_duration_seconds", time.Since(start).Seconds(), "shutdown_timeout_seconds", shutdownTimeout.Seconds())
+			return err
 		}
 
-		log.Info("HTTP server stopped")
+		// Logged rather than exported as a metric, since nothing scrapes the registry after this
+		log.Info("HTTP server stopped", "shutdown_duration_seconds", time.Since(start).Seconds(), "shutdown_timeout_seconds", shutdownTimeout.Seconds())
 		return nil
 	}
 }
 
+// shutdownServer gracefully stops server and then waits for background tasks,
+// with both steps sharing a single timeout
+func shutdownServer(server *http.Server, tasks *backgroundTasks, timeout time.Duration) error {
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("HTTP server shutdown error: %w", err)
+	}
+
+	if err := tasks.Wait(shutdownCtx); err != nil {
+		return fmt.Errorf("HTTP server shutdown error: %w", err)
+	}
+	return nil
+}
+
+// backgroundTasks tracks goroutines running alongside the HTTP server (such as the
+// server loop itself) so that shutdown can wait for them to return
+type backgroundTasks struct {
+	wg sync.WaitGroup
+}
+
+// Go runs fn in a tracked goroutine; fn must return once the server's context is cancelled
+func (b *backgroundTasks) Go(fn func()) {
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		fn()
+	}()
+}
+
+// Wait blocks until all tracked goroutines have returned or ctx is done
+func (b *backgroundTasks) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("background tasks did not stop: %w", ctx.Err())
+	}
+}
+
+// newMetricsHandler returns a Prometheus handler for gatherer, requiring a client certificate when mTLS is configured
+// Requests are counted by scraper type when exporterMetrics is non-nil
+func newMetricsHandler(cfg *config.Config, gatherer prometheus.Gatherer, exporterMetrics *metrics.ExporterMetrics) http.Handler {
+	return protectMetricsHandler(cfg, exporterMetrics, newPromHandler(cfg, gatherer))
+}
+
+// registerGoMetrics registers the Go runtime and process collectors with registerer when enabled
+// They carry the constant labels like every other metric on /metrics
+func registerGoMetrics(cfg *config.Config, registerer prometheus.Registerer) error {
+	if !cfg.EnableGoMetrics {
+		return nil
+	}
+
+	registerer = prometheus.WrapRegistererWith(cfg.ConstantLabels, registerer)
+	if err := registerer.Register(collectors.NewGoCollector()); err != nil {
+		return fmt.Errorf("failed to register Go collector: %w", err)
+	}
+	if err := registerer.Register(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{})); err != nil {
+		return fmt.Errorf("failed to register process collector: %w", err)
+	}
+	return nil
+}
+
+// newPromHandler returns a Prometheus handler for gatherer using the configured scrape timeout
+func newPromHandler(cfg *config.Config, gatherer prometheus.Gatherer) http.Handler {
+	return promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{
+		EnableOpenMetrics: cfg.EnableOpenMetrics,
+		Timeout:           time.Duration(cfg.ScrapeTimeout) * time.Second,
+	})
+}
+
+// protectMetricsHandler wraps a metrics handler with request counting and, when mTLS is configured,
+// the client certificate requirement
+func protectMetricsHandler(cfg *config.Config, exporterMetrics *metrics.ExporterMetrics, handler http.Handler) http.Handler {
+	if exporterMetrics != nil {
+		handler = countScrapeRequests(exporterMetrics, handler)
+	}
+	if cfg.TLSClientCA != "" {
+		handler = requireClientCert(handler)
+	}
+	return handler
+}
+
+// registerPerHomeMetrics discovers the account's homes and serves each one from its own
+// registry at /metrics/<home_id>. Homes are discovered once at startup; exporter health
+// metrics remain on /metrics only.
+func registerPerHomeMetrics(ctx context.Context, cfg *config.Config, mux *http.ServeMux, tadoCollector *collector.TadoCollector, exporterMetrics *metrics.ExporterMetrics, log *logger.Logger) error {
+	discoveryCtx, cancel := context.WithTimeout(ctx, time.Duration(cfg.ScrapeTimeout)*time.Second)
+	defer cancel()
+
+	homeIDs, err := tadoCollector.HomeIDs(discoveryCtx)
+	if err != nil {
+		return fmt.Errorf("failed to discover homes for per-home metrics: %w", err)
+	}
+	if len(homeIDs) == 0 {
+		log.Warn("Per-home metrics enabled but no homes found")
+	}
+
+	for _, homeID := range homeIDs {
+		homeCollector, err := tadoCollector.ForHome(homeID)
+		if err != nil {
+			return err
+		}
+
+		homeRegistry := prometheus.NewRegistry()
+		if err := homeRegistry.Register(homeCollector); err != nil {
+			return fmt.Errorf("failed to register collector for home %s: %w", homeID, err)
+		}
+
+		mux.Handle("/metrics/"+homeID, newMetricsHandler(cfg, homeRegistry, exporterMetrics))
+		log.Info("Per-home metrics endpoint registered", "home_id", homeID, "path", "/metrics/"+homeID)
+	}
+	return nil
+}
+
+// overrideScrapeTimeout lets a request's X-Scrape-Timeout header replace the scrape timeout, capped at
+// cfg.MaxScrapeTimeout. Such requests are collected through a per-request registry, which also holds the
+// Go runtime metrics when enabled so the response matches /metrics without the header; requests without
+// the header, and all requests while no cap is configured, are passed to next
+func overrideScrapeTimeout(cfg *config.Config, tadoCollector metricsCollector, next http.Handler) http.Handler {
+	if cfg.MaxScrapeTimeout <= 0 {
+		return next
+	}
+	maxTimeout := time.Duration(cfg.MaxScrapeTimeout) * time.Second
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get(scrapeTimeoutHeader)
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		seconds, err := strconv.ParseFloat(header, 64)
+		if err != nil || seconds <= 0 {
+			writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid %s header %q: must be a positive number of seconds", scrapeTimeoutHeader, header))
+			return
+		}
+		timeout := time.Duration(seconds * float64(time.Second))
+		if timeout > maxTimeout {
+			timeout = maxTimeout
+		}
+
+		registry := prometheus.NewRegistry()
+		if err := tadoCollector.RegisterWithScrapeTimeout(registry, timeout); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, fmt.Sprintf("failed to register collector: %v", err))
+			return
+		}
+		if err := registerGoMetrics(cfg, registry); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		promhttp.HandlerFor(registry, promhttp.HandlerOpts{
+			EnableOpenMetrics: cfg.EnableOpenMetrics,
+			Timeout:           timeout,
+		}).ServeHTTP(w, r)
+	})
+}
+
+// serverWriteTimeout returns the HTTP server's write timeout: the longest scrape a /metrics request can
+// run, with the scrape timeout or an X-Scrape-Timeout override up to the maximum, plus writeTimeoutMargin
+func serverWriteTimeout(cfg *config.Config) time.Duration {
+	longestScrape := max(cfg.ScrapeTimeout, cfg.MaxScrapeTimeout)
+	return time.Duration(longestScrape)*time.Second + writeTimeoutMargin
+}
+
+// countScrapeRequests counts requests passed to next by the class of their User-Agent
+func countScrapeRequests(exporterMetrics *metrics.ExporterMetrics, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		exporterMetrics.RecordScrapeRequest(classifyUserAgent(r.UserAgent()))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// classifyUserAgent maps a User-Agent to a small, fixed set of scraper classes
+// so the request counter's label cardinality stays bounded
+func classifyUserAgent(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	switch {
+	case strings.HasPrefix(ua, "prometheus/"):
+		return "prometheus"
+	case strings.HasPrefix(ua, "grafanaagent/"), strings.HasPrefix(ua, "alloy/"):
+		return "grafana_agent"
+	case strings.HasPrefix(ua, "curl/"):
+		return "curl"
+	case strings.HasPrefix(ua, "mozilla/"):
+		return "browser"
+	default:
+		return "other"
+	}
+}
+
+// writeJSON writes v as a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeJSONError writes a {"error": msg} JSON response with the given status code
+// All handlers report errors through it so error responses are uniform
+func writeJSONError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
 // handleHealth handles the /health endpoint
 func handleHealth(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	_, _ = w.Write([]byte(`{"status":"ok"}`))
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleReady returns a handler for the /ready endpoint, which reports ready only when
+// a scrape has succeeded within maxAge (0 accepts any age)
+func handleReady(tadoCollector metricsCollector, maxAge time.Duration, circuitOpen func() bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The last scrape may have succeeded, but the data is known to be stale while Tado is failing
+		if circuitOpen != nil && circuitOpen() {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "not ready", "reason": "circuit breaker open"})
+			return
+		}
+
+		lastSuccess := tadoCollector.LastSuccessfulScrape()
+		if lastSuccess.IsZero() || (maxAge > 0 && time.Since(lastSuccess) > maxAge) {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "not ready"})
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+	})
+}
+
+// handleScrape returns a handler for POST /scrape which runs an immediate
+// out-of-band collection and responds with the rendered exposition text
+// A failed collection is reported as a JSON error instead of partial exposition text
+func handleScrape(gatherer prometheus.Gatherer, adminToken string) http.Handler {
+	var mu sync.Mutex
+
+	return requireAdminToken(adminToken, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		// Serialize debugging scrapes so repeated requests can't pile up collections
+		mu.Lock()
+		defer mu.Unlock()
+
+		families, err := gatherer.Gather()
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, fmt.Sprintf("scrape failed: %v", err))
+			return
+		}
+
+		// Render the already gathered families, so the collection runs only once
+		gathered := prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) { return families, nil })
+		promhttp.HandlerFor(gathered, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+	}))
+}
+
+// handleDebugValues returns a handler for GET /debug/values which gathers the metrics, collecting
+// from the Tado API like a scrape, and responds with each metric family's samples as JSON, keyed by
+// metric name, so values can be inspected without parsing the exposition format
+// Metrics that were gathered are returned even if gathering also reported an error
+func handleDebugValues(gatherer prometheus.Gatherer, adminToken string) http.Handler {
+	return requireAdminToken(adminToken, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		families, err := gatherer.Gather()
+		if err != nil && len(families) == 0 {
+			writeJSONError(w, http.StatusInternalServerError, fmt.Sprintf("gather failed: %v", err))
+			return
+		}
+
+		values := make(map[string][]*dto.Metric, len(families))
+		for _, family := range families {
+			values[family.GetName()] = family.GetMetric()
+		}
+		writeJSON(w, http.StatusOK, values)
+	}))
+}
+
+// newClientCATLSConfig builds a TLS config verifying client certificates against the CA bundle.
+// Certificates are verified whenever presented; requireClientCert then enforces them per
+// endpoint so that /health remains reachable without a client certificate.
+func newClientCATLSConfig(clientCAFile string) (*tls.Config, error) {
+	caPEM, err := os.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TLS client CA: %w", err)
+	}
+
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no valid certificates found in TLS client CA %s", clientCAFile)
+	}
+
+	return &tls.Config{
+		ClientAuth: tls.VerifyClientCertIfGiven,
+		ClientCAs:  clientCAs,
+		MinVersion: tls.VersionTLS12,
+	}, nil
+}
+
+// requireClientCert only passes requests that presented a verified client certificate to next
+func requireClientCert(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+			writeJSONError(w, http.StatusUnauthorized, "client certificate required")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireAdminToken only passes requests carrying the admin token as a bearer token to next
+func requireAdminToken(adminToken string, next http.Handler) http.Handler {
+	expected := []byte("Bearer " + adminToken)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeJSONError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
 }
 
 // SetupGracefulShutdown sets up signal handlers for graceful shutdown
@@ -3,23 +3,38 @@ package main
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"io"
+	"math/big"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"testing"
 	"time"
 
 	"github.com/andreweacott/tado-prometheus-exporter/pkg/collector"
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/collector/mocks"
 	"github.com/andreweacott/tado-prometheus-exporter/pkg/config"
 	"github.com/andreweacott/tado-prometheus-exporter/pkg/logger"
 	"github.com/andreweacott/tado-prometheus-exporter/pkg/metrics"
+	"github.com/clambin/tado/v2"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -220,6 +235,39 @@ func TestStartServerGracefulShutdown(t *testing.T) {
 	assert.Error(t, err)
 }
 
+// TestStartServerLogsShutdownDuration tests that graceful shutdown logs how long draining took
+func TestStartServerLogsShutdownDuration(t *testing.T) {
+	cfg := &config.Config{
+		Port:            findFreePort(),
+		ScrapeTimeout:   5,
+		TokenPassphrase: "test",
+		TokenPath:       "/tmp/test-token.json",
+	}
+
+	metricDescs, err := getTestMetrics()
+	require.NoError(t, err)
+
+	var logOutput bytes.Buffer
+	log, err := logger.NewWithWriter("info", "json", &logOutput)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- StartServer(ctx, cfg, collector.NewTadoCollector(nil, metricDescs, 5*time.Second, ""), metricDescs, log, nil)
+	}()
+
+	// Give server time to start
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	assert.Contains(t, logOutput.String(), `"msg":"HTTP server stopped"`)
+	assert.Contains(t, logOutput.String(), `"shutdown_duration_seconds"`)
+}
+
 // TestStartServerWithTimeout tests server startup with timeout
 func TestStartServerWithTimeout(t *testing.T) {
 	cfg := &config.Config{
@@ -405,6 +453,195 @@ func TestServerHeadersAndContent(t *testing.T) {
 	<-done
 }
 
+// TestHandleScrape tests the /scrape admin endpoint
+func TestHandleScrape(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "test_scrape_endpoint_gauge",
+		Help: "Test gauge for the scrape endpoint",
+	})
+	gauge.Set(42)
+	require.NoError(t, registry.Register(gauge))
+
+	handler := handleScrape(registry, "admin-secret")
+
+	tests := []struct {
+		name           string
+		method         string
+		authorization  string
+		expectedStatus int
+		expectMetrics  bool
+	}{
+		{
+			name:           "POST with valid token returns metrics",
+			method:         http.MethodPost,
+			authorization:  "Bearer admin-secret",
+			expectedStatus: http.StatusOK,
+			expectMetrics:  true,
+		},
+		{
+			name:           "POST with invalid token is rejected",
+			method:         http.MethodPost,
+			authorization:  "Bearer wrong",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "POST without token is rejected",
+			method:         http.MethodPost,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "GET with valid token is not allowed",
+			method:         http.MethodGet,
+			authorization:  "Bearer admin-secret",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/scrape", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			if tt.expectMetrics {
+				assert.Contains(t, recorder.Body.String(), "test_scrape_endpoint_gauge 42")
+			} else {
+				assert.NotContains(t, recorder.Body.String(), "test_scrape_endpoint_gauge")
+			}
+		})
+	}
+}
+
+// TestHandleScrapeConcurrent tests that concurrent out-of-band scrapes are served safely
+func TestHandleScrapeConcurrent(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "test_scrape_endpoint_concurrent_total",
+		Help: "Test counter for concurrent scrapes",
+	})))
+
+	handler := handleScrape(registry, "admin-secret")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodPost, "/scrape", nil)
+			req.Header.Set("Authorization", "Bearer admin-secret")
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), "test_scrape_endpoint_concurrent_total")
+		}()
+	}
+	wg.Wait()
+}
+
+// TestHandleDebugValues tests that /debug/values returns each metric's samples with their labels as JSON
+func TestHandleDebugValues(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "test_debug_values_celsius",
+		Help: "Test gauge for the metric values endpoint",
+	}, []string{"zone_id"})
+	gauge.WithLabelValues("3").Set(21.5)
+	require.NoError(t, registry.Register(gauge))
+
+	handler := handleDebugValues(registry, "admin-secret")
+
+	req := httptest.NewRequest(http.MethodGet, "/debug/values", nil)
+	req.Header.Set("Authorization", "Bearer admin-secret")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+	var values map[string][]struct {
+		Label []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"label"`
+		Gauge struct {
+			Value float64 `json:"value"`
+		} `json:"gauge"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &values))
+	samples := values["test_debug_values_celsius"]
+	require.Len(t, samples, 1)
+	require.Len(t, samples[0].Label, 1)
+	assert.Equal(t, "zone_id", samples[0].Label[0].Name)
+	assert.Equal(t, "3", samples[0].Label[0].Value)
+	assert.Equal(t, 21.5, samples[0].Gauge.Value)
+
+	// Without the admin token the values are not disclosed
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/values", nil))
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.NotContains(t, recorder.Body.String(), "test_debug_values_celsius")
+}
+
+// failingCollector reports a collection error on every scrape
+type failingCollector struct{}
+
+func (failingCollector) Describe(chan<- *prometheus.Desc) {}
+
+func (failingCollector) Collect(ch chan<- prometheus.Metric) {
+	desc := prometheus.NewDesc("test_failing_metric", "Test metric that fails to collect", nil, nil)
+	ch <- prometheus.NewInvalidMetric(desc, fmt.Errorf("tado unavailable"))
+}
+
+// TestHandleScrapeErrorsAreJSON tests that a failed out-of-band scrape and other errors are reported as JSON
+func TestHandleScrapeErrorsAreJSON(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(failingCollector{}))
+
+	handler := handleScrape(registry, "admin-secret")
+
+	tests := []struct {
+		name           string
+		method         string
+		expectedStatus int
+		expectedError  string
+	}{
+		{"failed collection", http.MethodPost, http.StatusInternalServerError, "tado unavailable"},
+		{"wrong method", http.MethodGet, http.StatusMethodNotAllowed, "method not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/scrape", nil)
+			req.Header.Set("Authorization", "Bearer admin-secret")
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+			var body map[string]string
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+			assert.Contains(t, body["error"], tt.expectedError)
+		})
+	}
+}
+
+// TestWriteJSONError tests that error responses are a JSON object with an error field
+func TestWriteJSONError(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	writeJSONError(recorder, http.StatusInternalServerError, "something broke")
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"error": "something broke"}`, recorder.Body.String())
+}
+
 // Helper functions
 
 // httpTestRecorder is a minimal implementation of http.ResponseWriter for testing
@@ -440,3 +677,707 @@ func findFreePort() int {
 	addr := listener.Addr().(*net.TCPAddr)
 	return addr.Port
 }
+
+// TestStartServerClientCertAuth tests that /metrics requires a client certificate signed by the configured CA
+func TestStartServerClientCertAuth(t *testing.T) {
+	dir := t.TempDir()
+
+	caCert, caKey, caFile, _ := writeTestCertificate(t, dir, "ca", &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}, nil, nil)
+	_, _, serverCertFile, serverKeyFile := writeTestCertificate(t, dir, "server", &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "localhost"},
+		DNSNames:    []string{"localhost"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, caCert, caKey)
+	_, _, validClientCertFile, validClientKeyFile := writeTestCertificate(t, dir, "client", &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "prometheus"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, caCert, caKey)
+
+	// A client certificate signed by an unrelated CA
+	otherCACert, otherCAKey, _, _ := writeTestCertificate(t, dir, "other-ca", &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "other-ca"},
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}, nil, nil)
+	_, _, invalidClientCertFile, invalidClientKeyFile := writeTestCertificate(t, dir, "invalid-client", &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "intruder"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, otherCACert, otherCAKey)
+
+	cfg := &config.Config{
+		Port:            findFreePort(),
+		ScrapeTimeout:   5,
+		TokenPassphrase: "test",
+		TokenPath:       "/tmp/test-token.json",
+		TLSCertFile:     serverCertFile,
+		TLSKeyFile:      serverKeyFile,
+		TLSClientCA:     caFile,
+	}
+
+	metricDescs, err := getTestMetrics()
+	require.NoError(t, err)
+
+	exporterMetrics, err := getTestExporterMetrics()
+	require.NoError(t, err)
+
+	mockAPI := (&mocks.MockTadoAPI{}).ExpectAllAPICalls()
+	mockCollector := collector.NewTadoCollector(
+		mockAPI,
+		metricDescs,
+		5*time.Second,
+		"",
+	).WithExporterMetrics(exporterMetrics)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- StartServer(ctx, cfg, mockCollector, metricDescs, getTestLogger(), exporterMetrics)
+	}()
+
+	// Give server time to start
+	time.Sleep(100 * time.Millisecond)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+	newClient := func(certFile, keyFile string) *http.Client {
+		tlsConfig := &tls.Config{RootCAs: roots}
+		if certFile != "" {
+			clientCert, err := tls.LoadX509KeyPair(certFile, keyFile)
+			require.NoError(t, err)
+			// Always present the certificate, even when its issuer isn't one the server asked for
+			tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+				return &clientCert, nil
+			}
+		}
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	}
+
+	metricsURL := fmt.Sprintf("https://localhost:%d/metrics", cfg.Port)
+	healthURL := fmt.Sprintf("https://localhost:%d/health", cfg.Port)
+
+	// Valid client certificate can scrape /metrics
+	resp, err := newClient(validClientCertFile, validClientKeyFile).Get(metricsURL)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Client certificate from an unknown CA is rejected during the handshake
+	_, err = newClient(invalidClientCertFile, invalidClientKeyFile).Get(metricsURL)
+	assert.Error(t, err)
+
+	// No client certificate: /metrics is refused, /health is still available
+	resp, err = newClient("", "").Get(metricsURL)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, err = newClient("", "").Get(healthURL)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+// writeTestCertificate creates a certificate from template signed by parent (self-signed when parent is nil)
+// and writes it and its key as PEM files into dir
+func writeTestCertificate(t *testing.T, dir, name string, template, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return cert, key, certFile, keyFile
+}
+
+// TestStartServerTracksConnections tests that open connections are reflected in the active connections gauge
+func TestStartServerTracksConnections(t *testing.T) {
+	cfg := &config.Config{
+		Port:            findFreePort(),
+		ScrapeTimeout:   5,
+		TokenPassphrase: "test",
+		TokenPath:       "/tmp/test-token.json",
+	}
+
+	metricDescs, err := getTestMetrics()
+	require.NoError(t, err)
+
+	// Use isolated exporter metrics so other tests' connections don't affect the gauge
+	registry := prometheus.NewRegistry()
+	exporterMetrics := metrics.NewExporterMetricsUnregistered()
+	require.NoError(t, exporterMetrics.RegisterWith(registry))
+
+	mockCollector := collector.NewTadoCollector(nil, metricDescs, 5*time.Second, "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- StartServer(ctx, cfg, mockCollector, metricDescs, getTestLogger(), exporterMetrics)
+	}()
+
+	// Give server time to start
+	time.Sleep(100 * time.Millisecond)
+
+	activeConnections := func() float64 {
+		families, err := registry.Gather()
+		require.NoError(t, err)
+		for _, family := range families {
+			if family.GetName() == "tado_exporter_http_connections_active" {
+				return family.Metric[0].GetGauge().GetValue()
+			}
+		}
+		return -1
+	}
+
+	address := fmt.Sprintf("localhost:%d", cfg.Port)
+	first, err := net.Dial("tcp", address)
+	require.NoError(t, err)
+	second, err := net.Dial("tcp", address)
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return activeConnections() == 2 }, time.Second, 10*time.Millisecond)
+
+	_ = first.Close()
+	_ = second.Close()
+
+	assert.Eventually(t, func() bool { return activeConnections() == 0 }, time.Second, 10*time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+// TestStartServerPerHomeMetrics tests that /metrics/<home_id> serves only that home's series
+func TestStartServerPerHomeMetrics(t *testing.T) {
+	cfg := &config.Config{
+		Port:            findFreePort(),
+		ScrapeTimeout:   5,
+		TokenPassphrase: "test",
+		TokenPath:       "/tmp/test-token.json",
+		PerHomeMetrics:  true,
+	}
+
+	metricDescs, err := getTestMetrics()
+	require.NoError(t, err)
+
+	exporterMetrics, err := getTestExporterMetrics()
+	require.NoError(t, err)
+
+	zoneID := 1
+	temperature := float32(20.5)
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]tado.HomeId{123, 456})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{Id: &zoneID}}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{
+		"1": {SensorDataPoints: &tado.SensorDataPoints{InsideTemperature: &tado.TemperatureDataPoint{Celsius: &temperature}}},
+	}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	mockCollector := collector.NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "").WithExporterMetrics(exporterMetrics)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- StartServer(ctx, cfg, mockCollector, metricDescs, getTestLogger(), exporterMetrics)
+	}()
+
+	// Give server time to start
+	time.Sleep(100 * time.Millisecond)
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get(fmt.Sprintf("http://localhost:%d%s", cfg.Port, path))
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	status, body := get("/metrics/123")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `tado_temperature_measured_celsius{home_id="123"`)
+	assert.NotContains(t, body, `home_id="456"`)
+	assert.NotContains(t, body, "tado_exporter_", "exporter health metrics are only served on /metrics")
+
+	status, body = get("/metrics/456")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `home_id="456"`)
+	assert.NotContains(t, body, `home_id="123"`)
+
+	status, _ = get("/metrics/789")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+// TestStartServerSeparateExporterMetrics tests that exporter health metrics move from /metrics to /metrics/exporter
+func TestStartServerSeparateExporterMetrics(t *testing.T) {
+	cfg := &config.Config{
+		Port:                    findFreePort(),
+		ScrapeTimeout:           5,
+		TokenPassphrase:         "test",
+		TokenPath:               "/tmp/test-token.json",
+		SeparateExporterMetrics: true,
+	}
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	exporterMetrics := metrics.NewExporterMetricsUnregistered()
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]tado.HomeId{123})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	mockCollector := collector.NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "").WithSeparateExporterMetrics(exporterMetrics)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- StartServer(ctx, cfg, mockCollector, metricDescs, getTestLogger(), exporterMetrics)
+	}()
+
+	// Give server time to start
+	time.Sleep(100 * time.Millisecond)
+
+	get := func(path string) string {
+		resp, err := http.Get(fmt.Sprintf("http://localhost:%d%s", cfg.Port, path))
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	body := get("/metrics")
+	assert.Contains(t, body, "tado_is_resident_present")
+	assert.NotContains(t, body, "tado_exporter_")
+
+	body = get("/metrics/exporter")
+	assert.Contains(t, body, "tado_exporter_scrape_duration_seconds")
+	for _, line := range strings.Split(strings.TrimSpace(body), "\n") {
+		name := strings.TrimPrefix(strings.TrimPrefix(line, "# HELP "), "# TYPE ")
+		assert.True(t, strings.HasPrefix(name, "tado_exporter_"), "unexpected series on /metrics/exporter: %s", line)
+	}
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+// TestStartServerOTLPWithoutScrapes tests that OTLP pushes carry Tado metrics even when /metrics is never scraped
+func TestStartServerOTLPWithoutScrapes(t *testing.T) {
+	var mu sync.Mutex
+	var pushed []string
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		mu.Lock()
+		pushed = append(pushed, string(body))
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	cfg := &config.Config{
+		Port:            findFreePort(),
+		ScrapeTimeout:   5,
+		TokenPassphrase: "test",
+		TokenPath:       "/tmp/test-token.json",
+		OTLPEndpoint:    receiver.URL,
+		OTLPInterval:    50 * time.Millisecond,
+	}
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	exporterMetrics := metrics.NewExporterMetricsUnregistered()
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]tado.HomeId{123})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{{}, {}}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	mockCollector := collector.NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "").WithExporterMetrics(exporterMetrics)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- StartServer(ctx, cfg, mockCollector, metricDescs, getTestLogger(), exporterMetrics)
+	}()
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(pushed) > 0 && strings.Contains(pushed[len(pushed)-1], "tado_home_devices_total")
+	}, 2*time.Second, 10*time.Millisecond, "pushes fetch from Tado themselves while /metrics isn't scraped")
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+// TestShutdownServer_StopsBackgroundTasks tests that shutdown waits for a running background goroutine to stop
+func TestShutdownServer_StopsBackgroundTasks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var stopped atomic.Bool
+	tasks := &backgroundTasks{}
+	tasks.Go(func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				// Simulate finishing in-flight work
+				time.Sleep(50 * time.Millisecond)
+				stopped.Store(true)
+				return
+			}
+		}
+	})
+
+	cancel()
+
+	start := time.Now()
+	err := shutdownServer(&http.Server{}, tasks, time.Second)
+
+	assert.NoError(t, err)
+	assert.True(t, stopped.Load(), "shutdown should wait for the background goroutine")
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+// TestShutdownServer_TimesOut tests that shutdown returns within the timeout when a background goroutine doesn't stop
+func TestShutdownServer_TimesOut(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	tasks := &backgroundTasks{}
+	tasks.Go(func() { <-release })
+
+	start := time.Now()
+	err := shutdownServer(&http.Server{}, tasks, 100*time.Millisecond)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "background tasks did not stop")
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+// TestNewMetricsHandler_OpenMetricsToggle tests that the exposition format respects the OpenMetrics setting
+func TestNewMetricsHandler_OpenMetricsToggle(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_gauge", Help: "Test gauge"})
+	require.NoError(t, registry.Register(gauge))
+
+	// Accept headers as sent by a modern Prometheus and by an older, text-only scraper
+	const acceptOpenMetrics = "application/openmetrics-text;version=1.0.0,text/plain;version=0.0.4;q=0.5,*/*;q=0.1"
+	const acceptTextPlain = "text/plain;version=0.0.4;q=1.0,application/openmetrics-text;version=1.0.0;q=0.5"
+
+	tests := []struct {
+		name                string
+		enableOpenMetrics   bool
+		accept              string
+		expectedContentType string
+	}{
+		{"enabled, scraper prefers OpenMetrics", true, acceptOpenMetrics, "application/openmetrics-text"},
+		{"enabled, scraper prefers text", true, acceptTextPlain, "text/plain"},
+		{"disabled, scraper prefers OpenMetrics", false, acceptOpenMetrics, "text/plain"},
+		{"disabled, scraper prefers text", false, acceptTextPlain, "text/plain"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{ScrapeTimeout: 5, EnableOpenMetrics: tt.enableOpenMetrics}
+
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			req.Header.Set("Accept", tt.accept)
+			rec := httptest.NewRecorder()
+
+			newMetricsHandler(cfg, registry, nil).ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.True(t, strings.HasPrefix(rec.Header().Get("Content-Type"), tt.expectedContentType),
+				"unexpected content type %q", rec.Header().Get("Content-Type"))
+			assert.Contains(t, rec.Body.String(), "test_gauge")
+		})
+	}
+}
+
+// TestNewMetricsHandler_CountsScrapeRequests tests that metrics requests are counted by User-Agent class
+func TestNewMetricsHandler_CountsScrapeRequests(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	exporterMetrics := metrics.NewExporterMetricsUnregistered()
+	require.NoError(t, exporterMetrics.RegisterWith(registry))
+
+	handler := newMetricsHandler(&config.Config{ScrapeTimeout: 5}, registry, exporterMetrics)
+
+	for _, userAgent := range []string{"Prometheus/2.53.0", "Prometheus/3.0.1", "curl/8.5.0"} {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.Header.Set("User-Agent", userAgent)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	counts := map[string]float64{}
+	for _, family := range families {
+		if family.GetName() != "tado_exporter_scrape_requests_total" {
+			continue
+		}
+		for _, m := range family.Metric {
+			counts[m.Label[0].GetValue()] = m.GetCounter().GetValue()
+		}
+	}
+
+	assert.Equal(t, map[string]float64{"prometheus": 2, "curl": 1}, counts)
+}
+
+// TestClassifyUserAgent tests mapping of User-Agent headers to scraper classes
+func TestClassifyUserAgent(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		expected  string
+	}{
+		{"Prometheus/2.53.0", "prometheus"},
+		{"GrafanaAgent/v0.40.0 (static; linux; binary)", "grafana_agent"},
+		{"Alloy/v1.4.0 (linux; binary)", "grafana_agent"},
+		{"curl/8.5.0", "curl"},
+		{"Mozilla/5.0 (X11; Linux x86_64) Firefox/128.0", "browser"},
+		{"Go-http-client/1.1", "other"},
+		{"", "other"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.userAgent, func(t *testing.T) {
+			assert.Equal(t, tt.expected, classifyUserAgent(tt.userAgent))
+		})
+	}
+}
+
+// TestOverrideScrapeTimeout tests that X-Scrape-Timeout replaces the scrape timeout of a /metrics request,
+// capped at the configured maximum
+func TestOverrideScrapeTimeout(t *testing.T) {
+	tests := []struct {
+		name             string
+		maxScrapeTimeout int
+		header           string
+		expectedStatus   int
+		expectedTimeout  time.Duration
+	}{
+		{"no header uses the scrape timeout", 10, "", http.StatusOK, 2 * time.Second},
+		{"header below the cap is honored", 10, "5", http.StatusOK, 5 * time.Second},
+		{"header above the cap is capped", 10, "30", http.StatusOK, 10 * time.Second},
+		{"header ignored without a cap", 0, "30", http.StatusOK, 2 * time.Second},
+		{"header above 10s is honored", 30, "25", http.StatusOK, 25 * time.Second},
+		{"invalid header is rejected", 10, "soon", http.StatusBadRequest, 0},
+		{"negative header is rejected", 10, "-1", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Record how long the scrape's context allowed, without actually waiting for it
+			var remaining time.Duration
+			mockAPI := &mocks.MockTadoAPI{}
+			mockAPI.On("GetMe", mock.Anything).Run(func(args mock.Arguments) {
+				deadline, ok := args.Get(0).(context.Context).Deadline()
+				require.True(t, ok)
+				remaining = time.Until(deadline)
+			}).Return(nil, fmt.Errorf("unavailable"))
+
+			metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+			require.NoError(t, err)
+			tadoCollector := collector.NewTadoCollectorWithLogger(mockAPI, metricDescs, 2*time.Second, "", getTestLogger())
+
+			registry := prometheus.NewRegistry()
+			require.NoError(t, tadoCollector.RegisterWith(registry))
+
+			cfg := &config.Config{ScrapeTimeout: 2, MaxScrapeTimeout: tt.maxScrapeTimeout}
+			handler := overrideScrapeTimeout(cfg, tadoCollector, newPromHandler(cfg, registry))
+
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.header != "" {
+				req.Header.Set(scrapeTimeoutHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus != http.StatusOK {
+				mockAPI.AssertNotCalled(t, "GetMe", mock.Anything)
+				assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+				return
+			}
+			assert.LessOrEqual(t, remaining, tt.expectedTimeout)
+			assert.Greater(t, remaining, tt.expectedTimeout-time.Second)
+
+			// The server must not cut the response off before the scrape may finish
+			assert.Greater(t, serverWriteTimeout(cfg), tt.expectedTimeout)
+		})
+	}
+}
+
+// TestServerWriteTimeout tests that the write timeout outlasts the longest scrape a request can ask for
+func TestServerWriteTimeout(t *testing.T) {
+	tests := []struct {
+		name             string
+		scrapeTimeout    int
+		maxScrapeTimeout int
+		expected         time.Duration
+	}{
+		{"scrape timeout without overrides", 10, 0, 15 * time.Second},
+		{"override cap above 10s", 10, 60, 65 * time.Second},
+		{"scrape timeout above 10s", 20, 0, 25 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{ScrapeTimeout: tt.scrapeTimeout, MaxScrapeTimeout: tt.maxScrapeTimeout}
+			assert.Equal(t, tt.expected, serverWriteTimeout(cfg))
+		})
+	}
+}
+
+// TestRegisterGoMetrics tests that the Go runtime metrics are only exposed when enabled
+func TestRegisterGoMetrics(t *testing.T) {
+	hasGoroutines := func(cfg *config.Config) bool {
+		registry := prometheus.NewRegistry()
+		require.NoError(t, registerGoMetrics(cfg, registry))
+
+		families, err := registry.Gather()
+		require.NoError(t, err)
+		for _, family := range families {
+			if family.GetName() == "go_goroutines" {
+				return true
+			}
+		}
+		return false
+	}
+
+	assert.False(t, hasGoroutines(&config.Config{}))
+	assert.True(t, hasGoroutines(&config.Config{EnableGoMetrics: true}))
+}
+
+// TestOverrideScrapeTimeout_KeepsGoMetrics tests that /metrics exposes the Go runtime metrics whether or not
+// the request overrides the scrape timeout
+func TestOverrideScrapeTimeout_KeepsGoMetrics(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{"without the header", ""},
+		{"with the header", "5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := &mocks.MockTadoAPI{}
+			mockAPI.ExpectGetMeReturnsError(fmt.Errorf("unavailable"))
+
+			metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+			require.NoError(t, err)
+			tadoCollector := collector.NewTadoCollectorWithLogger(mockAPI, metricDescs, 2*time.Second, "", getTestLogger())
+
+			cfg := &config.Config{ScrapeTimeout: 2, MaxScrapeTimeout: 10, EnableGoMetrics: true}
+			registry := prometheus.NewRegistry()
+			require.NoError(t, tadoCollector.RegisterWith(registry))
+			require.NoError(t, registerGoMetrics(cfg, registry))
+			handler := overrideScrapeTimeout(cfg, tadoCollector, newPromHandler(cfg, registry))
+
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.header != "" {
+				req.Header.Set(scrapeTimeoutHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			require.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Body.String(), "go_goroutines")
+		})
+	}
+}
+
+// TestRecordFeatures tests that enabled features report 1 and every other feature 0
+func TestRecordFeatures(t *testing.T) {
+	cfg := &config.Config{TLSCertFile: "/certs/tls.crt", TLSKeyFile: "/certs/tls.key", SnapshotPath: "/data/snapshot.json"}
+	exporterMetrics := metrics.NewExporterMetricsUnregistered()
+	registry := prometheus.NewRegistry()
+	require.NoError(t, exporterMetrics.RegisterWith(registry))
+
+	recordFeatures(cfg, exporterMetrics)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	features := map[string]float64{}
+	for _, family := range families {
+		if family.GetName() != "tado_exporter_feature_enabled" {
+			continue
+		}
+		for _, m := range family.Metric {
+			for _, pair := range m.Label {
+				if pair.GetName() == "feature" {
+					features[pair.GetValue()] = m.GetGauge().GetValue()
+				}
+			}
+		}
+	}
+
+	require.Len(t, features, len(cfg.Features()))
+	for feature, value := range features {
+		switch feature {
+		case "tls", "snapshot":
+			assert.Equal(t, 1.0, value, feature)
+		default:
+			assert.Equal(t, 0.0, value, feature)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/auth"
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/collector"
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/config"
+)
+
+// validateAPITimeout bounds the single GetMe call made by -validate and, per account, by -list-homes
+const validateAPITimeout = 30 * time.Second
+
+// clientLoader creates a Tado API client from a stored token without starting the device flow
+type clientLoader func(ctx context.Context, tokenPath, tokenPassphrase string) (collector.TadoAPI, error)
+
+// loadStoredClient is the clientLoader used by -validate, reading the token from disk
+func loadStoredClient(cfg *config.Config) clientLoader {
+	return func(ctx context.Context, tokenPath, tokenPassphrase string) (collector.TadoAPI, error) {
+		client, err := auth.LoadTadoClient(ctx, tokenPath, tokenPassphrase, cfg.ServerURL)
+		if err != nil {
+			return nil, err
+		}
+		return collector.NewTadoClientAdapter(client), nil
+	}
+}
+
+// runValidation checks the configuration, then for each account loads its token and calls GetMe once,
+// writing a pass/fail checklist to out. It returns the process exit code: 0 if every check passed, 1 otherwise
+// Checks that depend on a failed one are reported as skipped
+func runValidation(ctx context.Context, cfg *config.Config, out io.Writer, load clientLoader) int {
+	failed := false
+	check := func(name string, err error) bool {
+		if err != nil {
+			failed = true
+			_, _ = fmt.Fprintf(out, "[FAIL] %s: %v\n", name, err)
+			return false
+		}
+		_, _ = fmt.Fprintf(out, "[PASS] %s\n", name)
+		return true
+	}
+	skip := func(name string) {
+		_, _ = fmt.Fprintf(out, "[SKIP] %s\n", name)
+	}
+
+	if !check("configuration", cfg.Validate()) {
+		skip("token")
+		skip("tado api")
+		return 1
+	}
+
+	accounts := cfg.Accounts
+	if len(accounts) == 0 {
+		accounts = []config.Account{{TokenPath: cfg.TokenPath, TokenPassphrase: cfg.TokenPassphrase}}
+	}
+	for _, account := range accounts {
+		suffix := ""
+		if account.Name != "" {
+			suffix = fmt.Sprintf(" (account %s)", account.Name)
+		}
+
+		api, err := load(ctx, account.TokenPath, account.TokenPassphrase)
+		if !check("token"+suffix, err) {
+			skip("tado api" + suffix)
+			continue
+		}
+
+		check("tado api"+suffix, checkGetMe(ctx, api))
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// checkGetMe calls GetMe once, failing if the call fails or its response has no homes field
+func checkGetMe(ctx context.Context, api collector.TadoAPI) error {
+	ctx, cancel := context.WithTimeout(ctx, validateAPITimeout)
+	defer cancel()
+
+	user, err := api.GetMe(ctx)
+	if err != nil {
+		return err
+	}
+	if user == nil || user.Homes == nil {
+		return errors.New("response has no homes field")
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/auth"
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/collector"
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/collector/mocks"
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/config"
+	"github.com/clambin/tado/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// validConfig returns a configuration passing Validate
+func validConfig() *config.Config {
+	return &config.Config{
+		TokenPath:       "/tmp/token.json",
+		TokenPassphrase: "secret",
+		Port:            9100,
+		ScrapeTimeout:   10,
+		LogLevel:        "info",
+		MetricCompat:    "v1",
+	}
+}
+
+// TestRunValidation tests that the checklist reports each check's outcome and the exit code reflects failures
+func TestRunValidation(t *testing.T) {
+	mockLoader := func(api collector.TadoAPI, err error) clientLoader {
+		return func(context.Context, string, string) (collector.TadoAPI, error) {
+			return api, err
+		}
+	}
+
+	t.Run("all checks pass", func(t *testing.T) {
+		mockAPI := &mocks.MockTadoAPI{}
+		mockAPI.ExpectGetMeReturnsHomes([]tado.HomeId{1})
+
+		var out bytes.Buffer
+		code := runValidation(context.Background(), validConfig(), &out, mockLoader(mockAPI, nil))
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "[PASS] configuration\n[PASS] token\n[PASS] tado api\n", out.String())
+	})
+
+	t.Run("api failure", func(t *testing.T) {
+		mockAPI := &mocks.MockTadoAPI{}
+		mockAPI.On("GetMe", mock.Anything).Return(nil, errors.New("unauthorized"))
+
+		var out bytes.Buffer
+		code := runValidation(context.Background(), validConfig(), &out, mockLoader(mockAPI, nil))
+		assert.Equal(t, 1, code)
+		assert.Equal(t, "[PASS] configuration\n[PASS] token\n[FAIL] tado api: unauthorized\n", out.String())
+	})
+
+	t.Run("missing token skips the api check", func(t *testing.T) {
+		var out bytes.Buffer
+		code := runValidation(context.Background(), validConfig(), &out, mockLoader(nil, auth.ErrTokenMissing))
+		assert.Equal(t, 1, code)
+		assert.Contains(t, out.String(), "[FAIL] token: no usable token stored")
+		assert.Contains(t, out.String(), "[SKIP] tado api\n")
+	})
+
+	t.Run("invalid configuration skips everything", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.TokenPassphrase = ""
+
+		var out bytes.Buffer
+		code := runValidation(context.Background(), cfg, &out, mockLoader(nil, errors.New("must not be called")))
+		assert.Equal(t, 1, code)
+		assert.Contains(t, out.String(), "[FAIL] configuration: token-passphrase is required")
+		assert.Contains(t, out.String(), "[SKIP] token\n[SKIP] tado api\n")
+	})
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+)
+
+// Build details, set at build time with
+// -ldflags "-X main.version=... -X main.commit=... -X main.buildDate=..."
+var (
+	version   = "dev"
+	commit    = "unknown"
+	buildDate = "unknown"
+)
+
+// printVersion writes the build details of the binary to w
+func printVersion(w io.Writer) {
+	_, _ = fmt.Fprintf(w, "tado-prometheus-exporter %s (commit %s, built %s, %s)\n", version, commit, buildDate, runtime.Version())
+}
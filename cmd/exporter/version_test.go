@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPrintVersion tests that the build details are printed
+func TestPrintVersion(t *testing.T) {
+	var out bytes.Buffer
+	printVersion(&out)
+
+	assert.Contains(t, out.String(), "tado-prometheus-exporter dev")
+	assert.Contains(t, out.String(), "commit unknown")
+}
+
+// TestMainVersionFlag runs main with -version in a subprocess, asserting that it prints the version
+// and exits 0 without needing a passphrase or starting the server
+func TestMainVersionFlag(t *testing.T) {
+	if os.Getenv("TADO_EXPORTER_TEST_RUN_MAIN") == "1" {
+		os.Args = []string{"tado-exporter", "-version"}
+		main()
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, os.Args[0], "-test.run=^TestMainVersionFlag$")
+	cmd.Env = append(os.Environ(), "TADO_EXPORTER_TEST_RUN_MAIN=1", "TADO_TOKEN_PASSPHRASE=")
+	output, err := cmd.Output()
+	require.NoError(t, err, "main should exit 0 instead of starting the server")
+
+	assert.Contains(t, string(output), "tado-prometheus-exporter dev")
+	assert.NotContains(t, string(output), "PASS", "main should exit before the test framework reports")
+}
@@ -12,36 +12,73 @@ package auth
 
 import (
 	"context"
+	"crypto/sha256"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"net/http"
+	"os"
+	"sync"
+	"time"
 
 	"github.com/clambin/tado/v2"
+	"github.com/clambin/tado/v2/oauth2store"
 	"golang.org/x/oauth2"
 )
 
+// ErrTokenFileUnreadable is returned when the token file exists but cannot be decrypted or parsed,
+// usually because the file is corrupted or the passphrase has changed
+var ErrTokenFileUnreadable = errors.New("token file unreadable: delete it and re-authenticate, or fix the passphrase")
+
+// ErrDeviceFlowTimeout is returned when the device code authentication is not completed within the configured timeout
+var ErrDeviceFlowTimeout = errors.New("device authentication was not completed in time: restart and visit the verification URL, or raise the device flow timeout")
+
+// ErrTokenMissing is returned by LoadTadoClient when there is no usable stored token, so only the
+// device code authentication of a normal start could create one
+var ErrTokenMissing = errors.New("no usable token stored: start the exporter normally to authenticate")
+
+// maxTokenFileAge matches clambin/tado, which re-authenticates once the refresh token (valid for 30 days) is too old
+const maxTokenFileAge = 30 * 24 * time.Hour
+
 // CreateTadoClient creates a Tado API client with encrypted token storage
 // On first run, it will perform OAuth device code authentication
 // The user will be prompted to visit a verification URL
 // The token is persisted to tokenPath with encryption using tokenPassphrase
-func CreateTadoClient(ctx context.Context, tokenPath, tokenPassphrase string) (*http.Client, error) {
+// authURLFile, if set, additionally receives the verification URL so non-interactive deployments can retrieve it
+// deviceFlowTimeout bounds how long the device code authentication waits for the user; 0 waits indefinitely
+// tokenTracker, if non-nil, observes every token handed out by the client
+func CreateTadoClient(ctx context.Context, tokenPath, tokenPassphrase, authURLFile string, deviceFlowTimeout time.Duration, tokenTracker *TokenTracker) (*http.Client, error) {
+	// clambin/tado treats an unreadable token file like a missing one and starts the device flow,
+	// which hides a wrong passphrase behind an unexpected re-authentication prompt
+	if err := checkTokenFile(tokenPath, tokenPassphrase); err != nil {
+		return nil, err
+	}
+
 	// NewOAuth2Client handles:
 	// - Loading existing token from tokenPath if valid
 	// - Performing device code OAuth flow if no valid token
 	// - Storing encrypted token to tokenPath with tokenPassphrase via TokenSource when Token() is called
 	// - Automatically refreshing token when needed
-	client, err := tado.NewOAuth2Client(
-		ctx,
-		tokenPath,
-		tokenPassphrase,
-		func(response *oauth2.DeviceAuthResponse) {
-			fmt.Printf("\nNo token found. Visit this link to authenticate:\n")
-			fmt.Printf("%s\n\n", response.VerificationURIComplete)
-		},
-	)
+	client, err := newOAuth2ClientWithTimeout(ctx, deviceFlowTimeout, func(ctx context.Context) (*http.Client, error) {
+		return tado.NewOAuth2Client(
+			ctx,
+			tokenPath,
+			tokenPassphrase,
+			deviceAuthCallback(os.Stdout, authURLFile),
+		)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create OAuth2 client: %w", err)
 	}
 
+	// Track tokens before the first Token() call so the initial token becomes the refresh baseline
+	if tokenTracker != nil {
+		if err := trackTokens(client, tokenTracker); err != nil {
+			return nil, fmt.Errorf("failed to track tokens: %w", err)
+		}
+	}
+
 	// Persist the token to disk immediately after authentication
 	// This ensures newly acquired tokens are saved before the application makes API calls
 	err = persistToken(client)
@@ -52,6 +89,57 @@ func CreateTadoClient(ctx context.Context, tokenPath, tokenPassphrase string) (*
 	return client, nil
 }
 
+// newOAuth2ClientWithTimeout calls create, cancelling its context if it has not returned within timeout (0 disables)
+// The context also drives the returned client's token refreshes, so it is only cancelled while create is still
+// waiting for the device code authentication; a client created in time keeps a live context
+func newOAuth2ClientWithTimeout(ctx context.Context, timeout time.Duration, create func(ctx context.Context) (*http.Client, error)) (*http.Client, error) {
+	if timeout <= 0 {
+		return create(ctx)
+	}
+
+	createCtx, cancel := context.WithCancel(ctx)
+	timer := time.AfterFunc(timeout, cancel)
+
+	client, err := create(createCtx)
+	if !timer.Stop() {
+		// The timer fired, so the client's context is cancelled even if create returned successfully
+		return nil, fmt.Errorf("%w (timeout %s)", ErrDeviceFlowTimeout, timeout)
+	}
+	return client, err
+}
+
+// checkTokenFile returns ErrTokenFileUnreadable if the token file exists but cannot be decrypted with
+// tokenPassphrase or parsed. A missing file is fine: it is created by the device flow
+// The file's age is not checked here; clambin/tado re-authenticates when the token is too old
+func checkTokenFile(tokenPath, tokenPassphrase string) error {
+	if _, err := os.Stat(tokenPath); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	store := oauth2store.NewEncryptedFileTokenStore(tokenPath, tokenPassphrase, time.Duration(math.MaxInt64))
+	if _, err := store.Load(); err != nil {
+		return fmt.Errorf("%w (%s: %v)", ErrTokenFileUnreadable, tokenPath, err)
+	}
+	return nil
+}
+
+// deviceAuthCallback returns the device-flow callback that shows the verification URL to the user
+// The URL is always printed to out; when authURLFile is set it is also written to that file
+func deviceAuthCallback(out io.Writer, authURLFile string) func(*oauth2.DeviceAuthResponse) {
+	return func(response *oauth2.DeviceAuthResponse) {
+		_, _ = fmt.Fprintf(out, "\nNo token found. Visit this link to authenticate:\n")
+		_, _ = fmt.Fprintf(out, "%s\n\n", response.VerificationURIComplete)
+
+		if authURLFile == "" {
+			return
+		}
+		// The URL embeds the device user code, so keep the file private to the exporter user
+		if err := os.WriteFile(authURLFile, []byte(response.VerificationURIComplete+"\n"), 0600); err != nil {
+			_, _ = fmt.Fprintf(out, "Failed to write verification URL to %s: %v\n", authURLFile, err)
+		}
+	}
+}
+
 // persistToken forces the token to be saved by calling Token() on the client's token source
 // This ensures newly acquired tokens are persisted to disk immediately after authentication,
 // rather than waiting for the first API call which may never happen in some scenarios
@@ -68,17 +156,123 @@ func persistToken(client *http.Client) error {
 	return err
 }
 
+// TokenTracker observes the tokens handed out by the client's token source, recording
+// access token refreshes and the current token's expiry
+// Only a hash of the last access token is kept so the token itself is not held in memory twice
+type TokenTracker struct {
+	onRefresh func()
+
+	mu       sync.Mutex
+	lastHash [sha256.Size]byte
+	seen     bool
+	expiry   time.Time
+}
+
+// NewTokenTracker creates a TokenTracker; onRefresh, if non-nil, is called each time a new access token is observed
+func NewTokenTracker(onRefresh func()) *TokenTracker {
+	return &TokenTracker{onRefresh: onRefresh}
+}
+
+// Expiry returns the expiry of the most recently observed token
+// ok is false if no token has been observed yet or the token does not expire
+func (t *TokenTracker) Expiry() (expiry time.Time, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.expiry, !t.expiry.IsZero()
+}
+
+// observe records token, calling onRefresh if its access token differs from the previous one
+// The first token observed is treated as the baseline, not a refresh
+func (t *TokenTracker) observe(token *oauth2.Token) {
+	hash := sha256.Sum256([]byte(token.AccessToken))
+
+	t.mu.Lock()
+	refreshed := t.seen && hash != t.lastHash
+	t.lastHash = hash
+	t.seen = true
+	t.expiry = token.Expiry
+	t.mu.Unlock()
+
+	if refreshed && t.onRefresh != nil {
+		t.onRefresh()
+	}
+}
+
+// trackTokens wraps the client's token source so every token it returns is observed by tracker
+func trackTokens(client *http.Client, tracker *TokenTracker) error {
+	transport, ok := client.Transport.(*oauth2.Transport)
+	if !ok {
+		return fmt.Errorf("invalid transport type: expected *oauth2.Transport")
+	}
+
+	transport.Source = &trackingTokenSource{
+		source:  transport.Source,
+		tracker: tracker,
+	}
+	return nil
+}
+
+// trackingTokenSource is an oauth2.TokenSource reporting every token it returns to a TokenTracker
+type trackingTokenSource struct {
+	source  oauth2.TokenSource
+	tracker *TokenTracker
+}
+
+// Token returns the underlying token after reporting it to the tracker
+func (s *trackingTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.source.Token()
+	if err != nil || token == nil {
+		return token, err
+	}
+
+	s.tracker.observe(token)
+	return token, nil
+}
+
 // CreateTadoClientWithHTTPClient creates a Tado API client using clambin/tado library
 // This is the primary entry point for creating an authenticated Tado client
-func NewAuthenticatedTadoClient(ctx context.Context, tokenPath, tokenPassphrase string) (*tado.ClientWithResponses, error) {
-	httpClient, err := CreateTadoClient(ctx, tokenPath, tokenPassphrase)
+// serverURL is the Tado API base URL; empty uses tado.ServerURL
+// authURLFile, if set, additionally receives the device-flow verification URL
+// deviceFlowTimeout bounds how long the device-flow waits for the user; 0 waits indefinitely
+// tokenTracker, if non-nil, observes every token handed out by the client
+func NewAuthenticatedTadoClient(ctx context.Context, tokenPath, tokenPassphrase, serverURL, authURLFile string, deviceFlowTimeout time.Duration, tokenTracker *TokenTracker) (*tado.ClientWithResponses, error) {
+	httpClient, err := CreateTadoClient(ctx, tokenPath, tokenPassphrase, authURLFile, deviceFlowTimeout, tokenTracker)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create the Tado client with the authenticated HTTP client
+	return newTadoClient(serverURL, httpClient)
+}
+
+// LoadTadoClient creates a Tado API client from the token stored at tokenPath without ever starting
+// the device code authentication, returning ErrTokenMissing or ErrTokenFileUnreadable instead
+// Refreshed tokens are still persisted, as with NewAuthenticatedTadoClient
+func LoadTadoClient(ctx context.Context, tokenPath, tokenPassphrase, serverURL string) (*tado.ClientWithResponses, error) {
+	if err := checkTokenFile(tokenPath, tokenPassphrase); err != nil {
+		return nil, err
+	}
+
+	store := oauth2store.NewEncryptedFileTokenStore(tokenPath, tokenPassphrase, maxTokenFileAge)
+	token, err := store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("%w (%s: %v)", ErrTokenMissing, tokenPath, err)
+	}
+
+	httpClient := oauth2.NewClient(ctx, &oauth2store.TokenSource{
+		TokenSource: tado.Config.TokenSource(ctx, token),
+		TokenStore:  store,
+	})
+	return newTadoClient(serverURL, httpClient)
+}
+
+// newTadoClient creates a Tado client sending requests to serverURL through httpClient
+func newTadoClient(serverURL string, httpClient *http.Client) (*tado.ClientWithResponses, error) {
+	if serverURL == "" {
+		serverURL = tado.ServerURL
+	}
+
 	client, err := tado.NewClientWithResponses(
-		tado.ServerURL,
+		serverURL,
 		tado.WithHTTPClient(httpClient),
 	)
 	if err != nil {
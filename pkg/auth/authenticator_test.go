@@ -1,23 +1,38 @@
 package auth
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/metrics"
+	"github.com/clambin/tado/v2/oauth2store"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/oauth2"
 )
 
 // MockTokenSource mocks the oauth2.TokenSource to track Token() calls
+// If tokens is set, successive calls return successive tokens (repeating the last one)
 type MockTokenSource struct {
 	tokenCalls int
 	token      *oauth2.Token
+	tokens     []*oauth2.Token
 	err        error
 }
 
 func (m *MockTokenSource) Token() (*oauth2.Token, error) {
 	m.tokenCalls++
+	if len(m.tokens) > 0 {
+		return m.tokens[min(m.tokenCalls, len(m.tokens))-1], m.err
+	}
 	return m.token, m.err
 }
 
@@ -91,3 +106,211 @@ func TestPersistToken_NilTransport(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid transport type")
 }
+
+// TestTrackTokenRefreshes verifies that a refresh is counted only when the access token changes
+func TestTrackTokenRefreshes(t *testing.T) {
+	mockTokenSource := &MockTokenSource{
+		tokens: []*oauth2.Token{
+			{AccessToken: "first-access-token", TokenType: "Bearer"},
+			{AccessToken: "first-access-token", TokenType: "Bearer"},
+			{AccessToken: "second-access-token", TokenType: "Bearer"},
+		},
+	}
+
+	client := &http.Client{
+		Transport: &oauth2.Transport{
+			Source: mockTokenSource,
+		},
+	}
+
+	registry := prometheus.NewRegistry()
+	exporterMetrics := metrics.NewExporterMetricsUnregistered()
+	require.NoError(t, exporterMetrics.RegisterWith(registry))
+
+	require.NoError(t, trackTokens(client, NewTokenTracker(exporterMetrics.IncrementTokenRefreshes)))
+
+	// First token is the baseline, the unchanged second token is not a refresh, the third is
+	for i := 0; i < 4; i++ {
+		require.NoError(t, persistToken(client))
+	}
+
+	assert.Equal(t, 4, mockTokenSource.tokenCalls)
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	var refreshes *float64
+	for _, family := range families {
+		if family.GetName() == "tado_exporter_token_refreshes_total" {
+			value := family.Metric[0].GetCounter().GetValue()
+			refreshes = &value
+		}
+	}
+	require.NotNil(t, refreshes, "token refresh counter should be registered")
+	assert.Equal(t, 1.0, *refreshes)
+}
+
+// TestTrackTokens_InvalidTransport verifies that trackTokens rejects non-oauth2.Transport
+func TestTrackTokens_InvalidTransport(t *testing.T) {
+	client := &http.Client{
+		Transport: &http.Transport{},
+	}
+
+	err := trackTokens(client, NewTokenTracker(nil))
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid transport type")
+}
+
+// TestTokenTrackerExpiry verifies that the tracker reports the expiry of the latest token
+func TestTokenTrackerExpiry(t *testing.T) {
+	expiry := time.Now().Add(time.Hour)
+	mockTokenSource := &MockTokenSource{
+		token: &oauth2.Token{AccessToken: "test-access-token", Expiry: expiry},
+	}
+
+	client := &http.Client{
+		Transport: &oauth2.Transport{
+			Source: mockTokenSource,
+		},
+	}
+
+	tracker := NewTokenTracker(nil)
+	require.NoError(t, trackTokens(client, tracker))
+
+	_, ok := tracker.Expiry()
+	assert.False(t, ok, "no expiry before a token was observed")
+
+	require.NoError(t, persistToken(client))
+
+	got, ok := tracker.Expiry()
+	assert.True(t, ok)
+	assert.True(t, expiry.Equal(got))
+}
+
+// TestNewTadoClient_UsesServerURL verifies that the created client sends requests to the configured server URL
+func TestNewTadoClient_UsesServerURL(t *testing.T) {
+	var requestedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"stub"}`))
+	}))
+	defer server.Close()
+
+	client, err := newTadoClient(server.URL+"/api/v2", server.Client())
+	require.NoError(t, err)
+
+	response, err := client.GetMeWithResponse(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "/api/v2/me", requestedPath)
+	require.NotNil(t, response.JSON200)
+	assert.Equal(t, "stub", *response.JSON200.Name)
+}
+
+// TestDeviceAuthCallback verifies that the verification URL is printed and, when configured, written to the URL file
+func TestDeviceAuthCallback(t *testing.T) {
+	response := &oauth2.DeviceAuthResponse{VerificationURIComplete: "https://login.tado.com/device?user_code=ABCD-1234"}
+
+	var out bytes.Buffer
+	deviceAuthCallback(&out, "")(response)
+	assert.Contains(t, out.String(), response.VerificationURIComplete)
+
+	urlFile := filepath.Join(t.TempDir(), "auth-url")
+	out.Reset()
+	deviceAuthCallback(&out, urlFile)(response)
+	assert.Contains(t, out.String(), response.VerificationURIComplete, "the URL is still printed")
+
+	content, err := os.ReadFile(urlFile)
+	require.NoError(t, err)
+	assert.Equal(t, response.VerificationURIComplete+"\n", string(content))
+
+	info, err := os.Stat(urlFile)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// A file that can't be written is reported rather than failing authentication
+	out.Reset()
+	deviceAuthCallback(&out, filepath.Join(t.TempDir(), "missing", "auth-url"))(response)
+	assert.Contains(t, out.String(), "Failed to write verification URL")
+}
+
+// TestCreateTadoClient_GarbageTokenFile verifies that a corrupted token file fails with ErrTokenFileUnreadable
+// instead of silently starting a new device authentication
+func TestCreateTadoClient_GarbageTokenFile(t *testing.T) {
+	tokenPath := filepath.Join(t.TempDir(), "token.json")
+	require.NoError(t, os.WriteFile(tokenPath, []byte("not an encrypted token"), 0600))
+
+	_, err := CreateTadoClient(context.Background(), tokenPath, "passphrase", "", 0, nil)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrTokenFileUnreadable))
+	assert.Contains(t, err.Error(), "delete it and re-authenticate, or fix the passphrase")
+	assert.Contains(t, err.Error(), tokenPath)
+}
+
+// TestCheckTokenFile verifies that only existing token files that can't be decrypted are rejected
+func TestCheckTokenFile(t *testing.T) {
+	dir := t.TempDir()
+
+	// No token file yet: the device flow will create one
+	assert.NoError(t, checkTokenFile(filepath.Join(dir, "missing.json"), "passphrase"))
+
+	tokenPath := filepath.Join(dir, "token.json")
+	store := oauth2store.NewEncryptedFileTokenStore(tokenPath, "passphrase", time.Hour)
+	require.NoError(t, store.Save(&oauth2.Token{AccessToken: "access", RefreshToken: "refresh"}))
+
+	assert.NoError(t, checkTokenFile(tokenPath, "passphrase"))
+
+	err := checkTokenFile(tokenPath, "wrong-passphrase")
+	assert.True(t, errors.Is(err, ErrTokenFileUnreadable))
+}
+
+// TestLoadTadoClient verifies that a stored token is loaded while a missing token fails instead of
+// starting the device flow
+func TestLoadTadoClient(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := LoadTadoClient(context.Background(), filepath.Join(dir, "missing.json"), "passphrase", "")
+	assert.True(t, errors.Is(err, ErrTokenMissing))
+
+	tokenPath := filepath.Join(dir, "token.json")
+	store := oauth2store.NewEncryptedFileTokenStore(tokenPath, "passphrase", time.Hour)
+	require.NoError(t, store.Save(&oauth2.Token{AccessToken: "access", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)}))
+
+	client, err := LoadTadoClient(context.Background(), tokenPath, "passphrase", "")
+	require.NoError(t, err)
+	assert.NotNil(t, client)
+
+	_, err = LoadTadoClient(context.Background(), tokenPath, "wrong-passphrase", "")
+	assert.True(t, errors.Is(err, ErrTokenFileUnreadable))
+}
+
+// TestNewOAuth2ClientWithTimeout verifies that a device flow that never completes fails with ErrDeviceFlowTimeout,
+// while a client created in time keeps a live context
+func TestNewOAuth2ClientWithTimeout(t *testing.T) {
+	// Stub device flow that waits for the user until its context is cancelled
+	neverCompletes := func(ctx context.Context) (*http.Client, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	start := time.Now()
+	_, err := newOAuth2ClientWithTimeout(context.Background(), 50*time.Millisecond, neverCompletes)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrDeviceFlowTimeout))
+	assert.Contains(t, err.Error(), "50ms")
+	assert.Less(t, time.Since(start), time.Second)
+
+	var createCtx context.Context
+	completes := func(ctx context.Context) (*http.Client, error) {
+		createCtx = ctx
+		return &http.Client{}, nil
+	}
+
+	client, err := newOAuth2ClientWithTimeout(context.Background(), 50*time.Millisecond, completes)
+	require.NoError(t, err)
+	assert.NotNil(t, client)
+
+	// The client's token refreshes use the context, so it must outlive the timeout
+	time.Sleep(100 * time.Millisecond)
+	assert.NoError(t, createCtx.Err())
+}
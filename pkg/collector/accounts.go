@@ -0,0 +1,181 @@
+// Package collector provides collection across several Tado accounts.
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// accountLabel is the label distinguishing the Tado metrics of different accounts
+const accountLabel = "account"
+
+// Account is a Tado account collected by a MultiAccountCollector
+type Account struct {
+	// Name is the account label value on the account's metrics
+	Name string
+
+	// Collector collects the account's homes; it needs its own MetricDescriptors
+	Collector *TadoCollector
+
+	// ExporterMetrics records the account's scrape, authentication and Tado API health; it needs its own
+	// ExporterMetrics too. Optional: without it the account's collection health isn't recorded
+	ExporterMetrics *metrics.ExporterMetrics
+}
+
+// MultiAccountCollector collects several Tado accounts that are not merged under one login
+// Each account keeps its own TadoCollector, TadoAPI and ExporterMetrics; its Tado metrics and collection
+// health metrics are labelled with the account name. Process-wide exporter metrics, such as build info
+// and HTTP connections, are exposed once without an account label
+type MultiAccountCollector struct {
+	accounts                []Account
+	exporterMetrics         *metrics.ExporterMetrics // Optional: for the process-wide health metrics
+	separateExporterMetrics bool                     // Exporter metrics are registered by RegisterExporterMetricsWith instead
+}
+
+// NewMultiAccountCollector creates a collector for accounts
+func NewMultiAccountCollector(accounts []Account) *MultiAccountCollector {
+	return &MultiAccountCollector{accounts: accounts}
+}
+
+// WithExporterMetrics exposes em's process-wide metrics once, and each account's own ExporterMetrics
+// with the account's metrics
+func (m *MultiAccountCollector) WithExporterMetrics(em *metrics.ExporterMetrics) *MultiAccountCollector {
+	m.exporterMetrics = em
+	m.separateExporterMetrics = false
+	for _, account := range m.accounts {
+		if account.ExporterMetrics != nil {
+			account.Collector.withAccountExporterMetrics(account.ExporterMetrics)
+		}
+	}
+	return m
+}
+
+// WithSeparateExporterMetrics keeps em and the accounts' ExporterMetrics off RegisterWith, for
+// serving them from their own registry with RegisterExporterMetricsWith
+func (m *MultiAccountCollector) WithSeparateExporterMetrics(em *metrics.ExporterMetrics) *MultiAccountCollector {
+	m.exporterMetrics = em
+	m.separateExporterMetrics = true
+	for _, account := range m.accounts {
+		if account.ExporterMetrics != nil {
+			account.Collector.withSharedExporterMetrics(account.ExporterMetrics)
+		}
+	}
+	return m
+}
+
+// RegisterWith registers each account's collector with registerer, labelling its metrics with the account name
+// Call it after the accounts' collectors are configured
+func (m *MultiAccountCollector) RegisterWith(registerer prometheus.Registerer) error {
+	for _, account := range m.accounts {
+		accountRegisterer := prometheus.WrapRegistererWith(prometheus.Labels{accountLabel: account.Name}, registerer)
+		if err := account.Collector.RegisterWith(accountRegisterer); err != nil {
+			return fmt.Errorf("failed to register collector for account %s: %w", account.Name, err)
+		}
+	}
+
+	if m.exporterMetrics != nil && !m.separateExporterMetrics {
+		if err := m.exporterMetrics.RegisterProcessMetricsWith(registerer); err != nil {
+			return fmt.Errorf("failed to register exporter metrics: %w", err)
+		}
+	}
+	return nil
+}
+
+// RegisterWithScrapeTimeout registers each account's collector with registerer like RegisterWith, but
+// their scrapes time out after scrapeTimeout instead of the collectors' own scrape timeout
+func (m *MultiAccountCollector) RegisterWithScrapeTimeout(registerer prometheus.Registerer, scrapeTimeout time.Duration) error {
+	for _, account := range m.accounts {
+		accountRegisterer := prometheus.WrapRegistererWith(prometheus.Labels{accountLabel: account.Name}, registerer)
+		if err := account.Collector.RegisterWithScrapeTimeout(accountRegisterer, scrapeTimeout); err != nil {
+			return fmt.Errorf("failed to register collector for account %s: %w", account.Name, err)
+		}
+	}
+
+	if m.exporterMetrics != nil && !m.separateExporterMetrics {
+		if err := m.exporterMetrics.RegisterProcessMetricsWith(registerer); err != nil {
+			return fmt.Errorf("failed to register exporter metrics: %w", err)
+		}
+	}
+	return nil
+}
+
+// RegisterLastValuesWith registers each account's most recently fetched values with registerer like RegisterWith,
+// without calling the Tado API
+func (m *MultiAccountCollector) RegisterLastValuesWith(registerer prometheus.Registerer) error {
+	for _, account := range m.accounts {
+		accountRegisterer := prometheus.WrapRegistererWith(prometheus.Labels{accountLabel: account.Name}, registerer)
+		if err := account.Collector.RegisterLastValuesWith(accountRegisterer); err != nil {
+			return fmt.Errorf("failed to register collector for account %s: %w", account.Name, err)
+		}
+	}
+
+	if m.exporterMetrics != nil && !m.separateExporterMetrics {
+		if err := m.exporterMetrics.RegisterProcessMetricsWith(registerer); err != nil {
+			return fmt.Errorf("failed to register exporter metrics: %w", err)
+		}
+	}
+	return nil
+}
+
+// RefreshIfStale refreshes each account whose last fetch is older than maxAge, returning their errors joined
+func (m *MultiAccountCollector) RefreshIfStale(ctx context.Context, maxAge time.Duration) error {
+	var errs []error
+	for _, account := range m.accounts {
+		if err := account.Collector.RefreshIfStale(ctx, maxAge); err != nil {
+			errs = append(errs, fmt.Errorf("account %s: %w", account.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RegisterExporterMetricsWith registers the process-wide exporter metrics and each account's exporter
+// metrics, labelled with the account name, with registerer. Use it with WithSeparateExporterMetrics
+func (m *MultiAccountCollector) RegisterExporterMetricsWith(registerer prometheus.Registerer) error {
+	for _, account := range m.accounts {
+		if account.ExporterMetrics == nil {
+			continue
+		}
+		accountRegisterer := prometheus.WrapRegistererWith(prometheus.Labels{accountLabel: account.Name}, registerer)
+		if err := account.ExporterMetrics.RegisterAccountMetricsWith(accountRegisterer); err != nil {
+			return fmt.Errorf("failed to register exporter metrics for account %s: %w", account.Name, err)
+		}
+	}
+
+	if m.exporterMetrics != nil {
+		if err := m.exporterMetrics.RegisterProcessMetricsWith(registerer); err != nil {
+			return fmt.Errorf("failed to register exporter metrics: %w", err)
+		}
+	}
+	return nil
+}
+
+// CircuitBreakerOpen reports whether any account's Tado API circuit breaker is open
+func (m *MultiAccountCollector) CircuitBreakerOpen() bool {
+	for _, account := range m.accounts {
+		if account.ExporterMetrics != nil && account.ExporterMetrics.CircuitBreakerOpen() {
+			return true
+		}
+	}
+	return false
+}
+
+// LastSuccessfulScrape returns the oldest of the accounts' last successful scrapes, so readiness
+// requires every account to be collected. The zero time is returned until all accounts have succeeded
+func (m *MultiAccountCollector) LastSuccessfulScrape() time.Time {
+	var oldest time.Time
+	for i, account := range m.accounts {
+		lastSuccess := account.Collector.LastSuccessfulScrape()
+		if lastSuccess.IsZero() {
+			return time.Time{}
+		}
+		if i == 0 || lastSuccess.Before(oldest) {
+			oldest = lastSuccess
+		}
+	}
+	return oldest
+}
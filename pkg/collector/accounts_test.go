@@ -0,0 +1,193 @@
+package collector
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/collector/mocks"
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/metrics"
+	"github.com/clambin/tado/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newAccountMockAPI returns a mock account with one home holding a single zone at temperature
+func newAccountMockAPI(homeID int64, temperature float32, deviceCount int) *mocks.MockTadoAPI {
+	zoneID := 1
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{homeID})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{Id: &zoneID}}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{
+		"1": {SensorDataPoints: &tado.SensorDataPoints{InsideTemperature: &tado.TemperatureDataPoint{Celsius: &temperature}}},
+	}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return(make([]tado.Device, deviceCount), nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+	return mockAPI
+}
+
+// TestMultiAccountCollector tests that the homes of separate accounts appear side by side under distinct
+// account labels, with each account's exporter health metrics and the process-wide ones exposed once
+func TestMultiAccountCollector(t *testing.T) {
+	t.Parallel()
+
+	newAccount := func(name string, api TadoAPI) Account {
+		metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+		require.NoError(t, err)
+		return Account{
+			Name:            name,
+			Collector:       NewTadoCollector(api, metricDescs, 5*time.Second, ""),
+			ExporterMetrics: metrics.NewExporterMetricsUnregistered(),
+		}
+	}
+
+	multiCollector := NewMultiAccountCollector([]Account{
+		newAccount("main", newAccountMockAPI(100, 20.5, 3)),
+		newAccount("cabin", newAccountMockAPI(200, 12.0, 2)),
+	}).WithExporterMetrics(metrics.NewExporterMetricsUnregistered())
+
+	registry := prometheus.NewRegistry()
+	require.NoError(t, multiCollector.RegisterWith(registry))
+
+	value, found := findGaugeValue(t, registry, "tado_temperature_measured_celsius", map[string]string{"account": "main", "home_id": "100"})
+	require.True(t, found)
+	assert.Equal(t, 20.5, value)
+
+	value, found = findGaugeValue(t, registry, "tado_temperature_measured_celsius", map[string]string{"account": "cabin", "home_id": "200"})
+	require.True(t, found)
+	assert.Equal(t, 12.0, value)
+
+	// Home-level metrics no longer overwrite each other across accounts
+	value, found = findGaugeValue(t, registry, "tado_home_devices_total", map[string]string{"account": "main"})
+	require.True(t, found)
+	assert.Equal(t, 3.0, value)
+
+	value, found = findGaugeValue(t, registry, "tado_home_devices_total", map[string]string{"account": "cabin"})
+	require.True(t, found)
+	assert.Equal(t, 2.0, value)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		switch family.GetName() {
+		case "tado_exporter_scrape_duration_seconds":
+			require.Len(t, family.Metric, 2, "each account's scrapes are exposed separately")
+			for _, metric := range family.Metric {
+				require.Len(t, metric.Label, 1)
+				assert.Equal(t, "account", metric.Label[0].GetName())
+				assert.Positive(t, metric.GetHistogram().GetSampleCount())
+			}
+		case "tado_exporter_build_info":
+			require.Len(t, family.Metric, 1, "process-wide metrics are exposed once")
+			for _, label := range family.Metric[0].Label {
+				assert.NotEqual(t, "account", label.GetName(), "process-wide metrics carry no account label")
+			}
+		}
+	}
+
+	assert.False(t, multiCollector.LastSuccessfulScrape().IsZero(), "both accounts were collected")
+}
+
+// TestMultiAccountCollectorLastSuccessfulScrape tests that readiness waits for every account
+func TestMultiAccountCollectorLastSuccessfulScrape(t *testing.T) {
+	t.Parallel()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	healthy := NewTadoCollector(newAccountMockAPI(100, 20.5, 1), metricDescs, 5*time.Second, "")
+
+	failingAPI := &mocks.MockTadoAPI{}
+	failingAPI.ExpectGetMeReturnsEmptyHomes()
+	metricDescs, err = metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	failing := NewTadoCollector(failingAPI, metricDescs, 5*time.Second, "")
+
+	multiCollector := NewMultiAccountCollector([]Account{
+		{Name: "main", Collector: healthy},
+		{Name: "cabin", Collector: failing},
+	})
+
+	registry := prometheus.NewRegistry()
+	require.NoError(t, multiCollector.RegisterWith(registry))
+	_, err = registry.Gather()
+	require.NoError(t, err)
+
+	assert.False(t, healthy.LastSuccessfulScrape().IsZero())
+	assert.True(t, multiCollector.LastSuccessfulScrape().IsZero(), "not ready while an account has never been collected")
+}
+
+// TestMultiAccountCollectorExporterMetricsPerAccount tests that a failing account's scrape health doesn't
+// overwrite that of a healthy one
+func TestMultiAccountCollectorExporterMetricsPerAccount(t *testing.T) {
+	t.Parallel()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	healthy := NewTadoCollector(newAccountMockAPI(100, 20.5, 1), metricDescs, 5*time.Second, "")
+
+	failingAPI := &mocks.MockTadoAPI{}
+	failingAPI.ExpectGetMeReturnsError(errors.New("connection refused"))
+	metricDescs, err = metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	failing := NewTadoCollector(failingAPI, metricDescs, 5*time.Second, "")
+
+	multiCollector := NewMultiAccountCollector([]Account{
+		{Name: "main", Collector: healthy, ExporterMetrics: metrics.NewExporterMetricsUnregistered()},
+		{Name: "cabin", Collector: failing, ExporterMetrics: metrics.NewExporterMetricsUnregistered()},
+	}).WithExporterMetrics(metrics.NewExporterMetricsUnregistered())
+
+	registry := prometheus.NewRegistry()
+	require.NoError(t, multiCollector.RegisterWith(registry))
+
+	value, found := findGaugeValue(t, registry, "tado_exporter_scrape_success", map[string]string{"account": "main"})
+	require.True(t, found)
+	assert.Equal(t, 1.0, value)
+
+	value, found = findGaugeValue(t, registry, "tado_exporter_scrape_success", map[string]string{"account": "cabin"})
+	require.True(t, found)
+	assert.Equal(t, 0.0, value)
+
+	_, found = findGaugeValue(t, registry, "tado_exporter_last_error_info", map[string]string{"account": "cabin"})
+	assert.True(t, found, "the failing account reports its error")
+	_, found = findGaugeValue(t, registry, "tado_exporter_last_error_info", map[string]string{"account": "main"})
+	assert.False(t, found, "the healthy account reports no error")
+
+	value, found = findGaugeValue(t, registry, "tado_exporter_homes_total", map[string]string{"account": "main"})
+	require.True(t, found)
+	assert.Equal(t, 1.0, value)
+}
+
+// TestMultiAccountCollectorSeparateExporterMetrics tests that separately served exporter metrics keep
+// each account's health apart and stay off the Tado metrics registry
+func TestMultiAccountCollectorSeparateExporterMetrics(t *testing.T) {
+	t.Parallel()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	multiCollector := NewMultiAccountCollector([]Account{
+		{
+			Name:            "main",
+			Collector:       NewTadoCollector(newAccountMockAPI(100, 20.5, 1), metricDescs, 5*time.Second, ""),
+			ExporterMetrics: metrics.NewExporterMetricsUnregistered(),
+		},
+	}).WithSeparateExporterMetrics(metrics.NewExporterMetricsUnregistered())
+
+	registry := prometheus.NewRegistry()
+	require.NoError(t, multiCollector.RegisterWith(registry))
+	exporterRegistry := prometheus.NewRegistry()
+	require.NoError(t, multiCollector.RegisterExporterMetricsWith(exporterRegistry))
+
+	_, found := findGaugeValue(t, registry, "tado_temperature_measured_celsius", map[string]string{"account": "main"})
+	require.True(t, found)
+	_, found = findGaugeValue(t, registry, "tado_exporter_scrape_success", nil)
+	assert.False(t, found, "exporter metrics are served separately")
+
+	value, found := findGaugeValue(t, exporterRegistry, "tado_exporter_scrape_success", map[string]string{"account": "main"})
+	require.True(t, found)
+	assert.Equal(t, 1.0, value)
+	assert.False(t, multiCollector.CircuitBreakerOpen())
+}
@@ -3,80 +3,214 @@ package collector
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"time"
 
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/logger"
 	"github.com/clambin/tado/v2"
 )
 
 // TadoClientAdapter adapts *tado.ClientWithResponses to implement TadoAPI interface
 type TadoClientAdapter struct {
-	client *tado.ClientWithResponses
+	client            *tado.ClientWithResponses
+	log               *logger.Logger
+	slowCallThreshold time.Duration // Calls slower than this are logged; 0 disables
+	onUnavailable     func()        // Called for every 503 response; nil disables
+}
+
+// MaintenanceError is returned when the Tado API answers 503 Service Unavailable,
+// which it does across all endpoints during scheduled maintenance
+type MaintenanceError struct {
+	Operation  string // The failed call, e.g. "get zones"
+	StatusCode int
+}
+
+func (e *MaintenanceError) Error() string {
+	return fmt.Sprintf("failed to %s: Tado API unavailable (status code %d), possibly down for maintenance", e.Operation, e.StatusCode)
+}
+
+// StatusError is returned when the Tado API answers with an unexpected status code other than 503
+type StatusError struct {
+	Operation  string // The failed call, e.g. "get zones"
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("failed to %s: status code %d", e.Operation, e.StatusCode)
+}
+
+// IsMaintenanceError reports whether err is, or wraps, a MaintenanceError
+func IsMaintenanceError(err error) bool {
+	var maintenanceErr *MaintenanceError
+	return errors.As(err, &maintenanceErr)
 }
 
 func NewTadoClientAdapter(client *tado.ClientWithResponses) TadoAPI {
-	return &TadoClientAdapter{client: client}
+	return NewTadoClientAdapterWithLogger(client, nil, 0)
+}
+
+func NewTadoClientAdapterWithLogger(client *tado.ClientWithResponses, log *logger.Logger, slowCallThreshold time.Duration) *TadoClientAdapter {
+	// Use noop logger if none provided
+	if log == nil {
+		noop, _ := logger.NewWithWriter("error", "text", io.Discard)
+		log = noop
+	}
+
+	return &TadoClientAdapter{
+		client:            client,
+		log:               log,
+		slowCallThreshold: slowCallThreshold,
+	}
+}
+
+// WithOnUnavailable sets a callback run for every 503 response from the Tado API
+func (a *TadoClientAdapter) WithOnUnavailable(fn func()) *TadoClientAdapter {
+	a.onUnavailable = fn
+	return a
+}
+
+// statusError returns the error for an unexpected status code from operation
+// A 503 becomes a MaintenanceError so callers can tell expected outages from real failures
+func (a *TadoClientAdapter) statusError(operation string, statusCode int) error {
+	if statusCode == http.StatusServiceUnavailable {
+		if a.onUnavailable != nil {
+			a.onUnavailable()
+		}
+		return &MaintenanceError{Operation: operation, StatusCode: statusCode}
+	}
+	return &StatusError{Operation: operation, StatusCode: statusCode}
+}
+
+// logSlowCall logs a warning if the call to endpoint started at start exceeded the slow call threshold
+// The scrape's request ID is taken from ctx so the warning can be correlated with collector logs
+func (a *TadoClientAdapter) logSlowCall(ctx context.Context, endpoint string, start time.Time) {
+	duration := time.Since(start)
+	if a.slowCallThreshold > 0 && duration > a.slowCallThreshold {
+		a.log.WarnContext(ctx, "Slow Tado API call",
+			"endpoint", endpoint,
+			"duration_seconds", duration.Seconds(),
+			"threshold_seconds", a.slowCallThreshold.Seconds())
+	}
 }
 
 func (a *TadoClientAdapter) GetMe(ctx context.Context) (*tado.User, error) {
+	defer a.logSlowCall(ctx, "GetMe", time.Now())
+
 	response, err := a.client.GetMeWithResponse(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get me: %w", err)
 	}
 
 	if response.StatusCode() != 200 || response.JSON200 == nil {
-		return nil, fmt.Errorf("failed to get me: status code %d", response.StatusCode())
+		return nil, a.statusError("get me", response.StatusCode())
 	}
 
 	return response.JSON200, nil
 }
 
 func (a *TadoClientAdapter) GetHomeState(ctx context.Context, homeID tado.HomeId) (*tado.HomeState, error) {
+	defer a.logSlowCall(ctx, "GetHomeState", time.Now())
+
 	response, err := a.client.GetHomeStateWithResponse(ctx, homeID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get home state: %w", err)
 	}
 
 	if response.StatusCode() != 200 || response.JSON200 == nil {
-		return nil, fmt.Errorf("failed to get home state: status code %d", response.StatusCode())
+		return nil, a.statusError("get home state", response.StatusCode())
 	}
 
 	return response.JSON200, nil
 }
 
 func (a *TadoClientAdapter) GetZones(ctx context.Context, homeID tado.HomeId) ([]tado.Zone, error) {
+	defer a.logSlowCall(ctx, "GetZones", time.Now())
+
 	response, err := a.client.GetZonesWithResponse(ctx, homeID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get zones: %w", err)
 	}
 
 	if response.StatusCode() != 200 || response.JSON200 == nil {
-		return nil, fmt.Errorf("failed to get zones: status code %d", response.StatusCode())
+		return nil, a.statusError("get zones", response.StatusCode())
 	}
 
 	return *response.JSON200, nil
 }
 
 func (a *TadoClientAdapter) GetZoneStates(ctx context.Context, homeID tado.HomeId) (*tado.ZoneStates, error) {
+	defer a.logSlowCall(ctx, "GetZoneStates", time.Now())
+
 	response, err := a.client.GetZoneStatesWithResponse(ctx, homeID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get zone states: %w", err)
 	}
 
 	if response.StatusCode() != 200 || response.JSON200 == nil {
-		return nil, fmt.Errorf("failed to get zone states: status code %d", response.StatusCode())
+		return nil, a.statusError("get zone states", response.StatusCode())
 	}
 
 	return response.JSON200, nil
 }
 
 func (a *TadoClientAdapter) GetWeather(ctx context.Context, homeID tado.HomeId) (*tado.Weather, error) {
+	defer a.logSlowCall(ctx, "GetWeather", time.Now())
+
 	response, err := a.client.GetWeatherWithResponse(ctx, homeID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get weather: %w", err)
 	}
 
 	if response.StatusCode() != 200 || response.JSON200 == nil {
-		return nil, fmt.Errorf("failed to get weather: status code %d", response.StatusCode())
+		return nil, a.statusError("get weather", response.StatusCode())
+	}
+
+	return response.JSON200, nil
+}
+
+func (a *TadoClientAdapter) GetDevices(ctx context.Context, homeID tado.HomeId) ([]tado.Device, error) {
+	defer a.logSlowCall(ctx, "GetDevices", time.Now())
+
+	response, err := a.client.GetDevicesWithResponse(ctx, homeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get devices: %w", err)
+	}
+
+	if response.StatusCode() != 200 || response.JSON200 == nil {
+		return nil, a.statusError("get devices", response.StatusCode())
+	}
+
+	return *response.JSON200, nil
+}
+
+func (a *TadoClientAdapter) GetMobileDevices(ctx context.Context, homeID tado.HomeId) ([]tado.MobileDevice, error) {
+	defer a.logSlowCall(ctx, "GetMobileDevices", time.Now())
+
+	response, err := a.client.GetMobileDevicesWithResponse(ctx, homeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mobile devices: %w", err)
+	}
+
+	if response.StatusCode() != 200 || response.JSON200 == nil {
+		return nil, a.statusError("get mobile devices", response.StatusCode())
+	}
+
+	return *response.JSON200, nil
+}
+
+func (a *TadoClientAdapter) GetTemperatureOffset(ctx context.Context, deviceID tado.DeviceId) (*tado.Temperature, error) {
+	defer a.logSlowCall(ctx, "GetTemperatureOffset", time.Now())
+
+	response, err := a.client.GetTemperatureOffsetWithResponse(ctx, deviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get temperature offset: %w", err)
+	}
+
+	if response.StatusCode() != 200 || response.JSON200 == nil {
+		return nil, a.statusError("get temperature offset", response.StatusCode())
 	}
 
 	return response.JSON200, nil
@@ -0,0 +1,138 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/logger"
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/metrics"
+	"github.com/clambin/tado/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newStubTadoClient returns a Tado client talking to a stub server which
+// waits for delay before answering every request with an empty JSON object
+func newStubTadoClient(t *testing.T, delay time.Duration) *tado.ClientWithResponses {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := tado.NewClientWithResponses(server.URL)
+	require.NoError(t, err)
+	return client
+}
+
+// TestAdapterLogsSlowCalls tests that calls exceeding the threshold are logged with endpoint and duration
+func TestAdapterLogsSlowCalls(t *testing.T) {
+	t.Parallel()
+
+	var logOutput bytes.Buffer
+	log, err := logger.NewWithWriter("warn", "json", &logOutput)
+	require.NoError(t, err)
+
+	adapter := NewTadoClientAdapterWithLogger(newStubTadoClient(t, 100*time.Millisecond), log, 50*time.Millisecond)
+
+	_, err = adapter.GetMe(context.Background())
+	require.NoError(t, err)
+
+	assert.Contains(t, logOutput.String(), "Slow Tado API call")
+	assert.Contains(t, logOutput.String(), `"endpoint":"GetMe"`)
+	assert.Contains(t, logOutput.String(), `"duration_seconds"`)
+}
+
+// TestAdapterDoesNotLogFastCalls tests that calls within the threshold are not logged
+func TestAdapterDoesNotLogFastCalls(t *testing.T) {
+	t.Parallel()
+
+	var logOutput bytes.Buffer
+	log, err := logger.NewWithWriter("warn", "json", &logOutput)
+	require.NoError(t, err)
+
+	adapter := NewTadoClientAdapterWithLogger(newStubTadoClient(t, 0), log, time.Second)
+
+	_, err = adapter.GetMe(context.Background())
+	require.NoError(t, err)
+
+	assert.Empty(t, logOutput.String())
+}
+
+// TestScrapeLogsShareRequestID tests that collector and adapter logs from one scrape carry the same request ID
+func TestScrapeLogsShareRequestID(t *testing.T) {
+	t.Parallel()
+
+	var logOutput bytes.Buffer
+	log, err := logger.NewWithWriter("warn", "json", &logOutput)
+	require.NoError(t, err)
+
+	// The stub answers slowly (adapter warning) and with a user without a homes field (collector warning)
+	adapter := NewTadoClientAdapterWithLogger(newStubTadoClient(t, 20*time.Millisecond), log, time.Millisecond)
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+
+	collector := NewTadoCollectorWithLogger(adapter, metricDescs, 5*time.Second, "", log)
+
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	requestIDs := map[string]bool{}
+	messages := map[string]bool{}
+	for _, line := range strings.Split(strings.TrimSpace(logOutput.String()), "\n") {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+
+		requestID, _ := entry["request_id"].(string)
+		assert.NotEmpty(t, requestID, "log line without request_id: %s", line)
+		requestIDs[requestID] = true
+		messages[entry["msg"].(string)] = true
+	}
+
+	assert.True(t, messages["Slow Tado API call"], "adapter should log the slow call")
+	assert.True(t, messages["user response has no homes field"], "collector should log the missing homes")
+	assert.Len(t, requestIDs, 1, "all log lines of one scrape should share a request ID")
+}
+
+// TestAdapterMaintenanceError tests that 503 responses return a MaintenanceError and are counted
+func TestAdapterMaintenanceError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := tado.NewClientWithResponses(server.URL)
+	require.NoError(t, err)
+
+	exporterMetrics := metrics.NewExporterMetricsUnregistered()
+	registry := prometheus.NewRegistry()
+	require.NoError(t, exporterMetrics.RegisterWith(registry))
+
+	adapter := NewTadoClientAdapterWithLogger(client, nil, 0).WithOnUnavailable(exporterMetrics.IncrementAPIUnavailable)
+
+	_, err = adapter.GetZones(context.Background(), 1)
+	require.Error(t, err)
+	var maintenanceErr *MaintenanceError
+	require.ErrorAs(t, err, &maintenanceErr)
+	assert.Equal(t, "get zones", maintenanceErr.Operation)
+	assert.Equal(t, http.StatusServiceUnavailable, maintenanceErr.StatusCode)
+
+	_, err = adapter.GetMe(context.Background())
+	assert.True(t, IsMaintenanceError(err))
+
+	assert.Equal(t, 2.0, findCounterValue(t, registry, "tado_exporter_api_unavailable_total"))
+}
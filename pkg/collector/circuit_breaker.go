@@ -0,0 +1,219 @@
+// Package collector provides a circuit breaker for Tado API calls.
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/clambin/tado/v2"
+)
+
+// CircuitState is the state of a circuit breaker
+type CircuitState int
+
+const (
+	// CircuitClosed passes calls through to the Tado API
+	CircuitClosed CircuitState = iota
+	// CircuitHalfOpen lets a single trial call through to probe whether the Tado API has recovered
+	CircuitHalfOpen
+	// CircuitOpen fails calls immediately without contacting the Tado API
+	CircuitOpen
+)
+
+// String returns the lower-case name of the state
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitHalfOpen:
+		return "half-open"
+	case CircuitOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrCircuitOpen is returned without calling the Tado API while the circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitBreakerSettings configures the circuit breaker created by NewCircuitBreakerAPI
+type CircuitBreakerSettings struct {
+	// MaxFailures is the number of consecutive failed calls that opens the breaker
+	MaxFailures int
+
+	// OpenTimeout is how long the breaker stays open before a trial call is let through
+	OpenTimeout time.Duration
+
+	// OnStateChange, if non-nil, is called after every state transition
+	OnStateChange func(from, to CircuitState)
+}
+
+// circuitBreaker stops calling a failing dependency after MaxFailures consecutive failures,
+// retrying with a single trial call once OpenTimeout has passed
+type circuitBreaker struct {
+	settings CircuitBreakerSettings
+	now      func() time.Time
+
+	mu            sync.Mutex
+	state         CircuitState
+	failures      int
+	openedAt      time.Time
+	trialInFlight bool
+}
+
+// newCircuitBreaker creates a closed circuit breaker
+func newCircuitBreaker(settings CircuitBreakerSettings) *circuitBreaker {
+	return &circuitBreaker{settings: settings, now: time.Now}
+}
+
+// State returns the current state, moving an open breaker to half-open once its timeout has passed
+func (cb *circuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	from := cb.state
+	to := cb.currentState()
+	cb.mu.Unlock()
+
+	cb.notify(from, to)
+	return to
+}
+
+// Execute runs fn unless the breaker is open, recording its outcome
+// A MaintenanceError or ErrRateLimited from fn is returned without being recorded as a failure
+// ErrCircuitOpen is returned without running fn while the breaker is open
+func (cb *circuitBreaker) Execute(fn func() (interface{}, error)) (interface{}, error) {
+	cb.mu.Lock()
+	before := cb.state
+	state := cb.currentState()
+	if state == CircuitOpen || (state == CircuitHalfOpen && cb.trialInFlight) {
+		cb.mu.Unlock()
+		cb.notify(before, state)
+		return nil, ErrCircuitOpen
+	}
+	if state == CircuitHalfOpen {
+		cb.trialInFlight = true
+	}
+	cb.mu.Unlock()
+	cb.notify(before, state)
+
+	result, err := fn()
+
+	cb.mu.Lock()
+	from := cb.state
+	if state == CircuitHalfOpen {
+		cb.trialInFlight = false
+	}
+	switch {
+	case IsMaintenanceError(err), errors.Is(err, ErrRateLimited):
+		// Tado maintenance is expected to recover on its own, and the exporter's own rate limit
+		// says nothing about the API, so neither counts as a failure nor proves the API healthy;
+		// a half-open breaker waits for the next trial
+	case err != nil:
+		cb.failures++
+		if cb.state == CircuitHalfOpen || cb.failures >= cb.settings.MaxFailures {
+			cb.setState(CircuitOpen)
+		}
+	default:
+		cb.failures = 0
+		if cb.state == CircuitHalfOpen {
+			cb.setState(CircuitClosed)
+		}
+	}
+	to := cb.state
+	cb.mu.Unlock()
+
+	cb.notify(from, to)
+	return result, err
+}
+
+// currentState moves an open breaker whose timeout has passed to half-open and returns the state
+// cb.mu must be held
+func (cb *circuitBreaker) currentState() CircuitState {
+	if cb.state == CircuitOpen && cb.now().Sub(cb.openedAt) >= cb.settings.OpenTimeout {
+		cb.setState(CircuitHalfOpen)
+	}
+	return cb.state
+}
+
+// setState transitions to state, resetting the failure count
+// cb.mu must be held
+func (cb *circuitBreaker) setState(state CircuitState) {
+	if state == CircuitOpen {
+		cb.openedAt = cb.now()
+	}
+	cb.state = state
+	cb.failures = 0
+}
+
+// notify calls OnStateChange if the state changed; it must be called without cb.mu held
+func (cb *circuitBreaker) notify(from, to CircuitState) {
+	if from != to && cb.settings.OnStateChange != nil {
+		cb.settings.OnStateChange(from, to)
+	}
+}
+
+// executor runs a call, possibly refusing it; circuitBreaker implements it
+type executor interface {
+	Execute(fn func() (interface{}, error)) (interface{}, error)
+}
+
+// circuitBreakerAPI wraps a TadoAPI so that calls fail fast while the Tado API is failing
+type circuitBreakerAPI struct {
+	api     TadoAPI
+	breaker executor
+}
+
+// NewCircuitBreakerAPI wraps api with a circuit breaker shared by all of its endpoints
+func NewCircuitBreakerAPI(api TadoAPI, settings CircuitBreakerSettings) TadoAPI {
+	return &circuitBreakerAPI{api: api, breaker: newCircuitBreaker(settings)}
+}
+
+// execute runs fn through breaker and returns its result as a T
+// A result of another type, e.g. a nil interface, is reported as an error rather than panicking
+func execute[T any](breaker executor, operation string, fn func() (interface{}, error)) (T, error) {
+	var zero T
+	result, err := breaker.Execute(fn)
+	if err != nil {
+		return zero, err
+	}
+	typed, ok := result.(T)
+	if !ok {
+		return zero, fmt.Errorf("circuit breaker: unexpected %s result of type %T", operation, result)
+	}
+	return typed, nil
+}
+
+func (c *circuitBreakerAPI) GetMe(ctx context.Context) (*tado.User, error) {
+	return execute[*tado.User](c.breaker, "GetMe", func() (interface{}, error) { return c.api.GetMe(ctx) })
+}
+
+func (c *circuitBreakerAPI) GetHomeState(ctx context.Context, homeID tado.HomeId) (*tado.HomeState, error) {
+	return execute[*tado.HomeState](c.breaker, "GetHomeState", func() (interface{}, error) { return c.api.GetHomeState(ctx, homeID) })
+}
+
+func (c *circuitBreakerAPI) GetZones(ctx context.Context, homeID tado.HomeId) ([]tado.Zone, error) {
+	return execute[[]tado.Zone](c.breaker, "GetZones", func() (interface{}, error) { return c.api.GetZones(ctx, homeID) })
+}
+
+func (c *circuitBreakerAPI) GetZoneStates(ctx context.Context, homeID tado.HomeId) (*tado.ZoneStates, error) {
+	return execute[*tado.ZoneStates](c.breaker, "GetZoneStates", func() (interface{}, error) { return c.api.GetZoneStates(ctx, homeID) })
+}
+
+func (c *circuitBreakerAPI) GetWeather(ctx context.Context, homeID tado.HomeId) (*tado.Weather, error) {
+	return execute[*tado.Weather](c.breaker, "GetWeather", func() (interface{}, error) { return c.api.GetWeather(ctx, homeID) })
+}
+
+func (c *circuitBreakerAPI) GetDevices(ctx context.Context, homeID tado.HomeId) ([]tado.Device, error) {
+	return execute[[]tado.Device](c.breaker, "GetDevices", func() (interface{}, error) { return c.api.GetDevices(ctx, homeID) })
+}
+
+func (c *circuitBreakerAPI) GetMobileDevices(ctx context.Context, homeID tado.HomeId) ([]tado.MobileDevice, error) {
+	return execute[[]tado.MobileDevice](c.breaker, "GetMobileDevices", func() (interface{}, error) { return c.api.GetMobileDevices(ctx, homeID) })
+}
+
+func (c *circuitBreakerAPI) GetTemperatureOffset(ctx context.Context, deviceID tado.DeviceId) (*tado.Temperature, error) {
+	return execute[*tado.Temperature](c.breaker, "GetTemperatureOffset", func() (interface{}, error) { return c.api.GetTemperatureOffset(ctx, deviceID) })
+}
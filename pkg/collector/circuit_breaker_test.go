@@ -0,0 +1,202 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/collector/mocks"
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/metrics"
+	"github.com/clambin/tado/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var errTadoUnavailable = errors.New("tado unavailable")
+
+func failingCall() (interface{}, error)    { return nil, errTadoUnavailable }
+func succeedingCall() (interface{}, error) { return "ok", nil }
+
+// TestCircuitBreakerStateTransitions tests opening after consecutive failures, the half-open trial and recovery
+func TestCircuitBreakerStateTransitions(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	var transitions []string
+	cb := newCircuitBreaker(CircuitBreakerSettings{
+		MaxFailures: 2,
+		OpenTimeout: time.Minute,
+		OnStateChange: func(from, to CircuitState) {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	})
+	cb.now = func() time.Time { return now }
+
+	// A success resets the consecutive failure count
+	_, _ = cb.Execute(failingCall)
+	_, _ = cb.Execute(succeedingCall)
+	_, _ = cb.Execute(failingCall)
+	assert.Equal(t, CircuitClosed, cb.State())
+
+	_, err := cb.Execute(failingCall)
+	assert.ErrorIs(t, err, errTadoUnavailable)
+	assert.Equal(t, CircuitOpen, cb.State())
+
+	// Calls fail fast while open
+	called := false
+	_, err = cb.Execute(func() (interface{}, error) { called = true; return nil, nil })
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.False(t, called)
+
+	// After the timeout a failed trial call reopens the breaker
+	now = now.Add(time.Minute)
+	assert.Equal(t, CircuitHalfOpen, cb.State())
+	_, _ = cb.Execute(failingCall)
+	assert.Equal(t, CircuitOpen, cb.State())
+
+	// A successful trial call closes it
+	now = now.Add(time.Minute)
+	result, err := cb.Execute(succeedingCall)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", result)
+	assert.Equal(t, CircuitClosed, cb.State())
+
+	assert.Equal(t, []string{
+		"closed->open",
+		"open->half-open", "half-open->open",
+		"open->half-open", "half-open->closed",
+	}, transitions)
+}
+
+// TestCircuitBreakerIgnoresMaintenance tests that Tado maintenance responses do not open the breaker
+func TestCircuitBreakerIgnoresMaintenance(t *testing.T) {
+	t.Parallel()
+
+	cb := newCircuitBreaker(CircuitBreakerSettings{MaxFailures: 2, OpenTimeout: time.Minute})
+	maintenanceCall := func() (interface{}, error) {
+		return nil, &MaintenanceError{Operation: "get zones", StatusCode: 503}
+	}
+
+	for i := 0; i < 5; i++ {
+		_, err := cb.Execute(maintenanceCall)
+		assert.True(t, IsMaintenanceError(err))
+	}
+	assert.Equal(t, CircuitClosed, cb.State())
+
+	// Maintenance does not reset the count of real failures either
+	_, _ = cb.Execute(failingCall)
+	_, _ = cb.Execute(maintenanceCall)
+	_, _ = cb.Execute(failingCall)
+	assert.Equal(t, CircuitOpen, cb.State())
+}
+
+// TestCircuitBreakerIgnoresRateLimit tests that calls rejected by the exporter's own rate limit do not open the breaker
+func TestCircuitBreakerIgnoresRateLimit(t *testing.T) {
+	t.Parallel()
+
+	cb := newCircuitBreaker(CircuitBreakerSettings{MaxFailures: 2, OpenTimeout: time.Minute})
+	rateLimitedCall := func() (interface{}, error) {
+		return nil, fmt.Errorf("%w: next request allowed in 1s", ErrRateLimited)
+	}
+
+	for i := 0; i < 5; i++ {
+		_, err := cb.Execute(rateLimitedCall)
+		assert.ErrorIs(t, err, ErrRateLimited)
+	}
+	assert.Equal(t, CircuitClosed, cb.State())
+}
+
+// TestCircuitBreakerAPI_FailsFast tests that the wrapped API is not called while the breaker is open
+func TestCircuitBreakerAPI_FailsFast(t *testing.T) {
+	t.Parallel()
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsError(errTadoUnavailable)
+
+	api := NewCircuitBreakerAPI(mockAPI, CircuitBreakerSettings{MaxFailures: 1, OpenTimeout: time.Hour})
+
+	_, err := api.GetMe(context.Background())
+	assert.ErrorIs(t, err, errTadoUnavailable)
+
+	_, err = api.GetMe(context.Background())
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	_, err = api.GetZones(context.Background(), 1)
+	assert.ErrorIs(t, err, ErrCircuitOpen, "the breaker is shared by all endpoints")
+
+	mockAPI.AssertNumberOfCalls(t, "GetMe", 1)
+}
+
+// nilResultExecutor is a breaker stub that returns a nil result without an error
+type nilResultExecutor struct{}
+
+func (nilResultExecutor) Execute(func() (interface{}, error)) (interface{}, error) { return nil, nil }
+
+// TestCircuitBreakerAPI_NilResult tests that a result of the wrong type is reported as an error instead of panicking
+func TestCircuitBreakerAPI_NilResult(t *testing.T) {
+	t.Parallel()
+
+	api := &circuitBreakerAPI{api: &mocks.MockTadoAPI{}, breaker: nilResultExecutor{}}
+
+	assert.NotPanics(t, func() {
+		user, err := api.GetMe(context.Background())
+		assert.Nil(t, user)
+		assert.ErrorContains(t, err, "unexpected GetMe result")
+
+		zones, err := api.GetZones(context.Background(), 1)
+		assert.Nil(t, zones)
+		assert.ErrorContains(t, err, "unexpected GetZones result")
+	})
+
+	// A typed nil result is passed through unchanged
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone(nil), nil)
+	zones, err := NewCircuitBreakerAPI(mockAPI, CircuitBreakerSettings{MaxFailures: 1, OpenTimeout: time.Hour}).GetZones(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Nil(t, zones)
+}
+
+// TestCircuitBreakerOpenSecondsMetric tests that the open duration metric reflects the time since the breaker opened
+func TestCircuitBreakerOpenSecondsMetric(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+	exporterMetrics := metrics.NewExporterMetricsUnregistered()
+	require.NoError(t, exporterMetrics.RegisterWith(registry))
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.On("GetMe", mock.Anything).Return(nil, errTadoUnavailable).Once()
+	homes := []tado.HomeBase{}
+	mockAPI.On("GetMe", mock.Anything).Return(&tado.User{Homes: &homes}, nil)
+
+	api := NewCircuitBreakerAPI(mockAPI, CircuitBreakerSettings{
+		MaxFailures: 1,
+		OpenTimeout: 100 * time.Millisecond,
+		OnStateChange: func(_, to CircuitState) {
+			exporterMetrics.SetCircuitBreakerOpen(to != CircuitClosed)
+		},
+	})
+
+	value, found := findGaugeValue(t, registry, "tado_exporter_circuit_breaker_open_seconds", map[string]string{})
+	require.True(t, found)
+	assert.Equal(t, 0.0, value, "0 while closed")
+
+	// Force the breaker open and let it stay open for a while
+	_, err := api.GetMe(context.Background())
+	require.Error(t, err)
+	time.Sleep(150 * time.Millisecond)
+
+	value, _ = findGaugeValue(t, registry, "tado_exporter_circuit_breaker_open_seconds", map[string]string{})
+	assert.GreaterOrEqual(t, value, 0.15)
+	assert.Less(t, value, 5.0)
+
+	// The trial call succeeds and closes the breaker
+	_, err = api.GetMe(context.Background())
+	require.NoError(t, err)
+
+	value, _ = findGaugeValue(t, registry, "tado_exporter_circuit_breaker_open_seconds", map[string]string{})
+	assert.Equal(t, 0.0, value, "0 again once closed")
+}
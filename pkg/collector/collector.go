@@ -13,8 +13,16 @@ package collector
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"math"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/andreweacott/tado-prometheus-exporter/pkg/logger"
@@ -23,15 +31,97 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+// clockSkewWarnThreshold is how far ahead of the local clock Tado sensor timestamps
+// may be before a warning is logged. Readings are normally a few minutes in the past.
+const clockSkewWarnThreshold = time.Minute
+
+// zoneTimeBudgetWarnThreshold is the remaining scrape time per zone below which a warning
+// is logged, as the scrape is then likely to time out before every zone is collected
+const zoneTimeBudgetWarnThreshold = 100 * time.Millisecond
+
+// slowScrapeWarnRatio is the fraction of the scrape timeout a scrape may take before a warning
+// suggesting a higher timeout is logged
+const slowScrapeWarnRatio = 0.9
+
+// slowScrapeWarnInterval is the minimum time between slow scrape warnings, so that a
+// consistently slow Tado API logs one warning every few minutes rather than one per scrape
+const slowScrapeWarnInterval = 15 * time.Minute
+
+// errMissingHomes is returned when GetMe returns a user without a homes field
+var errMissingHomes = errors.New("malformed user response: homes field missing")
+
 // TadoCollector implements the prometheus.Collector interface
 // It fetches Tado metrics on-demand when Prometheus scrapes the /metrics endpoint
 type TadoCollector struct {
-	tadoClient        TadoAPI
-	metricDescriptors *metrics.MetricDescriptors
-	scrapeTimeout     time.Duration
-	homeID            string // Optional: filter to specific home
-	log               *logger.Logger
-	exporterMetrics   *metrics.ExporterMetrics // Optional: for internal health monitoring
+	tadoClient           TadoAPI
+	metricDescriptors    *metrics.MetricDescriptors
+	scrapeTimeout        time.Duration
+	homeID               string          // Optional: filter to specific home
+	excludedHomeIDs      map[string]bool // Optional: homes to skip
+	log                  *logger.Logger
+	exporterMetrics      *metrics.ExporterMetrics         // Optional: for internal health monitoring
+	strictMode           bool                             // Emit no Tado metrics if any collection error occurs
+	skipWeather          bool                             // Don't call GetWeather; weather metrics are left unset
+	exposeEmail          bool                             // Expose the raw account email on tado_exporter_account_info
+	maxLabelLength       int                              // Maximum length of user-controlled label values
+	allowNoHomes         bool                             // Treat an account without homes as valid rather than an error
+	temperatureUnits     map[metrics.TemperatureUnit]bool // Units the temperature metrics are exported in
+	temperaturePrecision int                              // Decimal places temperatures are rounded to; negative disables rounding
+	deviceMetrics        metrics.DeviceMetricsLevel       // How much per-device detail is collected from GetDevices
+	tokenExpiry          func() (time.Time, bool)         // Optional: reports the current access token's expiry
+	clock                metrics.Clock                    // Tells the time for time-dependent metrics and the home failure cooldown
+
+	// Per-zone device calls, made for every zone on a bounded worker pool with a timeout per call
+	temperatureOffsets bool          // Call GetTemperatureOffset for each zone's leading device
+	zoneDeviceWorkers  int           // Maximum number of concurrent per-zone device calls
+	zoneDeviceTimeout  time.Duration // Timeout of each per-zone device call
+
+	// Homes failing homeFailureThreshold scrapes in a row are skipped for homeFailureCooldown (0 disables)
+	homeFailureThreshold int
+	homeFailureCooldown  time.Duration
+
+	// sharedExporterMetrics is set when exporterMetrics are recorded here but exposed from their own registry
+	sharedExporterMetrics bool
+	// accountExporterMetrics is set when exporterMetrics are one account's of a MultiAccountCollector:
+	// only the account's collection health metrics are exposed here
+	accountExporterMetrics bool
+
+	mu                   sync.Mutex
+	lastScrapeDuration   time.Duration // Duration of the most recent scrape
+	lastSuccessfulScrape time.Time     // Completion time of the most recent error-free scrape
+	lastCollectStart     time.Time     // Start time of the most recent Collect call
+	lastFetchStart       time.Time     // Start time of the most recent fetch, by a scrape or RefreshIfStale
+	lastFetchFailed      bool          // Whether the most recent fetch failed
+	lastSlowScrapeWarn   time.Time     // When the last slow scrape warning was logged
+
+	// homeFailures holds the consecutive failures of each home ID, guarded by mu
+	homeFailures map[string]*homeFailures
+
+	// trackedZones holds the label values exported for each zone ID per home ID, so the series of zones
+	// removed from a home, or of a zone's previous name or type, can be evicted
+	trackedZones map[string]map[string][]string
+
+	// inFlight is the collection currently fetching from the Tado API, joined by concurrent scrapes
+	// so that they share one round-trip instead of each calling the API; guarded by inFlightMu
+	inFlightMu sync.Mutex
+	inFlight   *sharedCollection
+}
+
+// sharedCollection is a fetch from the Tado API whose outcome is shared by every scrape that joined it
+type sharedCollection struct {
+	deadline time.Time     // The fetching scrape's deadline, zero if it has none
+	done     chan struct{} // Closed once the fetch has finished and err is set
+	err      error
+}
+
+// outlasts reports whether the fetch runs at least as long as a scrape with ctx may wait for it,
+// i.e. its deadline is no earlier than ctx's
+func (f *sharedCollection) outlasts(ctx context.Context) bool {
+	if f.deadline.IsZero() {
+		return true
+	}
+	deadline, ok := ctx.Deadline()
+	return ok && !deadline.After(f.deadline)
 }
 
 func NewTadoCollector(
@@ -57,12 +147,20 @@ func NewTadoCollectorWithLogger(
 	}
 
 	return &TadoCollector{
-		tadoClient:        tadoClient,
-		metricDescriptors: metricDescriptors,
-		scrapeTimeout:     scrapeTimeout,
-		homeID:            homeID,
-		log:               log,
-		exporterMetrics:   nil, // Will be set separately if needed
+		tadoClient:           tadoClient,
+		metricDescriptors:    metricDescriptors,
+		scrapeTimeout:        scrapeTimeout,
+		homeID:               homeID,
+		log:                  log,
+		exporterMetrics:      nil, // Will be set separately if needed
+		maxLabelLength:       DefaultMaxLabelLength,
+		temperatureUnits:     temperatureUnitSet(metrics.DefaultTemperatureUnits),
+		temperaturePrecision: -1,
+		deviceMetrics:        metrics.DefaultDeviceMetricsLevel,
+		zoneDeviceWorkers:    DefaultZoneDeviceWorkers,
+		zoneDeviceTimeout:    DefaultZoneDeviceTimeout,
+		homeFailureCooldown:  DefaultHomeFailureCooldown,
+		clock:                metrics.RealClock,
 	}
 }
 
@@ -72,99 +170,656 @@ func (tc *TadoCollector) WithExporterMetrics(em *metrics.ExporterMetrics) *TadoC
 	return tc
 }
 
+// WithSeparateExporterMetrics records exporter health metrics into em without exposing them,
+// for serving em from its own registry
+func (tc *TadoCollector) WithSeparateExporterMetrics(em *metrics.ExporterMetrics) *TadoCollector {
+	return tc.withSharedExporterMetrics(em)
+}
+
+// withSharedExporterMetrics records into em without exposing it, for serving em from its own registry
+func (tc *TadoCollector) withSharedExporterMetrics(em *metrics.ExporterMetrics) *TadoCollector {
+	tc.exporterMetrics = em
+	tc.sharedExporterMetrics = true
+	return tc
+}
+
+// withAccountExporterMetrics records the account's collection health into em and exposes it, leaving
+// the process-wide exporter metrics to a MultiAccountCollector
+func (tc *TadoCollector) withAccountExporterMetrics(em *metrics.ExporterMetrics) *TadoCollector {
+	tc.exporterMetrics = em
+	tc.accountExporterMetrics = true
+	return tc
+}
+
+// WithStrictMode makes any collection error fail the whole scrape: Collect then
+// emits only exporter health metrics instead of partial Tado metrics
+func (tc *TadoCollector) WithStrictMode(strict bool) *TadoCollector {
+	tc.strictMode = strict
+	return tc
+}
+
+// WithSkipWeather skips weather collection, saving one GetWeather call per home per scrape
+// The weather metrics (solar intensity, daytime, outside temperature) are then never set
+func (tc *TadoCollector) WithSkipWeather(skip bool) *TadoCollector {
+	tc.skipWeather = skip
+	return tc
+}
+
+// WithExposeAccountEmail adds the raw account email to tado_exporter_account_info
+// By default only a hash of the email is exposed, keeping personal data out of the metrics
+func (tc *TadoCollector) WithExposeAccountEmail(expose bool) *TadoCollector {
+	tc.exposeEmail = expose
+	return tc
+}
+
+// WithMaxLabelLength sets the maximum length of user-controlled label values such as zone names
+// Longer values are truncated; 0 disables truncation
+func (tc *TadoCollector) WithMaxLabelLength(maxLength int) *TadoCollector {
+	tc.maxLabelLength = maxLength
+	return tc
+}
+
+// WithAllowNoHomes treats an account without homes as a valid, error-free state instead of an
+// authentication failure, for exporters deployed before the home is set up
+func (tc *TadoCollector) WithAllowNoHomes(allow bool) *TadoCollector {
+	tc.allowNoHomes = allow
+	return tc
+}
+
+// WithTemperatureOffsets collects the temperature offset of each zone's leading device, which costs one
+// Tado API call per zone and scrape
+func (tc *TadoCollector) WithTemperatureOffsets(enabled bool) *TadoCollector {
+	tc.temperatureOffsets = enabled
+	return tc
+}
+
+// WithDeviceMetrics sets how much per-device detail is collected from the devices GetDevices already returns:
+// none, the battery and connection state, or additionally the firmware version
+func (tc *TadoCollector) WithDeviceMetrics(level metrics.DeviceMetricsLevel) *TadoCollector {
+	tc.deviceMetrics = level
+	return tc
+}
+
+// WithZoneDeviceCalls makes at most workers per-zone device calls at once, each timing out after timeout
+// Non-positive values keep the defaults
+func (tc *TadoCollector) WithZoneDeviceCalls(workers int, timeout time.Duration) *TadoCollector {
+	if workers > 0 {
+		tc.zoneDeviceWorkers = workers
+	}
+	if timeout > 0 {
+		tc.zoneDeviceTimeout = timeout
+	}
+	return tc
+}
+
+// WithTemperatureUnits exports the temperature metrics in units only; the other units' series are not emitted
+// No units keeps metrics.DefaultTemperatureUnits
+func (tc *TadoCollector) WithTemperatureUnits(units []metrics.TemperatureUnit) *TadoCollector {
+	if len(units) > 0 {
+		tc.temperatureUnits = temperatureUnitSet(units)
+	}
+	return tc
+}
+
+// WithClock replaces the clock telling the time for time-dependent metrics, such as the token validity
+// and overlay countdowns, and for the home failure cooldown, so tests can control it
+func (tc *TadoCollector) WithClock(clock metrics.Clock) *TadoCollector {
+	tc.clock = clock
+	return tc
+}
+
+// WithTemperaturePrecision rounds temperature metrics to decimals decimal places before they are set
+// A negative value disables rounding, the default
+func (tc *TadoCollector) WithTemperaturePrecision(decimals int) *TadoCollector {
+	tc.temperaturePrecision = decimals
+	return tc
+}
+
+// roundTemperature rounds a temperature to the configured precision
+func (tc *TadoCollector) roundTemperature(value float64) float64 {
+	if tc.temperaturePrecision < 0 {
+		return value
+	}
+	scale := math.Pow10(tc.temperaturePrecision)
+	return math.Round(value*scale) / scale
+}
+
+// temperatureUnitSet returns units as a set
+func temperatureUnitSet(units []metrics.TemperatureUnit) map[metrics.TemperatureUnit]bool {
+	set := make(map[metrics.TemperatureUnit]bool, len(units))
+	for _, unit := range units {
+		set[unit] = true
+	}
+	return set
+}
+
+// WithExcludedHomeIDs skips the given homes, applied after the home ID filter
+func (tc *TadoCollector) WithExcludedHomeIDs(homeIDs []string) *TadoCollector {
+	tc.excludedHomeIDs = make(map[string]bool, len(homeIDs))
+	for _, homeID := range homeIDs {
+		tc.excludedHomeIDs[homeID] = true
+	}
+	return tc
+}
+
+// WithTokenExpiry reports the current access token's expiry as tado_exporter_token_valid_seconds on each scrape
+// tokenExpiry returns false while the expiry is unknown
+func (tc *TadoCollector) WithTokenExpiry(tokenExpiry func() (time.Time, bool)) *TadoCollector {
+	tc.tokenExpiry = tokenExpiry
+	return tc
+}
+
+// RegisterWith registers the collector, and with it the Tado and exporter metrics it emits, with registerer
+// Call it after the collector is configured: metrics added by later builder calls would not be described
+// Library users can pass their own registry to keep the exporter off the Prometheus default registry
+func (tc *TadoCollector) RegisterWith(registerer prometheus.Registerer) error {
+	return registerer.Register(tc)
+}
+
+// includesHome reports whether homeID passes the home ID filter and exclude list
+func (tc *TadoCollector) includesHome(homeID string) bool {
+	if tc.homeID != "" && homeID != tc.homeID {
+		return false
+	}
+	return !tc.excludedHomeIDs[homeID]
+}
+
+// HomeIDs returns the IDs of the homes this collector exports, honouring the home ID filter and exclude list
+func (tc *TadoCollector) HomeIDs(ctx context.Context) ([]string, error) {
+	user, err := tc.tadoClient.GetMe(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve user information: %w", err)
+	}
+	if user.Homes == nil {
+		return nil, errMissingHomes
+	}
+
+	var homeIDs []string
+	for _, userHome := range *user.Homes {
+		if userHome.Id == nil {
+			continue
+		}
+		homeIDStr := fmt.Sprintf("%d", *userHome.Id)
+		if !tc.includesHome(homeIDStr) {
+			continue
+		}
+		homeIDs = append(homeIDs, homeIDStr)
+	}
+	return homeIDs, nil
+}
+
+// ForHome returns a collector restricted to homeID with its own, unregistered metric descriptors
+// so it can be served from an isolated registry. Exporter health metrics are not attached.
+func (tc *TadoCollector) ForHome(homeID string) (*TadoCollector, error) {
+	metricDescs, err := metrics.NewMetricDescriptorsUnregisteredWithLabels(tc.metricDescriptors.Compat(), tc.metricDescriptors.ConstLabels())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metric descriptors for home %s: %w", homeID, err)
+	}
+
+	return NewTadoCollectorWithLogger(tc.tadoClient, metricDescs, tc.scrapeTimeout, homeID, tc.log).
+		WithStrictMode(tc.strictMode).
+		WithSkipWeather(tc.skipWeather).
+		WithExposeAccountEmail(tc.exposeEmail).
+		WithMaxLabelLength(tc.maxLabelLength).
+		WithAllowNoHomes(tc.allowNoHomes).
+		WithTemperatureUnits(tc.temperatureUnitList()).
+		WithTemperaturePrecision(tc.temperaturePrecision).
+		WithDeviceMetrics(tc.deviceMetrics).
+		WithTemperatureOffsets(tc.temperatureOffsets).
+		WithZoneDeviceCalls(tc.zoneDeviceWorkers, tc.zoneDeviceTimeout).
+		WithHomeFailureSkip(tc.homeFailureThreshold, tc.homeFailureCooldown).
+		WithClock(tc.clock), nil
+}
+
+// temperatureUnitList returns the units the temperature metrics are exported in
+func (tc *TadoCollector) temperatureUnitList() []metrics.TemperatureUnit {
+	units := make([]metrics.TemperatureUnit, 0, len(tc.temperatureUnits))
+	for unit := range tc.temperatureUnits {
+		units = append(units, unit)
+	}
+	return units
+}
+
+// LastScrapeDuration returns the duration of the most recent scrape
+func (tc *TadoCollector) LastScrapeDuration() time.Duration {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return tc.lastScrapeDuration
+}
+
+// LastSuccessfulScrape returns when the most recent error-free scrape completed
+// The zero time is returned if no scrape has succeeded yet
+func (tc *TadoCollector) LastSuccessfulScrape() time.Time {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return tc.lastSuccessfulScrape
+}
+
 func (tc *TadoCollector) Describe(ch chan<- *prometheus.Desc) {
 	// Home-level metrics
 	tc.metricDescriptors.IsResidentPresent.Describe(ch)
+	tc.metricDescriptors.HomePresenceLocked.Describe(ch)
+	tc.metricDescriptors.HomePresenceState.Describe(ch)
+	tc.metricDescriptors.HomeBridgeConnected.Describe(ch)
+	tc.metricDescriptors.HomeDevicesTotal.Describe(ch)
+	tc.metricDescriptors.HomeDevicesAtHomeTotal.Describe(ch)
 	tc.metricDescriptors.SolarIntensityPercentage.Describe(ch)
+	tc.metricDescriptors.WeatherIsDaytime.Describe(ch)
 	tc.metricDescriptors.TemperatureOutsideCelsius.Describe(ch)
 	tc.metricDescriptors.TemperatureOutsideFahrenheit.Describe(ch)
 
+	// Device-level metrics
+	tc.metricDescriptors.DeviceBatteryLow.Describe(ch)
+	tc.metricDescriptors.DeviceConnected.Describe(ch)
+	tc.metricDescriptors.DeviceFirmwareInfo.Describe(ch)
+
 	// Zone-level metrics
 	tc.metricDescriptors.TemperatureMeasuredCelsius.Describe(ch)
 	tc.metricDescriptors.TemperatureMeasuredFahrenheit.Describe(ch)
+	tc.metricDescriptors.TemperatureMeasuredKelvin.Describe(ch)
 	tc.metricDescriptors.HumidityMeasuredPercentage.Describe(ch)
 	tc.metricDescriptors.TemperatureSetCelsius.Describe(ch)
 	tc.metricDescriptors.TemperatureSetFahrenheit.Describe(ch)
 	tc.metricDescriptors.HeatingPowerPercentage.Describe(ch)
 	tc.metricDescriptors.IsWindowOpen.Describe(ch)
 	tc.metricDescriptors.IsZonePowered.Describe(ch)
+	tc.metricDescriptors.ZoneDataPresent.Describe(ch)
+	tc.metricDescriptors.ZoneACMode.Describe(ch)
+	tc.metricDescriptors.ZoneACPower.Describe(ch)
+	tc.metricDescriptors.ZoneACFanSpeed.Describe(ch)
+	tc.metricDescriptors.ZoneIndoorOutdoorDelta.Describe(ch)
+	tc.metricDescriptors.ZoneChildLockEnabled.Describe(ch)
+	tc.metricDescriptors.ZoneDazzleModeEnabled.Describe(ch)
+	tc.metricDescriptors.HotWaterOverlayActive.Describe(ch)
+	tc.metricDescriptors.ZoneDevicesTotal.Describe(ch)
+	tc.metricDescriptors.ZoneTemperatureOffsetCelsius.Describe(ch)
+	tc.metricDescriptors.ZoneFrostProtectionActive.Describe(ch)
+	tc.metricDescriptors.ZoneOverlayRemainingSeconds.Describe(ch)
 
 	// Exporter health metrics if configured
-	if tc.exporterMetrics != nil {
+	if tc.exporterMetrics != nil && !tc.sharedExporterMetrics {
 		tc.exporterMetrics.ScrapeDurationSeconds.Describe(ch)
 		tc.exporterMetrics.ScrapeErrorsTotal.Describe(ch)
-		tc.exporterMetrics.BuildInfo.Describe(ch)
 		tc.exporterMetrics.AuthenticationValid.Describe(ch)
 		tc.exporterMetrics.AuthenticationErrorsTotal.Describe(ch)
 		tc.exporterMetrics.LastAuthenticationSuccessUnix.Describe(ch)
+		tc.exporterMetrics.ScrapeBudgetUsedRatio.Describe(ch)
+		tc.exporterMetrics.ZonesObserved.Describe(ch)
+		tc.exporterMetrics.ClockSkewSeconds.Describe(ch)
+		tc.exporterMetrics.TokenRefreshesTotal.Describe(ch)
+		tc.exporterMetrics.HomeCollectionDurationSeconds.Describe(ch)
+		tc.exporterMetrics.TokenValidSeconds.Describe(ch)
+		tc.exporterMetrics.CircuitBreakerOpenSeconds.Describe(ch)
+		tc.exporterMetrics.ObservedScrapeIntervalSeconds.Describe(ch)
+		tc.exporterMetrics.ResponseParseErrorsTotal.Describe(ch)
+		tc.exporterMetrics.CollectorPanicsTotal.Describe(ch)
+		tc.exporterMetrics.ScrapeSuccess.Describe(ch)
+		tc.exporterMetrics.AccountInfo.Describe(ch)
+		tc.exporterMetrics.ThrottledRequestsTotal.Describe(ch)
+		tc.exporterMetrics.APIUnavailableTotal.Describe(ch)
+		tc.exporterMetrics.ZoneTimeBudgetSeconds.Describe(ch)
+		tc.exporterMetrics.LastErrorInfo.Describe(ch)
+		tc.exporterMetrics.HomesTotal.Describe(ch)
+		tc.exporterMetrics.TrackedSeriesTotal.Describe(ch)
+		tc.exporterMetrics.OldestZoneReadingUnix.Describe(ch)
+		tc.exporterMetrics.ZonesHeatingTotal.Describe(ch)
+		tc.exporterMetrics.RetriesTotal.Describe(ch)
+		tc.exporterMetrics.HomeSkipped.Describe(ch)
+		tc.exporterMetrics.DataAgeSeconds.Describe(ch)
+
+		// Process-wide metrics are exposed once by a MultiAccountCollector, not by each account
+		if !tc.accountExporterMetrics {
+			tc.exporterMetrics.BuildInfo.Describe(ch)
+			tc.exporterMetrics.HTTPConnectionsActive.Describe(ch)
+			tc.exporterMetrics.ScrapeRequestsTotal.Describe(ch)
+			tc.exporterMetrics.TokenFileError.Describe(ch)
+			tc.exporterMetrics.FeatureEnabled.Describe(ch)
+		}
 	}
 }
 
 // Collect is called by the Prometheus client when scraping /metrics
 // It fetches current metrics from Tado API and sends them to the channel
 func (tc *TadoCollector) Collect(ch chan<- prometheus.Metric) {
-	// Create context with timeout to prevent hanging requests
-	ctx, cancel := context.WithTimeout(context.Background(), tc.scrapeTimeout)
+	tc.collect(ch, tc.scrapeTimeout)
+}
+
+// RegisterWithScrapeTimeout registers the collector with registerer like RegisterWith, but its scrapes
+// time out after scrapeTimeout instead of the collector's own scrape timeout
+// Use it with a per-request registry to run a single scrape with a different timeout
+func (tc *TadoCollector) RegisterWithScrapeTimeout(registerer prometheus.Registerer, scrapeTimeout time.Duration) error {
+	return registerer.Register(&scrapeTimeoutCollector{tc: tc, scrapeTimeout: scrapeTimeout})
+}
+
+// RegisterLastValuesWith registers a collector sending the values of the most recent fetch with registerer,
+// without calling the Tado API. Use it to push metrics elsewhere without every push collecting them again
+func (tc *TadoCollector) RegisterLastValuesWith(registerer prometheus.Registerer) error {
+	return registerer.Register(&lastValuesCollector{tc: tc})
+}
+
+// RefreshIfStale fetches from the Tado API, sharing a fetch already in flight, unless a scrape or
+// refresh started one within maxAge. It records the scrape's success and errors like a scrape, but
+// not its duration or the scrape interval, so refreshing for pushes doesn't skew the scrape metrics
+func (tc *TadoCollector) RefreshIfStale(ctx context.Context, maxAge time.Duration) error {
+	startTime := time.Now()
+	tc.mu.Lock()
+	if !tc.lastFetchStart.IsZero() && startTime.Sub(tc.lastFetchStart) < maxAge {
+		tc.mu.Unlock()
+		return nil
+	}
+	tc.lastFetchStart = startTime
+	tc.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, tc.scrapeTimeout)
 	defer cancel()
+	ctx = logger.ContextWithRequestID(ctx, logger.NewRequestID())
+
+	fetchErr := tc.fetchShared(ctx)
+	if fetchErr != nil && tc.exporterMetrics != nil {
+		tc.exporterMetrics.IncrementScrapeErrors()
+	}
+
+	tc.mu.Lock()
+	tc.lastFetchFailed = fetchErr != nil
+	if fetchErr == nil {
+		tc.lastSuccessfulScrape = tc.clock.Now()
+	}
+	tc.mu.Unlock()
 
-	var startTime time.Time
 	if tc.exporterMetrics != nil {
-		startTime = time.Now()
+		tc.exporterMetrics.SetScrapeSuccess(fetchErr == nil)
+	}
+	return fetchErr
+}
+
+// lastValuesCollector sends a TadoCollector's most recently fetched values without fetching
+type lastValuesCollector struct {
+	tc *TadoCollector
+}
+
+func (c *lastValuesCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.tc.Describe(ch)
+}
+
+func (c *lastValuesCollector) Collect(ch chan<- prometheus.Metric) {
+	c.tc.mu.Lock()
+	lastFetchFailed := c.tc.lastFetchFailed
+	c.tc.mu.Unlock()
+
+	// In strict mode the values of a failed fetch are withheld, as on /metrics
+	c.tc.sendMetrics(ch, !lastFetchFailed || !c.tc.strictMode)
+}
+
+// scrapeTimeoutCollector collects a TadoCollector with a different scrape timeout
+type scrapeTimeoutCollector struct {
+	tc            *TadoCollector
+	scrapeTimeout time.Duration
+}
+
+func (c *scrapeTimeoutCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.tc.Describe(ch)
+}
+
+func (c *scrapeTimeoutCollector) Collect(ch chan<- prometheus.Metric) {
+	c.tc.collect(ch, c.scrapeTimeout)
+}
+
+// collect fetches current metrics from the Tado API, giving up after scrapeTimeout, and sends them to ch
+func (tc *TadoCollector) collect(ch chan<- prometheus.Metric, scrapeTimeout time.Duration) {
+	// Create context with timeout to prevent hanging requests
+	ctx, cancel := context.WithTimeout(context.Background(), scrapeTimeout)
+	defer cancel()
+
+	// Tag the scrape with a request ID so all of its log lines, including the
+	// client adapter's, can be correlated
+	ctx = logger.ContextWithRequestID(ctx, logger.NewRequestID())
+
+	startTime := time.Now()
+
+	// The time since the previous Collect call approximates the scrape interval; unknown on the first call
+	tc.mu.Lock()
+	previousCollectStart := tc.lastCollectStart
+	tc.lastCollectStart = startTime
+	tc.lastFetchStart = startTime
+	tc.mu.Unlock()
+	if tc.exporterMetrics != nil && !previousCollectStart.IsZero() {
+		tc.exporterMetrics.SetObservedScrapeInterval(startTime.Sub(previousCollectStart))
 	}
 
-	// Fetch metrics from Tado API
-	if err := tc.fetchAndCollectMetrics(ctx); err != nil {
-		tc.log.Warn("Failed to collect Tado metrics", "error", err.Error())
+	// Fetch metrics from Tado API, sharing a fetch already in flight for a concurrent scrape
+	collectErr := tc.fetchShared(ctx)
+	if collectErr != nil {
+		tc.log.WarnContext(ctx, "Failed to collect Tado metrics", "error", collectErr.Error())
 		if tc.exporterMetrics != nil {
 			tc.exporterMetrics.IncrementScrapeErrors()
 		}
 		// Don't return - Prometheus will use last known values
 	}
 
+	duration := time.Since(startTime)
+	tc.mu.Lock()
+	tc.lastScrapeDuration = duration
+	tc.lastFetchFailed = collectErr != nil
+	if collectErr == nil {
+		tc.lastSuccessfulScrape = tc.clock.Now()
+	}
+	tc.mu.Unlock()
+	tc.warnIfScrapeNearTimeout(ctx, duration, scrapeTimeout)
+
 	if tc.exporterMetrics != nil {
-		duration := time.Since(startTime).Seconds()
-		tc.exporterMetrics.RecordScrapeDuration(duration)
+		tc.exporterMetrics.RecordScrapeDuration(duration.Seconds())
+		tc.exporterMetrics.SetScrapeSuccess(collectErr == nil)
+		tc.exporterMetrics.RecordScrapeBudgetUsed(duration, scrapeTimeout)
+
+		// Checked after fetching so a token refreshed during the scrape is reflected
+		if tc.tokenExpiry != nil {
+			if expiry, ok := tc.tokenExpiry(); ok {
+				tc.exporterMetrics.SetTokenValidity(expiry.Sub(tc.clock.Now()))
+			}
+		}
 	}
 
 	// Send collected metrics to channel
-	// Home-level metrics
-	tc.metricDescriptors.IsResidentPresent.Collect(ch)
-	tc.metricDescriptors.SolarIntensityPercentage.Collect(ch)
-	tc.metricDescriptors.TemperatureOutsideCelsius.Collect(ch)
-	tc.metricDescriptors.TemperatureOutsideFahrenheit.Collect(ch)
+	// In strict mode a failed scrape emits no Tado metrics, so the failure is visible
+	tc.sendMetrics(ch, collectErr == nil || !tc.strictMode)
+}
 
-	// Zone-level metrics
-	tc.metricDescriptors.TemperatureMeasuredCelsius.Collect(ch)
-	tc.metricDescriptors.TemperatureMeasuredFahrenheit.Collect(ch)
-	tc.metricDescriptors.HumidityMeasuredPercentage.Collect(ch)
-	tc.metricDescriptors.TemperatureSetCelsius.Collect(ch)
-	tc.metricDescriptors.TemperatureSetFahrenheit.Collect(ch)
-	tc.metricDescriptors.HeatingPowerPercentage.Collect(ch)
-	tc.metricDescriptors.IsWindowOpen.Collect(ch)
-	tc.metricDescriptors.IsZonePowered.Collect(ch)
+// sendMetrics sends the current metric values to ch without calling the Tado API
+// Tado metrics are only sent with sendTadoMetrics; exporter health metrics are sent if configured
+func (tc *TadoCollector) sendMetrics(ch chan<- prometheus.Metric, sendTadoMetrics bool) {
+	if sendTadoMetrics {
+		// Home-level metrics
+		tc.metricDescriptors.IsResidentPresent.Collect(ch)
+		tc.metricDescriptors.HomePresenceLocked.Collect(ch)
+		tc.metricDescriptors.HomePresenceState.Collect(ch)
+		tc.metricDescriptors.HomeBridgeConnected.Collect(ch)
+		tc.metricDescriptors.HomeDevicesTotal.Collect(ch)
+		tc.metricDescriptors.HomeDevicesAtHomeTotal.Collect(ch)
+		tc.metricDescriptors.SolarIntensityPercentage.Collect(ch)
+		tc.metricDescriptors.WeatherIsDaytime.Collect(ch)
+		if tc.temperatureUnits[metrics.TemperatureUnitCelsius] {
+			tc.metricDescriptors.TemperatureOutsideCelsius.Collect(ch)
+		}
+		if tc.temperatureUnits[metrics.TemperatureUnitFahrenheit] {
+			tc.metricDescriptors.TemperatureOutsideFahrenheit.Collect(ch)
+		}
+
+		// Device-level metrics
+		tc.metricDescriptors.DeviceBatteryLow.Collect(ch)
+		tc.metricDescriptors.DeviceConnected.Collect(ch)
+		tc.metricDescriptors.DeviceFirmwareInfo.Collect(ch)
+
+		// Zone-level metrics
+		if tc.temperatureUnits[metrics.TemperatureUnitCelsius] {
+			tc.metricDescriptors.TemperatureMeasuredCelsius.Collect(ch)
+		}
+		if tc.temperatureUnits[metrics.TemperatureUnitFahrenheit] {
+			tc.metricDescriptors.TemperatureMeasuredFahrenheit.Collect(ch)
+		}
+		if tc.temperatureUnits[metrics.TemperatureUnitKelvin] {
+			tc.metricDescriptors.TemperatureMeasuredKelvin.Collect(ch)
+		}
+		tc.metricDescriptors.HumidityMeasuredPercentage.Collect(ch)
+		if tc.temperatureUnits[metrics.TemperatureUnitCelsius] {
+			tc.metricDescriptors.TemperatureSetCelsius.Collect(ch)
+		}
+		if tc.temperatureUnits[metrics.TemperatureUnitFahrenheit] {
+			tc.metricDescriptors.TemperatureSetFahrenheit.Collect(ch)
+		}
+		tc.metricDescriptors.HeatingPowerPercentage.Collect(ch)
+		tc.metricDescriptors.IsWindowOpen.Collect(ch)
+		tc.metricDescriptors.IsZonePowered.Collect(ch)
+		tc.metricDescriptors.ZoneDataPresent.Collect(ch)
+		tc.metricDescriptors.ZoneACMode.Collect(ch)
+		tc.metricDescriptors.ZoneACPower.Collect(ch)
+		tc.metricDescriptors.ZoneACFanSpeed.Collect(ch)
+		tc.metricDescriptors.ZoneIndoorOutdoorDelta.Collect(ch)
+		tc.metricDescriptors.ZoneChildLockEnabled.Collect(ch)
+		tc.metricDescriptors.ZoneDazzleModeEnabled.Collect(ch)
+		tc.metricDescriptors.HotWaterOverlayActive.Collect(ch)
+		tc.metricDescriptors.ZoneDevicesTotal.Collect(ch)
+		tc.metricDescriptors.ZoneTemperatureOffsetCelsius.Collect(ch)
+		tc.metricDescriptors.ZoneFrostProtectionActive.Collect(ch)
+		tc.metricDescriptors.ZoneOverlayRemainingSeconds.Collect(ch)
+	}
 
 	// Send exporter health metrics to channel if configured
-	if tc.exporterMetrics != nil {
+	if tc.exporterMetrics != nil && !tc.sharedExporterMetrics {
 		tc.exporterMetrics.ScrapeDurationSeconds.Collect(ch)
 		tc.exporterMetrics.ScrapeErrorsTotal.Collect(ch)
-		tc.exporterMetrics.BuildInfo.Collect(ch)
 		tc.exporterMetrics.AuthenticationValid.Collect(ch)
 		tc.exporterMetrics.AuthenticationErrorsTotal.Collect(ch)
 		tc.exporterMetrics.LastAuthenticationSuccessUnix.Collect(ch)
+		tc.exporterMetrics.ScrapeBudgetUsedRatio.Collect(ch)
+		tc.exporterMetrics.ZonesObserved.Collect(ch)
+		tc.exporterMetrics.ClockSkewSeconds.Collect(ch)
+		tc.exporterMetrics.TokenRefreshesTotal.Collect(ch)
+		tc.exporterMetrics.HomeCollectionDurationSeconds.Collect(ch)
+		tc.exporterMetrics.TokenValidSeconds.Collect(ch)
+		tc.exporterMetrics.CircuitBreakerOpenSeconds.Collect(ch)
+		tc.exporterMetrics.ObservedScrapeIntervalSeconds.Collect(ch)
+		tc.exporterMetrics.ResponseParseErrorsTotal.Collect(ch)
+		tc.exporterMetrics.CollectorPanicsTotal.Collect(ch)
+		tc.exporterMetrics.ScrapeSuccess.Collect(ch)
+		tc.exporterMetrics.AccountInfo.Collect(ch)
+		tc.exporterMetrics.ThrottledRequestsTotal.Collect(ch)
+		tc.exporterMetrics.APIUnavailableTotal.Collect(ch)
+		tc.exporterMetrics.ZoneTimeBudgetSeconds.Collect(ch)
+		tc.exporterMetrics.LastErrorInfo.Collect(ch)
+		tc.exporterMetrics.HomesTotal.Collect(ch)
+		tc.exporterMetrics.TrackedSeriesTotal.Collect(ch)
+		tc.exporterMetrics.OldestZoneReadingUnix.Collect(ch)
+		tc.exporterMetrics.ZonesHeatingTotal.Collect(ch)
+		tc.exporterMetrics.RetriesTotal.Collect(ch)
+		tc.exporterMetrics.HomeSkipped.Collect(ch)
+		tc.exporterMetrics.DataAgeSeconds.Collect(ch)
+
+		// Process-wide metrics are exposed once by a MultiAccountCollector, not by each account
+		if !tc.accountExporterMetrics {
+			tc.exporterMetrics.BuildInfo.Collect(ch)
+			tc.exporterMetrics.HTTPConnectionsActive.Collect(ch)
+			tc.exporterMetrics.ScrapeRequestsTotal.Collect(ch)
+			tc.exporterMetrics.TokenFileError.Collect(ch)
+			tc.exporterMetrics.FeatureEnabled.Collect(ch)
+		}
+	}
+}
+
+// warnIfScrapeNearTimeout logs a warning suggesting a higher scrape timeout when duration came close
+// to or exceeded it, at most once per slowScrapeWarnInterval
+func (tc *TadoCollector) warnIfScrapeNearTimeout(ctx context.Context, duration, scrapeTimeout time.Duration) {
+	if scrapeTimeout <= 0 || duration.Seconds() < scrapeTimeout.Seconds()*slowScrapeWarnRatio {
+		return
 	}
+
+	now := tc.clock.Now()
+	tc.mu.Lock()
+	if !tc.lastSlowScrapeWarn.IsZero() && now.Sub(tc.lastSlowScrapeWarn) < slowScrapeWarnInterval {
+		tc.mu.Unlock()
+		return
+	}
+	tc.lastSlowScrapeWarn = now
+	tc.mu.Unlock()
+
+	tc.log.WarnContext(ctx, "Scrape took close to or longer than the scrape timeout, consider raising it",
+		"duration_seconds", duration.Seconds(),
+		"scrape_timeout_seconds", scrapeTimeout.Seconds())
+}
+
+// fetchAndCollectMetricsRecovering runs fetchAndCollectMetrics, turning a panic into an error
+// A malformed API response that slips past the nil checks then fails only this scrape, and
+// Collect still serves the last known values instead of the scrape failing opaquely
+func (tc *TadoCollector) fetchAndCollectMetricsRecovering(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			tc.log.ErrorContext(ctx, "Recovered from panic while collecting Tado metrics", "panic", fmt.Sprint(r), "stack", string(debug.Stack()))
+			if tc.exporterMetrics != nil {
+				tc.exporterMetrics.IncrementCollectorPanics()
+			}
+			err = fmt.Errorf("panic while collecting metrics: %v", r)
+		}
+	}()
+
+	return tc.fetchAndCollectMetrics(ctx)
+}
+
+// fetchShared runs fetchAndCollectMetricsRecovering, unless another scrape's fetch is already in flight
+// and its deadline is no later than that scrape's own; the scrape then waits for that fetch and shares
+// its result, as both read the same metric values. A scrape allowed to run longer, e.g. one raising its
+// timeout with X-Scrape-Timeout, runs a separate fetch instead of inheriting the shorter deadline, and
+// becomes the fetch later scrapes join. A joining scrape gives up with its context's error if its own
+// deadline passes first
+func (tc *TadoCollector) fetchShared(ctx context.Context) error {
+	tc.inFlightMu.Lock()
+	if flight := tc.inFlight; flight != nil && flight.outlasts(ctx) {
+		tc.inFlightMu.Unlock()
+		tc.log.DebugContext(ctx, "Joining in-flight Tado API collection")
+		select {
+		case <-flight.done:
+			return flight.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	flight := &sharedCollection{done: make(chan struct{})}
+	if deadline, ok := ctx.Deadline(); ok {
+		flight.deadline = deadline
+	}
+	tc.inFlight = flight
+	tc.inFlightMu.Unlock()
+
+	flight.err = tc.fetchAndCollectMetricsRecovering(ctx)
+
+	tc.inFlightMu.Lock()
+	if tc.inFlight == flight {
+		tc.inFlight = nil
+	}
+	tc.inFlightMu.Unlock()
+	close(flight.done)
+	return flight.err
 }
 
 // fetchAndCollectMetrics fetches metrics from Tado API and updates metric values
 // This function continues collecting metrics even when individual API calls fail,
 // ensuring partial metrics are always available for alerting and monitoring.
 func (tc *TadoCollector) fetchAndCollectMetrics(ctx context.Context) error {
+	start := time.Now()
 	var collectionErrors []string
 
+	// Category of the scrape's last error for tado_exporter_last_error_info, empty if there was none
+	var lastErrorCategory string
+	defer func() { tc.recordLastError(lastErrorCategory) }()
+
 	// Get current user and homes
 	user, err := tc.tadoClient.GetMe(ctx)
 	if err != nil {
+		// Failing to fetch the user is treated as an authentication failure, unless it is clearly something else
+		lastErrorCategory = categorizeError(err)
+		if lastErrorCategory == errorCategoryAPI {
+			lastErrorCategory = errorCategoryAuth
+		}
 		errMsg := fmt.Sprintf("failed to fetch user: %v", err)
-		tc.log.Warn(errMsg)
+		tc.log.WarnContext(ctx, errMsg)
 		if tc.exporterMetrics != nil {
 			tc.exporterMetrics.IncrementScrapeErrors()
 			tc.exporterMetrics.IncrementAuthenticationErrors()
@@ -173,8 +828,29 @@ func (tc *TadoCollector) fetchAndCollectMetrics(ctx context.Context) error {
 		// Return early if we can't even get the list of homes
 		return fmt.Errorf("unable to retrieve user information: %w", err)
 	}
-	if user.Homes == nil || len(*user.Homes) == 0 {
-		tc.log.Warn("no homes found for user account")
+	if user.Homes == nil {
+		// An account without homes has an empty list; a missing list means the response is malformed
+		tc.log.WarnContext(ctx, "user response has no homes field")
+		lastErrorCategory = errorCategoryAPI
+		if tc.exporterMetrics != nil {
+			tc.exporterMetrics.IncrementResponseParseErrors()
+		}
+		return errMissingHomes
+	}
+	if len(*user.Homes) == 0 && tc.allowNoHomes {
+		// The account may simply not have a home set up yet: report it, but keep authentication valid
+		tc.log.InfoContext(ctx, "no homes found for user account, continuing as no homes are allowed")
+		if tc.exporterMetrics != nil {
+			tc.exporterMetrics.SetAuthenticationValid(true)
+			tc.exporterMetrics.RecordAuthenticationSuccess()
+			tc.exporterMetrics.SetHomesTotal(0)
+			tc.recordAccountInfo(user)
+		}
+		return nil
+	}
+	if len(*user.Homes) == 0 {
+		tc.log.WarnContext(ctx, "no homes found for user account")
+		lastErrorCategory = errorCategoryAuth
 		if tc.exporterMetrics != nil {
 			tc.exporterMetrics.IncrementAuthenticationErrors()
 			tc.exporterMetrics.SetAuthenticationValid(false)
@@ -186,202 +862,685 @@ func (tc *TadoCollector) fetchAndCollectMetrics(ctx context.Context) error {
 	if tc.exporterMetrics != nil {
 		tc.exporterMetrics.SetAuthenticationValid(true)
 		tc.exporterMetrics.RecordAuthenticationSuccess()
+		tc.recordAccountInfo(user)
 	}
 
 	homeCount := 0
 	homeErrorCount := 0
+	zoneCount := 0
+	zoneErrorCount := 0
+	heatingZoneCount := 0
+	var newestSensorTime, oldestSensorTime time.Time
 	for _, userHome := range *user.Homes {
 		homeID := userHome.Id
 		if homeID == nil {
 			continue
 		}
 
-		// Filter to specific home if specified
-		if tc.homeID != "" && fmt.Sprintf("%d", *homeID) != tc.homeID {
+		homeIDStr := fmt.Sprintf("%d", *homeID)
+
+		// Filter to specific home if specified, then drop excluded homes
+		// Note: tado.HomeBase only carries the home ID and name, so owned and
+		// shared (guest-access) homes cannot be told apart here; use homeID or
+		// the exclude list to restrict collection to particular homes instead.
+		if !tc.includesHome(homeIDStr) {
+			continue
+		}
+
+		// Skip homes that keep failing until their cooldown ends, leaving their series at the last values
+		if tc.skipFailingHome(ctx, homeIDStr) {
 			continue
 		}
 
 		homeCount++
-		homeIDStr := fmt.Sprintf("%d", *homeID)
+		homeStart := time.Now()
+		errorsBeforeHome := len(collectionErrors)
+		accessLost := false
 
 		// Collect home-level metrics - continue if fails
-		if err := tc.collectHomeMetrics(ctx, *homeID); err != nil {
+		// The outside temperature is passed on to the zones for the indoor-outdoor delta
+		outsideCelsius, err := tc.collectHomeMetrics(ctx, *homeID)
+		if err != nil {
 			homeErrorCount++
+			accessLost = accessLost || isHomeAccessLost(err)
 			errMsg := fmt.Sprintf("home metrics for %s: %v", homeIDStr, err)
-			tc.log.WithField("home_id", homeIDStr).Warn("Failed to collect home metrics", "error", err.Error())
+			tc.log.WarnContext(ctx, "Failed to collect home metrics", "home_id", homeIDStr, "error", err.Error())
 			collectionErrors = append(collectionErrors, errMsg)
+			lastErrorCategory = categorizeError(err)
 			// Continue to collect zone metrics even if home metrics fail
 		}
 
 		// Collect zone-level metrics - continue if fails
-		if err := tc.collectZoneMetrics(ctx, *homeID); err != nil {
+		summary, err := tc.collectZoneMetrics(ctx, *homeID, outsideCelsius)
+		zoneCount += summary.zoneCount
+		zoneErrorCount += summary.zoneErrorCount
+		heatingZoneCount += summary.heatingZoneCount
+		if summary.newestSensorTime.After(newestSensorTime) {
+			newestSensorTime = summary.newestSensorTime
+		}
+		if !summary.oldestSensorTime.IsZero() && (oldestSensorTime.IsZero() || summary.oldestSensorTime.Before(oldestSensorTime)) {
+			oldestSensorTime = summary.oldestSensorTime
+		}
+		if err != nil {
+			accessLost = accessLost || isHomeAccessLost(err)
 			errMsg := fmt.Sprintf("zone metrics for %s: %v", homeIDStr, err)
-			tc.log.WithField("home_id", homeIDStr).Warn("Failed to collect zone metrics", "error", err.Error())
+			tc.log.WarnContext(ctx, "Failed to collect zone metrics", "home_id", homeIDStr, "error", err.Error())
 			collectionErrors = append(collectionErrors, errMsg)
+			lastErrorCategory = categorizeError(err)
 			// Continue even if zone metrics fail
 		}
+
+		// Only lost access counts towards skipping the home, not e.g. a failing weather endpoint
+		tc.recordHomeOutcome(ctx, homeIDStr, accessLost)
+
+		if tc.exporterMetrics != nil {
+			tc.exporterMetrics.RecordHomeCollectionDuration(homeIDStr, time.Since(homeStart))
+			// Only a fully collected home counts as fresh data for tado_exporter_data_age_seconds
+			if len(collectionErrors) == errorsBeforeHome {
+				tc.exporterMetrics.RecordHomeDataFetched(homeIDStr)
+			}
+		}
+	}
+
+	if tc.exporterMetrics != nil {
+		tc.exporterMetrics.RecordZonesObserved(zoneCount)
+		tc.exporterMetrics.SetHomesTotal(homeCount)
+		tc.exporterMetrics.SetZonesHeating(heatingZoneCount)
+	}
+
+	if !newestSensorTime.IsZero() {
+		tc.recordClockSkew(ctx, newestSensorTime)
+	}
+	if !oldestSensorTime.IsZero() && tc.exporterMetrics != nil {
+		tc.exporterMetrics.SetOldestZoneReading(oldestSensorTime)
 	}
 
 	// If we collected from at least some homes, consider it a partial success
 	// Log warnings about failures but don't treat as a complete failure
 	if len(collectionErrors) > 0 {
-		tc.log.Warn("Scrape completed with errors",
+		tc.log.WarnContext(ctx, "Scrape completed with errors",
 			"total_homes", homeCount,
 			"homes_with_errors", homeErrorCount,
 			"error_count", len(collectionErrors))
 	}
 
+	tc.log.InfoContext(ctx, "Scrape completed",
+		"total_homes", homeCount,
+		"total_zones", zoneCount,
+		"errors", len(collectionErrors)+zoneErrorCount,
+		"duration_ms", time.Since(start).Milliseconds())
+
+	if tc.strictMode && (len(collectionErrors) > 0 || zoneErrorCount > 0) {
+		return fmt.Errorf("strict mode: scrape completed with %d home errors and %d zone errors", len(collectionErrors), zoneErrorCount)
+	}
+
 	return nil
 }
 
-// collectHomeMetrics collects home-level metrics (presence, weather)
-func (tc *TadoCollector) collectHomeMetrics(ctx context.Context, homeID tado.HomeId) error {
+// recordLastError exposes the category of the scrape's last error, or clears it if category is empty
+func (tc *TadoCollector) recordLastError(category string) {
+	if tc.exporterMetrics == nil {
+		return
+	}
+	if category == "" {
+		tc.exporterMetrics.ClearLastError()
+		return
+	}
+	tc.exporterMetrics.SetLastError(category)
+}
+
+// recordClockSkew records the difference between the newest sensor timestamp and the local clock,
+// warning when Tado's timestamps are ahead of the local clock
+func (tc *TadoCollector) recordClockSkew(ctx context.Context, newestSensorTime time.Time) {
+	skew := newestSensorTime.Sub(tc.clock.Now())
+	if skew > clockSkewWarnThreshold {
+		tc.log.WarnContext(ctx, "Tado sensor timestamps are ahead of the local clock, check NTP", "skew_seconds", skew.Seconds())
+	}
+
+	if tc.exporterMetrics != nil {
+		tc.exporterMetrics.SetClockSkew(skew)
+	}
+}
+
+// accountHashLength is the number of hex characters of the email hash kept in the account_hash label
+const accountHashLength = 16
+
+// recordAccountInfo records the authenticated account on tado_exporter_account_info
+// The account is identified by a hash of its email, or of its user ID if the email is missing
+func (tc *TadoCollector) recordAccountInfo(user *tado.User) {
+	var identity, email string
+	switch {
+	case user.Email != nil && *user.Email != "":
+		email = *user.Email
+		identity = strings.ToLower(email)
+	case user.Id != nil:
+		identity = user.Id.String()
+	default:
+		return
+	}
+
+	hash := sha256.Sum256([]byte(identity))
+	accountHash := hex.EncodeToString(hash[:])[:accountHashLength]
+
+	if !tc.exposeEmail {
+		email = ""
+	}
+	tc.exporterMetrics.SetAccountInfo(accountHash, sanitizeLabelValue(email, tc.maxLabelLength))
+}
+
+// bridgeDeviceType is the Tado device type of the internet bridge
+const bridgeDeviceType = "IB01"
+
+// recordBridgeConnected sets the bridge connectivity metric from the home's devices
+// A home with several bridges is only reported as connected if all of them are
+// If no bridge reports a connection state, the metric is left unchanged
+func (tc *TadoCollector) recordBridgeConnected(homeIDStr string, devices []tado.Device) {
+	var reported bool
+	connected := 1.0
+	for _, device := range devices {
+		if device.DeviceType == nil || *device.DeviceType != bridgeDeviceType {
+			continue
+		}
+		if device.ConnectionState == nil || device.ConnectionState.Value == nil {
+			continue
+		}
+		reported = true
+		if !*device.ConnectionState.Value {
+			connected = 0.0
+		}
+	}
+
+	if reported {
+		tc.metricDescriptors.HomeBridgeConnected.WithLabelValues(homeIDStr).Set(connected)
+	}
+}
+
+// recordDeviceMetrics sets the device-level metrics enabled by the device metrics level
+// Devices without a serial number are skipped, as are values a device doesn't report, e.g. the
+// battery state of mains-powered devices
+func (tc *TadoCollector) recordDeviceMetrics(homeIDStr string, devices []tado.Device) {
+	// Delete the home's series first so devices removed from it don't leave stale series behind
+	// Other homes' devices are left alone
+	homeLabels := prometheus.Labels{"home_id": homeIDStr}
+	tc.metricDescriptors.DeviceBatteryLow.DeletePartialMatch(homeLabels)
+	tc.metricDescriptors.DeviceConnected.DeletePartialMatch(homeLabels)
+	tc.metricDescriptors.DeviceFirmwareInfo.DeletePartialMatch(homeLabels)
+	if tc.deviceMetrics == metrics.DeviceMetricsOff {
+		return
+	}
+
+	for _, device := range devices {
+		if device.SerialNo == nil {
+			continue
+		}
+		deviceType := ""
+		if device.DeviceType != nil {
+			deviceType = string(*device.DeviceType)
+		}
+		labels := []string{homeIDStr, string(*device.SerialNo), deviceType}
+
+		if device.BatteryState != nil {
+			batteryLow := 0.0
+			if *device.BatteryState == tado.BatteryStateLOW {
+				batteryLow = 1.0
+			}
+			tc.metricDescriptors.DeviceBatteryLow.WithLabelValues(labels...).Set(batteryLow)
+		}
+		if device.ConnectionState != nil && device.ConnectionState.Value != nil {
+			connected := 0.0
+			if *device.ConnectionState.Value {
+				connected = 1.0
+			}
+			tc.metricDescriptors.DeviceConnected.WithLabelValues(labels...).Set(connected)
+		}
+		if tc.deviceMetrics == metrics.DeviceMetricsFull && device.CurrentFwVersion != nil {
+			tc.metricDescriptors.DeviceFirmwareInfo.WithLabelValues(append(labels, *device.CurrentFwVersion)...).Set(1)
+		}
+	}
+}
+
+// collectHomeMetrics collects home-level metrics (presence, weather, bridge connectivity, mobile devices at home)
+// It returns the outside temperature in Celsius, or nil if weather was skipped or not reported
+// Each endpoint is collected independently, so one failing doesn't freeze the others' metrics; their
+// errors are returned joined
+func (tc *TadoCollector) collectHomeMetrics(ctx context.Context, homeID tado.HomeId) (*float64, error) {
+	homeIDStr := fmt.Sprintf("%d", homeID)
+	var errs []error
+
 	homeState, err := tc.tadoClient.GetHomeState(ctx, homeID)
 	if err != nil {
-		return fmt.Errorf("failed to get home state: %w", err)
+		errs = append(errs, &homeAccessError{err: fmt.Errorf("failed to get home state: %w", err)})
+	} else if homeState != nil {
+		// Update resident presence metric
+		// Presence is "HOME" or "AWAY"; if it is missing the previous value is kept rather than
+		// reporting everyone as away
+		if homeState.Presence != nil {
+			presence := 0.0
+			if string(*homeState.Presence) == "HOME" {
+				presence = 1.0
+			}
+			tc.metricDescriptors.IsResidentPresent.Set(presence)
+
+			// Only the home's current state is kept, so its series changes label rather than piling up
+			// Other homes' states are left alone
+			tc.metricDescriptors.HomePresenceState.DeletePartialMatch(prometheus.Labels{"home_id": homeIDStr})
+			tc.metricDescriptors.HomePresenceState.WithLabelValues(homeIDStr, string(*homeState.Presence)).Set(1)
+		}
+
+		// Update presence lock metric
+		// presenceLocked is true when presence was set manually, overriding geofencing
+		var presenceLocked float64
+		if homeState.PresenceLocked != nil && *homeState.PresenceLocked {
+			presenceLocked = 1.0
+		}
+		tc.metricDescriptors.HomePresenceLocked.WithLabelValues(homeIDStr).Set(presenceLocked)
+	}
+
+	var outsideCelsius *float64
+	if !tc.skipWeather {
+		outsideCelsius, err = tc.collectWeatherMetrics(ctx, homeID)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Get devices (for the device count and internet bridge connectivity)
+	devices, err := tc.tadoClient.GetDevices(ctx, homeID)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to get devices: %w", err))
+	} else {
+		tc.metricDescriptors.HomeDevicesTotal.WithLabelValues(homeIDStr).Set(float64(len(devices)))
+		tc.recordBridgeConnected(homeIDStr, devices)
+		tc.recordDeviceMetrics(homeIDStr, devices)
 	}
 
-	if homeState != nil {
-		// Update resident presence metric
-		// Presence is "HOME" or "AWAY"
-		var presence float64
-		if homeState.Presence != nil && string(*homeState.Presence) == "HOME" {
-			presence = 1.0
-		} else {
-			presence = 0.0
+	// Get mobile devices (for the number of geofencing devices at home)
+	mobileDevices, err := tc.tadoClient.GetMobileDevices(ctx, homeID)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to get mobile devices: %w", err))
+	} else {
+		tc.metricDescriptors.HomeDevicesAtHomeTotal.WithLabelValues(homeIDStr).Set(float64(countMobileDevicesAtHome(mobileDevices)))
+	}
+
+	return outsideCelsius, errors.Join(errs...)
+}
+
+// countMobileDevicesAtHome counts the mobile devices whose geofencing location is at home
+// Devices without geo-tracking report no location and are not counted
+func countMobileDevicesAtHome(devices []tado.MobileDevice) int {
+	count := 0
+	for _, device := range devices {
+		if device.Location != nil && device.Location.AtHome != nil && *device.Location.AtHome {
+			count++
 		}
-		tc.metricDescriptors.IsResidentPresent.Set(presence)
 	}
+	return count
+}
 
-	// Get weather (for solar intensity and outside temperature)
+// collectWeatherMetrics collects the home's weather metrics (solar intensity, daytime, outside temperature)
+// It returns the outside temperature in Celsius, or nil if the weather did not report one
+func (tc *TadoCollector) collectWeatherMetrics(ctx context.Context, homeID tado.HomeId) (*float64, error) {
+	// Note: tado.Weather only carries outside temperature, solar intensity and the
+	// weather state; the API reports no outside humidity to export alongside them.
 	weather, err := tc.tadoClient.GetWeather(ctx, homeID)
 	if err != nil {
-		return fmt.Errorf("failed to get weather: %w", err)
+		return nil, fmt.Errorf("failed to get weather: %w", err)
 	}
 
+	var outsideCelsius *float64
 	if weather != nil {
 
-		// Update solar intensity metric
+		// Update solar intensity and daytime metrics
+		// The weather endpoint has no sunrise/sunset times, so any solar intensity is taken as daytime
 		if weather.SolarIntensity != nil && weather.SolarIntensity.Percentage != nil {
-			tc.metricDescriptors.SolarIntensityPercentage.Set(float64(*weather.SolarIntensity.Percentage))
+			solarIntensity := float64(*weather.SolarIntensity.Percentage)
+			tc.metricDescriptors.SolarIntensityPercentage.Set(solarIntensity)
+
+			var daytime float64
+			if solarIntensity > 0 {
+				daytime = 1.0
+			}
+			tc.metricDescriptors.WeatherIsDaytime.WithLabelValues(fmt.Sprintf("%d", homeID)).Set(daytime)
 		}
 
 		// Update outside temperature metrics
+		// Celsius is derived from Fahrenheit when only Fahrenheit is reported, so the Celsius series and
+		// the indoor-outdoor delta don't go missing
 		if weather.OutsideTemperature != nil {
 			if weather.OutsideTemperature.Celsius != nil {
-				tc.metricDescriptors.TemperatureOutsideCelsius.Set(float64(*weather.OutsideTemperature.Celsius))
+				celsius := float64(*weather.OutsideTemperature.Celsius)
+				outsideCelsius = &celsius
+			} else if weather.OutsideTemperature.Fahrenheit != nil {
+				celsius := metrics.FahrenheitToCelsius(float64(*weather.OutsideTemperature.Fahrenheit))
+				outsideCelsius = &celsius
+			}
+			if outsideCelsius != nil {
+				tc.metricDescriptors.TemperatureOutsideCelsius.Set(tc.roundTemperature(*outsideCelsius))
 			}
 			if weather.OutsideTemperature.Fahrenheit != nil {
-				tc.metricDescriptors.TemperatureOutsideFahrenheit.Set(float64(*weather.OutsideTemperature.Fahrenheit))
+				tc.metricDescriptors.TemperatureOutsideFahrenheit.Set(tc.roundTemperature(float64(*weather.OutsideTemperature.Fahrenheit)))
 			}
 		}
 	}
 
-	return nil
+	return outsideCelsius, nil
+}
+
+// zoneCollectionSummary describes the zones collected for a single home
+type zoneCollectionSummary struct {
+	zoneCount        int
+	zoneErrorCount   int
+	newestSensorTime time.Time // Zero if no zone reported a sensor timestamp
+	oldestSensorTime time.Time // Zero if no zone reported a sensor timestamp
+	heatingZoneCount int       // Zones with heating power above 0%
 }
 
 // collectZoneMetrics collects zone-level metrics (temperature, humidity, heating power, window status)
 // This function continues collecting metrics for each zone even if one zone fails,
 // ensuring partial metrics are available even if some zones have errors.
-func (tc *TadoCollector) collectZoneMetrics(ctx context.Context, homeID tado.HomeId) error {
+// outsideCelsius is the home's outside temperature, or nil if unknown
+func (tc *TadoCollector) collectZoneMetrics(ctx context.Context, homeID tado.HomeId, outsideCelsius *float64) (zoneCollectionSummary, error) {
+	var summary zoneCollectionSummary
+
 	zones, err := tc.tadoClient.GetZones(ctx, homeID)
 	if err != nil {
-		return fmt.Errorf("failed to get zones: %w", err)
+		return summary, &homeAccessError{err: fmt.Errorf("failed to get zones: %w", err)}
 	}
+	summary.zoneCount = len(zones)
+	tc.checkZoneTimeBudget(ctx, fmt.Sprintf("%d", homeID), len(zones))
 
 	zoneStates, err := tc.tadoClient.GetZoneStates(ctx, homeID)
 	if err != nil {
-		return fmt.Errorf("failed to get zone states: %w", err)
+		return summary, fmt.Errorf("failed to get zone states: %w", err)
 	}
 
 	if zoneStates == nil || zoneStates.ZoneStates == nil {
-		return fmt.Errorf("zone states are nil")
+		return summary, fmt.Errorf("zone states are nil")
 	}
 
 	homeIDStr := fmt.Sprintf("%d", homeID)
 	zoneCount := 0
 	zoneErrorCount := 0
 
+	// Zone IDs are only unique within a home, so the zone states map must come
+	// from this home's GetZoneStates call and duplicates within it are skipped.
+	seenZoneIDs := make(map[tado.ZoneId]bool, len(zones))
+	zoneLabels := make(map[string][]string, len(zones))
+	var deviceCalls []zoneDeviceCall
+
 	for _, zone := range zones {
-		if err := tc.collectSingleZoneMetrics(homeIDStr, zone, *zoneStates.ZoneStates); err != nil {
+		if zone.Id != nil {
+			if seenZoneIDs[*zone.Id] {
+				tc.log.WarnContext(ctx, "Duplicate zone ID in home, skipping", "home_id", homeIDStr, "zone_id", fmt.Sprintf("%d", *zone.Id))
+				continue
+			}
+			seenZoneIDs[*zone.Id] = true
+			zoneLabels[fmt.Sprintf("%d", *zone.Id)] = tc.zoneLabels(homeIDStr, zone)
+		}
+
+		zoneMetrics, err := tc.collectSingleZoneMetrics(ctx, homeIDStr, zone, *zoneStates.ZoneStates, outsideCelsius)
+		if err != nil {
 			zoneErrorCount++
-			tc.log.WithField("zone_id", fmt.Sprintf("%d", *zone.Id)).Warn("Failed to collect zone metrics", "error", err.Error())
+			tc.log.WarnContext(ctx, "Failed to collect zone metrics", "home_id", homeIDStr, "zone_id", zoneIDString(zone.Id), "error", err.Error())
+		} else {
+			if zoneMetrics.HeatingPowerPercentage != nil && *zoneMetrics.HeatingPowerPercentage > 0 {
+				summary.heatingZoneCount++
+			}
+			if tc.temperatureOffsets && zone.Id != nil {
+				if deviceID, ok := zoneLeaderDevice(zone); ok {
+					zoneIDStr := zoneIDString(zone.Id)
+					deviceCalls = append(deviceCalls, zoneDeviceCall{zoneID: zoneIDStr, labels: zoneLabels[zoneIDStr], deviceID: deviceID})
+				}
+			}
+			if ts := zoneMetrics.SensorTimestamp; ts != nil {
+				if ts.After(summary.newestSensorTime) {
+					summary.newestSensorTime = *ts
+				}
+				if summary.oldestSensorTime.IsZero() || ts.Before(summary.oldestSensorTime) {
+					summary.oldestSensorTime = *ts
+				}
+			}
 		}
 		zoneCount++
 	}
+	zoneErrorCount += tc.collectZoneDeviceMetrics(ctx, homeIDStr, deviceCalls)
+	summary.zoneCount = zoneCount
+	summary.zoneErrorCount = zoneErrorCount
+	tc.evictStaleZones(ctx, homeIDStr, zoneLabels)
 
 	if zoneErrorCount > 0 {
-		tc.log.Warn("Zone metrics collection completed with errors",
+		tc.log.WarnContext(ctx, "Zone metrics collection completed with errors",
 			"home_id", homeIDStr,
 			"total_zones", zoneCount,
 			"zones_with_errors", zoneErrorCount)
 	}
 
-	return nil
+	return summary, nil
 }
 
-// collectSingleZoneMetrics collects metrics for a single zone
-func (tc *TadoCollector) collectSingleZoneMetrics(homeIDStr string, zone tado.Zone, zoneStatesMap map[string]tado.ZoneState) error {
-	if zone.Id == nil {
-		return fmt.Errorf("zone ID is nil")
+// evictStaleZones deletes the series of zones tracked for a home that it no longer lists, and the old
+// series of zones whose labels changed, e.g. a zone reconfigured from HEATING to HOT_WATER
+// current maps the home's zone IDs to their label values; it is tracked as the home's zones
+// and the number of tracked (home_id, zone_id) combinations is recorded
+func (tc *TadoCollector) evictStaleZones(ctx context.Context, homeID string, current map[string][]string) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	for zoneID, previousLabels := range tc.trackedZones[homeID] {
+		currentLabels, ok := current[zoneID]
+		if !ok {
+			tc.metricDescriptors.DeleteZone(homeID, zoneID)
+			tc.log.InfoContext(ctx, "Zone no longer listed, removed its metrics", "home_id", homeID, "zone_id", zoneID)
+			continue
+		}
+		if !slices.Equal(previousLabels, currentLabels) {
+			tc.metricDescriptors.DeleteZoneSeries(previousLabels...)
+			tc.log.InfoContext(ctx, "Zone name or type changed, removed its previous metrics",
+				"home_id", homeID,
+				"zone_id", zoneID,
+				"previous_labels", strings.Join(previousLabels, ","))
+		}
 	}
 
-	zoneIDStr := fmt.Sprintf("%d", *zone.Id)
+	if tc.trackedZones == nil {
+		tc.trackedZones = make(map[string]map[string][]string)
+	}
+	tc.trackedZones[homeID] = current
 
-	zoneState, ok := zoneStatesMap[zoneIDStr]
-	if !ok {
-		return fmt.Errorf("zone state not found in map")
+	if tc.exporterMetrics != nil {
+		tracked := 0
+		for _, zones := range tc.trackedZones {
+			tracked += len(zones)
+		}
+		tc.exporterMetrics.SetTrackedSeries(tracked)
+	}
+}
+
+// checkZoneTimeBudget records the remaining scrape time per zone of a home, warning when it is low
+// Nothing is recorded for homes without zones or when ctx has no deadline
+func (tc *TadoCollector) checkZoneTimeBudget(ctx context.Context, homeID string, zoneCount int) {
+	deadline, ok := ctx.Deadline()
+	if !ok || zoneCount == 0 {
+		return
+	}
+
+	budget := time.Until(deadline) / time.Duration(zoneCount)
+	if budget < 0 {
+		budget = 0
+	}
+	if tc.exporterMetrics != nil {
+		tc.exporterMetrics.SetZoneTimeBudget(homeID, budget)
+	}
+	if budget < zoneTimeBudgetWarnThreshold {
+		tc.log.WarnContext(ctx, "Scrape time budget per zone is low, consider raising the scrape timeout",
+			"home_id", homeID,
+			"zones", zoneCount,
+			"budget_per_zone_seconds", budget.Seconds(),
+			"scrape_timeout_seconds", tc.scrapeTimeout.Seconds())
+	}
+}
+
+// zoneIDString formats a zone ID for logging, tolerating a nil ID
+func zoneIDString(zoneID *tado.ZoneId) string {
+	if zoneID == nil {
+		return "unknown"
 	}
+	return fmt.Sprintf("%d", *zoneID)
+}
 
-	zoneName := zone.Name
-	if zoneName == nil {
-		zoneName = &[]string{"unknown"}[0]
+// zoneLabels returns the label values of a zone's metrics; zone.Id must not be nil
+func (tc *TadoCollector) zoneLabels(homeIDStr string, zone tado.Zone) []string {
+	zoneName := "unknown"
+	if zone.Name != nil {
+		zoneName = *zone.Name
 	}
 	zoneType := ""
 	if zone.Type != nil {
 		zoneType = string(*zone.Type)
 	}
+	return zoneLabelValues(homeIDStr, fmt.Sprintf("%d", *zone.Id), sanitizeLabelValue(zoneName, tc.maxLabelLength), sanitizeLabelValue(zoneType, tc.maxLabelLength))
+}
+
+// collectSingleZoneMetrics collects metrics for a single zone
+// zoneStatesMap must be the zone states of the home identified by homeIDStr
+func (tc *TadoCollector) collectSingleZoneMetrics(ctx context.Context, homeIDStr string, zone tado.Zone, zoneStatesMap map[string]tado.ZoneState, outsideCelsius *float64) (*ZoneMetrics, error) {
+	if zone.Id == nil {
+		return nil, fmt.Errorf("zone ID is nil")
+	}
+
+	zoneIDStr := fmt.Sprintf("%d", *zone.Id)
+	labels := tc.zoneLabels(homeIDStr, zone)
+	tc.recordZoneSettingsMetrics(labels, zone)
+	tc.recordZoneDevicesMetric(labels, zone)
+
+	zoneState, ok := zoneStatesMap[zoneIDStr]
+	if !ok {
+		// GetZones and GetZoneStates can briefly disagree, e.g. right after a device is added or
+		// removed. Report the zone as having no data instead of failing it, leaving its other
+		// series at their last values.
+		tc.log.DebugContext(ctx, "Zone missing from zone states, reporting no data", "home_id", homeIDStr, "zone_id", zoneIDStr)
+		metrics := &ZoneMetrics{}
+		tc.recordZoneDataPresentMetric(labels, metrics)
+		return metrics, nil
+	}
 
-	metrics := ExtractAllZoneMetrics(&zoneState)
+	metrics := ExtractAllZoneMetrics(&zoneState, tc.clock.Now())
 
 	validationErrors := ValidateZoneMetrics(metrics)
 	if len(validationErrors) > 0 {
 		for _, err := range validationErrors {
-			tc.log.WithField("zone_id", zoneIDStr).Warn("Zone metric validation failed", "error", err.Error())
+			tc.log.WarnContext(ctx, "Zone metric validation failed", "zone_id", zoneIDStr, "error", err.Error())
 		}
 	}
 
-	labels := []string{homeIDStr, zoneIDStr, *zoneName, zoneType}
-	tc.recordMeasuredTemperatureMetrics(zoneIDStr, labels, metrics)
-	tc.recordMeasuredHumidityMetric(zoneIDStr, labels, metrics)
-	tc.recordTargetTemperatureMetrics(zoneIDStr, labels, metrics)
-	tc.recordHeatingPowerMetric(zoneIDStr, labels, metrics)
+	tc.recordMeasuredTemperatureMetrics(ctx, zoneIDStr, labels, metrics)
+	tc.recordIndoorOutdoorDeltaMetric(labels, metrics, outsideCelsius)
+	tc.recordMeasuredHumidityMetric(ctx, zoneIDStr, labels, metrics)
+	tc.recordTargetTemperatureMetrics(ctx, zoneIDStr, labels, metrics)
+	tc.recordHeatingPowerMetric(ctx, zoneIDStr, labels, metrics)
 	tc.recordWindowStatusMetric(labels, metrics)
 	tc.recordZonePoweredStatusMetric(labels, metrics)
+	tc.recordZoneDataPresentMetric(labels, metrics)
+	tc.recordAirConditioningMetrics(labels, metrics)
+	tc.recordHotWaterMetrics(labels, metrics)
+	tc.recordFrostProtectionMetric(labels, metrics)
+	tc.recordOverlayRemainingMetric(labels, metrics)
 
-	return nil
+	return metrics, nil
+}
+
+// recordZoneSettingsMetrics records the child lock and dazzle mode settings from the zone's details
+// Each is skipped when the zone does not report it, e.g. for devices without the setting
+func (tc *TadoCollector) recordZoneSettingsMetrics(labels []string, zone tado.Zone) {
+	if childLock, ok := zoneChildLockEnabled(zone); ok {
+		childLockEnabled := 0.0
+		if childLock {
+			childLockEnabled = 1.0
+		}
+		tc.metricDescriptors.ZoneChildLockEnabled.WithLabelValues(labels...).Set(childLockEnabled)
+	}
+
+	dazzle := zone.DazzleEnabled
+	if zone.DazzleMode != nil && zone.DazzleMode.Enabled != nil {
+		dazzle = zone.DazzleMode.Enabled
+	}
+	if dazzle != nil {
+		dazzleEnabled := 0.0
+		if *dazzle {
+			dazzleEnabled = 1.0
+		}
+		tc.metricDescriptors.ZoneDazzleModeEnabled.WithLabelValues(labels...).Set(dazzleEnabled)
+	}
+}
+
+// recordZoneDevicesMetric records the number of devices assigned to the zone
+// tado.Device carries no zone, so the devices come from the zone details returned by GetZones
+// rather than GetDevices; nothing is recorded if the zone details list no devices
+func (tc *TadoCollector) recordZoneDevicesMetric(labels []string, zone tado.Zone) {
+	if zone.Devices == nil {
+		return
+	}
+	tc.metricDescriptors.ZoneDevicesTotal.WithLabelValues(labels...).Set(float64(len(*zone.Devices)))
+}
+
+// zoneChildLockEnabled reports whether child lock is enabled on any of the zone's devices
+// ok is false if none of the devices report a child lock setting
+func zoneChildLockEnabled(zone tado.Zone) (enabled bool, ok bool) {
+	if zone.Devices == nil {
+		return false, false
+	}
+	for _, device := range *zone.Devices {
+		if device.ChildLockEnabled == nil {
+			continue
+		}
+		ok = true
+		enabled = enabled || *device.ChildLockEnabled
+	}
+	return enabled, ok
 }
 
 // recordMeasuredTemperatureMetrics records both Celsius and Fahrenheit measured temperatures
-func (tc *TadoCollector) recordMeasuredTemperatureMetrics(zoneIDStr string, labels []string, metrics *ZoneMetrics) {
-	if metrics.MeasuredTemperatureCelsius != nil {
-		if err := validateTemperature(*metrics.MeasuredTemperatureCelsius, "measured_temperature_celsius"); err != nil {
-			tc.log.WithField("zone_id", zoneIDStr).Warn("Invalid measured temperature, skipping metric", "value", *metrics.MeasuredTemperatureCelsius, "error", err.Error())
+func (tc *TadoCollector) recordMeasuredTemperatureMetrics(ctx context.Context, zoneIDStr string, labels []string, zoneMetrics *ZoneMetrics) {
+	if zoneMetrics.UnexpectedTemperatureType != "" {
+		tc.log.WarnContext(ctx, "Unexpected measured temperature type, skipping metric", "zone_id", zoneIDStr, "type", zoneMetrics.UnexpectedTemperatureType)
+		return
+	}
+
+	if zoneMetrics.MeasuredTemperatureCelsius != nil {
+		if err := validateTemperature(*zoneMetrics.MeasuredTemperatureCelsius, "measured_temperature_celsius"); err != nil {
+			tc.log.WarnContext(ctx, "Invalid measured temperature, skipping metric", "zone_id", zoneIDStr, "value", *zoneMetrics.MeasuredTemperatureCelsius, "error", err.Error())
 		} else {
-			tc.metricDescriptors.TemperatureMeasuredCelsius.WithLabelValues(labels...).Set(float64(*metrics.MeasuredTemperatureCelsius))
+			celsius := float64(*zoneMetrics.MeasuredTemperatureCelsius)
+			tc.metricDescriptors.TemperatureMeasuredCelsius.WithLabelValues(labels...).Set(tc.roundTemperature(celsius))
+			if tc.temperatureUnits[metrics.TemperatureUnitKelvin] {
+				tc.metricDescriptors.TemperatureMeasuredKelvin.WithLabelValues(labels...).Set(tc.roundTemperature(metrics.CelsiusToKelvin(celsius)))
+			}
 		}
 	}
 
-	if metrics.MeasuredTemperatureFahrenheit != nil {
-		tc.metricDescriptors.TemperatureMeasuredFahrenheit.WithLabelValues(labels...).Set(float64(*metrics.MeasuredTemperatureFahrenheit))
+	if zoneMetrics.MeasuredTemperatureFahrenheit != nil {
+		tc.metricDescriptors.TemperatureMeasuredFahrenheit.WithLabelValues(labels...).Set(tc.roundTemperature(float64(*zoneMetrics.MeasuredTemperatureFahrenheit)))
+	}
+}
+
+// recordIndoorOutdoorDeltaMetric records the measured temperature minus the home's outside temperature
+// It is skipped if either temperature is missing or the measured temperature is invalid
+func (tc *TadoCollector) recordIndoorOutdoorDeltaMetric(labels []string, metrics *ZoneMetrics, outsideCelsius *float64) {
+	if outsideCelsius == nil || metrics.MeasuredTemperatureCelsius == nil {
+		return
+	}
+	if validateTemperature(*metrics.MeasuredTemperatureCelsius, "measured_temperature_celsius") != nil {
+		return
 	}
+	delta := float64(*metrics.MeasuredTemperatureCelsius) - *outsideCelsius
+	tc.metricDescriptors.ZoneIndoorOutdoorDelta.WithLabelValues(labels...).Set(tc.roundTemperature(delta))
 }
 
 // recordMeasuredHumidityMetric records the measured humidity
-func (tc *TadoCollector) recordMeasuredHumidityMetric(zoneIDStr string, labels []string, metrics *ZoneMetrics) {
+func (tc *TadoCollector) recordMeasuredHumidityMetric(ctx context.Context, zoneIDStr string, labels []string, metrics *ZoneMetrics) {
 	if metrics.MeasuredHumidity != nil {
 		if err := validateHumidity(*metrics.MeasuredHumidity, "measured_humidity"); err != nil {
-			tc.log.WithField("zone_id", zoneIDStr).Warn("Invalid measured humidity, skipping metric", "value", *metrics.MeasuredHumidity, "error", err.Error())
+			tc.log.WarnContext(ctx, "Invalid measured humidity, skipping metric", "zone_id", zoneIDStr, "value", *metrics.MeasuredHumidity, "error", err.Error())
 		} else {
 			tc.metricDescriptors.HumidityMeasuredPercentage.WithLabelValues(labels...).Set(float64(*metrics.MeasuredHumidity))
 		}
@@ -389,25 +1548,25 @@ func (tc *TadoCollector) recordMeasuredHumidityMetric(zoneIDStr string, labels [
 }
 
 // recordTargetTemperatureMetrics records both Celsius and Fahrenheit target temperatures
-func (tc *TadoCollector) recordTargetTemperatureMetrics(zoneIDStr string, labels []string, metrics *ZoneMetrics) {
+func (tc *TadoCollector) recordTargetTemperatureMetrics(ctx context.Context, zoneIDStr string, labels []string, metrics *ZoneMetrics) {
 	if metrics.TargetTemperatureCelsius != nil {
 		if err := validateTemperature(*metrics.TargetTemperatureCelsius, "target_temperature_celsius"); err != nil {
-			tc.log.WithField("zone_id", zoneIDStr).Warn("Invalid target temperature, skipping metric", "value", *metrics.TargetTemperatureCelsius, "error", err.Error())
+			tc.log.WarnContext(ctx, "Invalid target temperature, skipping metric", "zone_id", zoneIDStr, "value", *metrics.TargetTemperatureCelsius, "error", err.Error())
 		} else {
-			tc.metricDescriptors.TemperatureSetCelsius.WithLabelValues(labels...).Set(float64(*metrics.TargetTemperatureCelsius))
+			tc.metricDescriptors.TemperatureSetCelsius.WithLabelValues(labels...).Set(tc.roundTemperature(float64(*metrics.TargetTemperatureCelsius)))
 		}
 	}
 
 	if metrics.TargetTemperatureFahrenheit != nil {
-		tc.metricDescriptors.TemperatureSetFahrenheit.WithLabelValues(labels...).Set(float64(*metrics.TargetTemperatureFahrenheit))
+		tc.metricDescriptors.TemperatureSetFahrenheit.WithLabelValues(labels...).Set(tc.roundTemperature(float64(*metrics.TargetTemperatureFahrenheit)))
 	}
 }
 
 // recordHeatingPowerMetric records the heating power percentage
-func (tc *TadoCollector) recordHeatingPowerMetric(zoneIDStr string, labels []string, metrics *ZoneMetrics) {
+func (tc *TadoCollector) recordHeatingPowerMetric(ctx context.Context, zoneIDStr string, labels []string, metrics *ZoneMetrics) {
 	if metrics.HeatingPowerPercentage != nil {
 		if err := validatePower(*metrics.HeatingPowerPercentage, "heating_power"); err != nil {
-			tc.log.WithField("zone_id", zoneIDStr).Warn("Invalid heating power, skipping metric", "value", *metrics.HeatingPowerPercentage, "error", err.Error())
+			tc.log.WarnContext(ctx, "Invalid heating power, skipping metric", "zone_id", zoneIDStr, "value", *metrics.HeatingPowerPercentage, "error", err.Error())
 		} else {
 			tc.metricDescriptors.HeatingPowerPercentage.WithLabelValues(labels...).Set(float64(*metrics.HeatingPowerPercentage))
 		}
@@ -431,3 +1590,71 @@ func (tc *TadoCollector) recordZonePoweredStatusMetric(labels []string, metrics
 	}
 	tc.metricDescriptors.IsZonePowered.WithLabelValues(labels...).Set(zonePowered)
 }
+
+// recordZoneDataPresentMetric records whether the zone reported a measured temperature (1) or not (0)
+// Missing readings leave the other zone series at their last value, so this distinguishes stale data
+func (tc *TadoCollector) recordZoneDataPresentMetric(labels []string, metrics *ZoneMetrics) {
+	dataPresent := 0.0
+	if metrics.MeasuredTemperatureCelsius != nil {
+		dataPresent = 1.0
+	}
+	tc.metricDescriptors.ZoneDataPresent.WithLabelValues(labels...).Set(dataPresent)
+}
+
+// recordAirConditioningMetrics records the AC mode, fan speed and power state of air conditioning zones
+// Heating and hot water zones are skipped
+func (tc *TadoCollector) recordAirConditioningMetrics(labels []string, metrics *ZoneMetrics) {
+	if !metrics.IsAirConditioning {
+		return
+	}
+
+	if metrics.ACMode != nil {
+		tc.metricDescriptors.ZoneACMode.WithLabelValues(labels...).Set(float64(*metrics.ACMode))
+	}
+
+	if metrics.ACFanSpeed != nil {
+		tc.metricDescriptors.ZoneACFanSpeed.WithLabelValues(labels...).Set(float64(*metrics.ACFanSpeed))
+	}
+
+	acPower := 0.0
+	if metrics.IsZonePowered {
+		acPower = 1.0
+	}
+	tc.metricDescriptors.ZoneACPower.WithLabelValues(labels...).Set(acPower)
+}
+
+// recordHotWaterMetrics records whether a manual overlay overrides the schedule, for hot water zones only
+func (tc *TadoCollector) recordHotWaterMetrics(labels []string, metrics *ZoneMetrics) {
+	if !metrics.IsHotWater {
+		return
+	}
+
+	overlayActive := 0.0
+	if metrics.IsOverlayActive {
+		overlayActive = 1.0
+	}
+	tc.metricDescriptors.HotWaterOverlayActive.WithLabelValues(labels...).Set(overlayActive)
+}
+
+// recordFrostProtectionMetric records whether the zone is held at the frost protection minimum, for heating zones only
+func (tc *TadoCollector) recordFrostProtectionMetric(labels []string, metrics *ZoneMetrics) {
+	if !metrics.IsHeating {
+		return
+	}
+
+	frostProtection := 0.0
+	if metrics.IsFrostProtectionActive() {
+		frostProtection = 1.0
+	}
+	tc.metricDescriptors.ZoneFrostProtectionActive.WithLabelValues(labels...).Set(frostProtection)
+}
+
+// recordOverlayRemainingMetric records the seconds until the zone's timer overlay expires
+// The series is removed when there is no timer overlay, so an expired countdown isn't left behind
+func (tc *TadoCollector) recordOverlayRemainingMetric(labels []string, metrics *ZoneMetrics) {
+	if metrics.OverlayRemainingSeconds == nil {
+		tc.metricDescriptors.ZoneOverlayRemainingSeconds.DeleteLabelValues(labels...)
+		return
+	}
+	tc.metricDescriptors.ZoneOverlayRemainingSeconds.WithLabelValues(labels...).Set(float64(*metrics.OverlayRemainingSeconds))
+}
@@ -1,8 +1,17 @@
 package collector
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -36,6 +45,8 @@ func TestCollectorWithSuccessfulCollection(t *testing.T) {
 	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
 	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{}}, nil)
 	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
 
 	// Create logger
 	log, err := logger.NewWithWriter("error", "text", io.Discard)
@@ -52,6 +63,7 @@ func TestCollectorWithSuccessfulCollection(t *testing.T) {
 	// Verify metrics were collected
 	metricsCount := len(ch)
 	assert.Greater(t, metricsCount, 0, "Expected metrics to be collected")
+	assert.WithinDuration(t, time.Now(), collector.LastSuccessfulScrape(), time.Second)
 }
 
 // TestCollectorHandlesGetMeError tests error handling when GetMe fails
@@ -80,6 +92,7 @@ func TestCollectorHandlesGetMeError(t *testing.T) {
 
 	// Should still collect metrics without panicking
 	assert.Greater(t, len(ch), 0)
+	assert.True(t, collector.LastSuccessfulScrape().IsZero(), "a failed scrape must not count as successful")
 }
 
 // TestCollectorHandlesEmptyHomes tests handling when user has no homes
@@ -108,6 +121,138 @@ func TestCollectorHandlesEmptyHomes(t *testing.T) {
 	assert.Greater(t, len(ch), 0)
 }
 
+// TestCollectorNilHomesVersusEmptyHomes tests that a user without a homes field is counted as a
+// malformed response, while an empty homes list is treated as an account without homes
+func TestCollectorNilHomesVersusEmptyHomes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                string
+		setup               func(m *mocks.MockTadoAPI)
+		expectedParseErrors float64
+		expectedAuthErrors  float64
+		expectedLog         string
+	}{
+		{
+			name:                "nil homes pointer",
+			setup:               func(m *mocks.MockTadoAPI) { m.ExpectGetMeReturnsNilHomes() },
+			expectedParseErrors: 1,
+			expectedAuthErrors:  0,
+			expectedLog:         "user response has no homes field",
+		},
+		{
+			name:                "empty homes slice",
+			setup:               func(m *mocks.MockTadoAPI) { m.ExpectGetMeReturnsEmptyHomes() },
+			expectedParseErrors: 0,
+			expectedAuthErrors:  1,
+			expectedLog:         "no homes found for user account",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := prometheus.NewRegistry()
+
+			metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+			require.NoError(t, err)
+			exporterMetrics := metrics.NewExporterMetricsUnregistered()
+			require.NoError(t, exporterMetrics.RegisterWith(registry))
+
+			mockAPI := &mocks.MockTadoAPI{}
+			tt.setup(mockAPI)
+
+			var logOutput bytes.Buffer
+			log, err := logger.NewWithWriter("warn", "json", &logOutput)
+			require.NoError(t, err)
+
+			collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log).
+				WithExporterMetrics(exporterMetrics)
+
+			ch := make(chan prometheus.Metric, 100)
+			collector.Collect(ch)
+			close(ch)
+
+			assert.Equal(t, tt.expectedParseErrors, findCounterValue(t, registry, "tado_exporter_response_parse_errors_total"))
+			assert.Equal(t, tt.expectedAuthErrors, findCounterValue(t, registry, "tado_exporter_authentication_errors_total"))
+			assert.Contains(t, logOutput.String(), tt.expectedLog)
+			assert.True(t, collector.LastSuccessfulScrape().IsZero(), "neither case is a successful scrape")
+		})
+	}
+
+	// Home discovery for per-home metrics reports the malformed response as an error
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsNilHomes()
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+
+	_, err = NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "").HomeIDs(context.Background())
+	assert.ErrorIs(t, err, errMissingHomes)
+}
+
+// TestCollectorAllowNoHomes tests that an account without homes is only an authentication error
+// when no homes are not allowed
+func TestCollectorAllowNoHomes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name               string
+		allowNoHomes       bool
+		expectedAuthValid  float64
+		expectedAuthErrors float64
+		expectedLastError  bool
+		expectedLogLevel   string
+	}{
+		{name: "not allowed", allowNoHomes: false, expectedAuthValid: 0, expectedAuthErrors: 1, expectedLastError: true, expectedLogLevel: "warning"},
+		{name: "allowed", allowNoHomes: true, expectedAuthValid: 1, expectedAuthErrors: 0, expectedLastError: false, expectedLogLevel: "info"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := prometheus.NewRegistry()
+
+			metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+			require.NoError(t, err)
+			exporterMetrics := metrics.NewExporterMetricsUnregistered()
+			require.NoError(t, exporterMetrics.RegisterWith(registry))
+
+			mockAPI := &mocks.MockTadoAPI{}
+			mockAPI.ExpectGetMeReturnsEmptyHomes()
+
+			var logOutput bytes.Buffer
+			log, err := logger.NewWithWriter("info", "json", &logOutput)
+			require.NoError(t, err)
+
+			collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log).
+				WithExporterMetrics(exporterMetrics).
+				WithAllowNoHomes(tt.allowNoHomes)
+
+			ch := make(chan prometheus.Metric, 100)
+			collector.Collect(ch)
+			close(ch)
+
+			authValid, ok := findGaugeValue(t, registry, "tado_exporter_authentication_valid", nil)
+			require.True(t, ok)
+			assert.Equal(t, tt.expectedAuthValid, authValid)
+			assert.Equal(t, tt.expectedAuthErrors, findCounterValue(t, registry, "tado_exporter_authentication_errors_total"))
+
+			_, hasLastError := findGaugeValue(t, registry, "tado_exporter_last_error_info", nil)
+			assert.Equal(t, tt.expectedLastError, hasLastError)
+
+			assert.Contains(t, logOutput.String(), "no homes found for user account")
+			assert.Contains(t, logOutput.String(), fmt.Sprintf(`"level":"%s"`, tt.expectedLogLevel))
+
+			if tt.allowNoHomes {
+				homesTotal, ok := findGaugeValue(t, registry, "tado_exporter_homes_total", nil)
+				require.True(t, ok)
+				assert.Equal(t, 0.0, homesTotal)
+				assert.False(t, collector.LastSuccessfulScrape().IsZero(), "no homes is a successful scrape when allowed")
+			} else {
+				assert.True(t, collector.LastSuccessfulScrape().IsZero())
+			}
+		})
+	}
+}
+
 // TestCollectorWithHomeIDFilter tests home ID filtering
 func TestCollectorWithHomeIDFilter(t *testing.T) {
 	t.Parallel()
@@ -125,6 +270,8 @@ func TestCollectorWithHomeIDFilter(t *testing.T) {
 	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
 	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{}}, nil)
 	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
 
 	log, err := logger.NewWithWriter("error", "text", io.Discard)
 	require.NoError(t, err)
@@ -139,6 +286,49 @@ func TestCollectorWithHomeIDFilter(t *testing.T) {
 	assert.Greater(t, len(ch), 0)
 }
 
+// TestCollectorWithExcludedHomeIDs tests that excluded homes are skipped without an include filter
+func TestCollectorWithExcludedHomeIDs(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	zoneID := 1
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1, 2, 3})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{Id: &zoneID}}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{"1": {}}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	log, err := logger.NewWithWriter("error", "text", io.Discard)
+	require.NoError(t, err)
+
+	collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log).
+		WithExcludedHomeIDs([]string{"2"})
+
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	mockAPI.AssertNumberOfCalls(t, "GetHomeState", 2)
+	mockAPI.AssertNotCalled(t, "GetZones", mock.Anything, tado.HomeId(2))
+
+	for homeID, expected := range map[string]bool{"1": true, "2": false, "3": true} {
+		_, found := findGaugeValue(t, registry, "tado_is_window_open", map[string]string{"home_id": homeID})
+		assert.Equal(t, expected, found, "home %s", homeID)
+	}
+
+	homeIDs, err := collector.HomeIDs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "3"}, homeIDs)
+}
+
 // TestCollectorWithExporterMetrics tests collection with exporter metrics
 func TestCollectorWithExporterMetrics(t *testing.T) {
 	t.Parallel()
@@ -149,8 +339,7 @@ func TestCollectorWithExporterMetrics(t *testing.T) {
 	require.NoError(t, err)
 	require.NoError(t, metricDescs.RegisterWith(registry))
 
-	exporterMetrics, err := metrics.NewExporterMetrics()
-	require.NoError(t, err)
+	exporterMetrics := metrics.NewExporterMetricsUnregistered()
 
 	mockAPI := &mocks.MockTadoAPI{}
 	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
@@ -158,6 +347,8 @@ func TestCollectorWithExporterMetrics(t *testing.T) {
 	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
 	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{}}, nil)
 	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
 
 	log, err := logger.NewWithWriter("error", "text", io.Discard)
 	require.NoError(t, err)
@@ -188,6 +379,8 @@ func TestCollectorContextCancellation(t *testing.T) {
 	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
 	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{}}, nil)
 	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
 
 	log, err := logger.NewWithWriter("error", "text", io.Discard)
 	require.NoError(t, err)
@@ -235,7 +428,7 @@ func TestDescribe(t *testing.T) {
 	assert.Greater(t, len(descriptors), 0, "Expected metrics to be described")
 }
 
-// TestCollectorGetWeatherError tests error handling when GetWeather fails
+// TestCollectorGetWeatherError tests that a failing GetWeather doesn't stop the other home-level metrics
 func TestCollectorGetWeatherError(t *testing.T) {
 	t.Parallel()
 
@@ -251,6 +444,8 @@ func TestCollectorGetWeatherError(t *testing.T) {
 	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
 	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{}}, nil)
 	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("weather API error"))
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{{}, {}}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
 
 	log, err := logger.NewWithWriter("error", "text", io.Discard)
 	require.NoError(t, err)
@@ -263,4 +458,2720 @@ func TestCollectorGetWeatherError(t *testing.T) {
 
 	// Should handle gracefully and still produce metrics
 	assert.Greater(t, len(ch), 0)
+
+	// Devices are still collected while the weather endpoint fails
+	value, found := findGaugeValue(t, registry, "tado_home_devices_total", map[string]string{"home_id": "1"})
+	require.True(t, found)
+	assert.Equal(t, 2.0, value)
+	mockAPI.AssertCalled(t, "GetMobileDevices", mock.Anything, mock.Anything)
+}
+
+// TestCollectorScrapeBudgetUsedRatio tests that the budget ratio reflects the fraction of the timeout used
+func TestCollectorScrapeBudgetUsedRatio(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	exporterMetrics := metrics.NewExporterMetricsUnregistered()
+	require.NoError(t, exporterMetrics.RegisterWith(registry))
+
+	// GetMe takes roughly half of the 1s scrape timeout
+	homes := []tado.HomeBase{}
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.On("GetMe", mock.Anything).Return(&tado.User{Homes: &homes}, nil).After(500 * time.Millisecond)
+
+	log, err := logger.NewWithWriter("error", "text", io.Discard)
+	require.NoError(t, err)
+
+	collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 1*time.Second, "", log).
+		WithExporterMetrics(exporterMetrics)
+
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	assert.GreaterOrEqual(t, collector.LastScrapeDuration(), 500*time.Millisecond)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	ratioFound := false
+	for _, family := range families {
+		if family.GetName() == "tado_exporter_scrape_budget_used_ratio" {
+			ratioFound = true
+			require.Len(t, family.Metric, 1)
+			assert.InDelta(t, 0.5, family.Metric[0].GetGauge().GetValue(), 0.2)
+		}
+	}
+	assert.True(t, ratioFound, "scrape budget ratio metric not found")
+}
+
+// TestCollectorZonesObserved tests that the total zone count across homes is observed once per scrape
+func TestCollectorZonesObserved(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	exporterMetrics := metrics.NewExporterMetricsUnregistered()
+	require.NoError(t, exporterMetrics.RegisterWith(registry))
+
+	// Home 1 has two zones, home 2 has one zone
+	zoneID1, zoneID2, zoneID3 := 1, 2, 3
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1, 2})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, tado.HomeId(1)).Return([]tado.Zone{{Id: &zoneID1}, {Id: &zoneID2}}, nil)
+	mockAPI.On("GetZones", mock.Anything, tado.HomeId(2)).Return([]tado.Zone{{Id: &zoneID3}}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	log, err := logger.NewWithWriter("error", "text", io.Discard)
+	require.NoError(t, err)
+
+	collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log).
+		WithExporterMetrics(exporterMetrics)
+
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	histogramFound := false
+	for _, family := range families {
+		if family.GetName() == "tado_exporter_zones_observed" {
+			histogramFound = true
+			require.Len(t, family.Metric, 1)
+			histogram := family.Metric[0].GetHistogram()
+			assert.Equal(t, uint64(1), histogram.GetSampleCount())
+			assert.Equal(t, 3.0, histogram.GetSampleSum())
+			for _, bucket := range histogram.Bucket {
+				if bucket.GetUpperBound() == 2 {
+					assert.Equal(t, uint64(0), bucket.GetCumulativeCount())
+				}
+				if bucket.GetUpperBound() == 4 {
+					assert.Equal(t, uint64(1), bucket.GetCumulativeCount())
+				}
+			}
+		}
+	}
+	assert.True(t, histogramFound, "zones observed histogram not found")
+}
+
+// TestCollectorSameZoneIDAcrossHomes tests that zones sharing an ID in different homes don't contaminate each other
+func TestCollectorSameZoneIDAcrossHomes(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	zoneID := 1
+	home1Temp, home2Temp := float32(19.5), float32(22.0)
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1, 2})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{Id: &zoneID}}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, tado.HomeId(1)).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{
+		"1": {SensorDataPoints: &tado.SensorDataPoints{InsideTemperature: &tado.TemperatureDataPoint{Celsius: &home1Temp}}},
+	}}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, tado.HomeId(2)).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{
+		"1": {SensorDataPoints: &tado.SensorDataPoints{InsideTemperature: &tado.TemperatureDataPoint{Celsius: &home2Temp}}},
+	}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	log, err := logger.NewWithWriter("error", "text", io.Discard)
+	require.NoError(t, err)
+
+	collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log)
+
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	value, found := findGaugeValue(t, registry, "tado_temperature_measured_celsius", map[string]string{"home_id": "1", "zone_id": "1"})
+	require.True(t, found)
+	assert.InDelta(t, 19.5, value, 0.001)
+
+	value, found = findGaugeValue(t, registry, "tado_temperature_measured_celsius", map[string]string{"home_id": "2", "zone_id": "1"})
+	require.True(t, found)
+	assert.InDelta(t, 22.0, value, 0.001)
+}
+
+// TestCollectorDuplicateZoneIDInHome tests that a zone ID repeated within one home is only recorded once
+func TestCollectorDuplicateZoneIDInHome(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	zoneID := 1
+	firstName, secondName := "Living Room", "Duplicate"
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{Id: &zoneID, Name: &firstName}, {Id: &zoneID, Name: &secondName}}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{"1": {}}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	log, err := logger.NewWithWriter("error", "text", io.Discard)
+	require.NoError(t, err)
+
+	collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log)
+
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	_, found := findGaugeValue(t, registry, "tado_is_window_open", map[string]string{"zone_name": firstName})
+	assert.True(t, found)
+	_, found = findGaugeValue(t, registry, "tado_is_window_open", map[string]string{"zone_name": secondName})
+	assert.False(t, found, "duplicate zone should be skipped")
+}
+
+// TestCollectorSkipsConsistentlyFailingHome tests that a home failing threshold scrapes in a row is skipped,
+// tried again once its cooldown ends and collected again after it recovers
+func TestCollectorSkipsConsistentlyFailingHome(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+	exporterMetrics := metrics.NewExporterMetricsUnregistered()
+	require.NoError(t, exporterMetrics.RegisterWith(registry))
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(nil, errors.New("access revoked")).Times(3)
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	cooldown := 10 * time.Minute
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	collector := NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "").
+		WithExporterMetrics(exporterMetrics).
+		WithHomeFailureSkip(2, cooldown).
+		WithClock(clock)
+	scrape := func() {
+		ch := make(chan prometheus.Metric, 200)
+		collector.Collect(ch)
+		close(ch)
+	}
+	skipped := func() float64 {
+		value, found := findGaugeValue(t, registry, "tado_exporter_home_skipped", map[string]string{"home_id": "1"})
+		require.True(t, found)
+		return value
+	}
+
+	// The first failure is tolerated, the second starts the cooldown
+	scrape()
+	assert.Equal(t, 0.0, skipped())
+	scrape()
+	assert.Equal(t, 1.0, skipped())
+	mockAPI.AssertNumberOfCalls(t, "GetHomeState", 2)
+
+	// During the cooldown the home's API is not called
+	scrape()
+	mockAPI.AssertNumberOfCalls(t, "GetHomeState", 2)
+
+	// After the cooldown it is tried again; failing again starts another cooldown
+	clock.Advance(cooldown)
+	scrape()
+	mockAPI.AssertNumberOfCalls(t, "GetHomeState", 3)
+	assert.Equal(t, 1.0, skipped())
+	scrape()
+	mockAPI.AssertNumberOfCalls(t, "GetHomeState", 3)
+
+	// A successful retry resumes collecting the home on every scrape
+	clock.Advance(cooldown)
+	scrape()
+	assert.Equal(t, 0.0, skipped())
+	scrape()
+	mockAPI.AssertNumberOfCalls(t, "GetHomeState", 5)
+}
+
+func TestCollectorHomeFailureSkipOnlyCountsLostAccess(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		weatherErr  error
+		wantSkipped float64
+	}{
+		{name: "weather endpoint failing", weatherErr: errors.New("weather unavailable"), wantSkipped: 0},
+		{name: "weather answered with 404", weatherErr: &StatusError{Operation: "GetWeather", StatusCode: http.StatusNotFound}, wantSkipped: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := prometheus.NewRegistry()
+
+			metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+			require.NoError(t, err)
+			require.NoError(t, metricDescs.RegisterWith(registry))
+			exporterMetrics := metrics.NewExporterMetricsUnregistered()
+			require.NoError(t, exporterMetrics.RegisterWith(registry))
+
+			mockAPI := &mocks.MockTadoAPI{}
+			mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+			mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+			mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
+			mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{}}, nil)
+			mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(nil, tt.weatherErr)
+			mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+			mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+			collector := NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "").
+				WithExporterMetrics(exporterMetrics).
+				WithHomeFailureSkip(2, 10*time.Minute).
+				WithClock(&fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)})
+
+			for range 3 {
+				ch := make(chan prometheus.Metric, 200)
+				collector.Collect(ch)
+				close(ch)
+			}
+
+			skipped, found := findGaugeValue(t, registry, "tado_exporter_home_skipped", map[string]string{"home_id": "1"})
+			require.True(t, found)
+			assert.Equal(t, tt.wantSkipped, skipped)
+			if tt.wantSkipped == 0 {
+				mockAPI.AssertNumberOfCalls(t, "GetHomeState", 3)
+			}
+		})
+	}
+}
+
+// fakeClock is a metrics.Clock that only moves when advanced, so time-dependent metrics can be asserted exactly
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// Now implements metrics.Clock
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// findGaugeValue gathers the registry and returns the value of the first gauge
+// series of the named metric whose labels include all of the given labels
+func findGaugeValue(t *testing.T, registry *prometheus.Registry, name string, labels map[string]string) (float64, bool) {
+	t.Helper()
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, m := range family.Metric {
+			matched := 0
+			for _, pair := range m.Label {
+				if value, ok := labels[pair.GetName()]; ok && value == pair.GetValue() {
+					matched++
+				}
+			}
+			if matched == len(labels) {
+				return m.GetGauge().GetValue(), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// findCounterValue gathers the registry and returns the value of the named unlabelled counter
+func findCounterValue(t *testing.T, registry *prometheus.Registry, name string) float64 {
+	t.Helper()
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() == name {
+			require.Len(t, family.Metric, 1)
+			return family.Metric[0].GetCounter().GetValue()
+		}
+	}
+	t.Fatalf("metric %s not found", name)
+	return 0
+}
+
+// TestCollectorClockSkew tests that the clock skew metric reflects future- and past-dated sensor timestamps
+func TestCollectorClockSkew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		offset     time.Duration
+		expectSign float64
+	}{
+		{"future-dated sensor timestamp", time.Hour, 1},
+		{"past-dated sensor timestamp", -time.Hour, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := prometheus.NewRegistry()
+
+			metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+			require.NoError(t, err)
+			require.NoError(t, metricDescs.RegisterWith(registry))
+
+			exporterMetrics := metrics.NewExporterMetricsUnregistered()
+			require.NoError(t, exporterMetrics.RegisterWith(registry))
+
+			zoneID := 1
+			timestamp := time.Now().Add(tt.offset)
+
+			mockAPI := &mocks.MockTadoAPI{}
+			mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+			mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+			mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{Id: &zoneID}}, nil)
+			mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{
+				"1": {SensorDataPoints: &tado.SensorDataPoints{InsideTemperature: &tado.TemperatureDataPoint{Timestamp: &timestamp}}},
+			}}, nil)
+			mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+			mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+			mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+			log, err := logger.NewWithWriter("error", "text", io.Discard)
+			require.NoError(t, err)
+
+			collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log).
+				WithExporterMetrics(exporterMetrics)
+
+			ch := make(chan prometheus.Metric, 100)
+			collector.Collect(ch)
+			close(ch)
+
+			skew, found := findGaugeValue(t, registry, "tado_exporter_clock_skew_seconds", nil)
+			require.True(t, found)
+			assert.InDelta(t, tt.expectSign*3600, skew, 5)
+		})
+	}
+}
+
+// TestCollectorStrictMode tests that a GetZones failure suppresses all Tado metrics only in strict mode
+func TestCollectorStrictMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		strict           bool
+		expectZoneSeries bool
+	}{
+		{"partial metrics when not strict", false, true},
+		{"no Tado metrics when strict", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+			require.NoError(t, err)
+
+			exporterMetrics := metrics.NewExporterMetricsUnregistered()
+
+			// Home 1 collects fine, home 2 fails to list its zones
+			zoneID := 1
+			mockAPI := &mocks.MockTadoAPI{}
+			mockAPI.ExpectGetMeReturnsHomes([]int64{1, 2})
+			mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+			mockAPI.On("GetZones", mock.Anything, tado.HomeId(1)).Return([]tado.Zone{{Id: &zoneID}}, nil)
+			mockAPI.On("GetZones", mock.Anything, tado.HomeId(2)).Return(nil, fmt.Errorf("zones API error"))
+			mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{"1": {}}}, nil)
+			mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+			mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+			mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+			log, err := logger.NewWithWriter("error", "text", io.Discard)
+			require.NoError(t, err)
+
+			collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log).
+				WithExporterMetrics(exporterMetrics).
+				WithStrictMode(tt.strict)
+
+			// Register the collector itself so gathering goes through Collect
+			registry := prometheus.NewRegistry()
+			require.NoError(t, registry.Register(collector))
+
+			_, found := findGaugeValue(t, registry, "tado_is_window_open", map[string]string{"home_id": "1"})
+			assert.Equal(t, tt.expectZoneSeries, found)
+
+			_, found = findGaugeValue(t, registry, "tado_is_resident_present", nil)
+			assert.Equal(t, !tt.strict, found)
+
+			families, err := registry.Gather()
+			require.NoError(t, err)
+			errorsFound, refreshesFound := false, false
+			for _, family := range families {
+				switch family.GetName() {
+				case "tado_exporter_scrape_errors_total":
+					errorsFound = true
+				case "tado_exporter_token_refreshes_total":
+					refreshesFound = true
+				}
+			}
+			assert.True(t, errorsFound, "scrape errors counter should always be emitted")
+			assert.True(t, refreshesFound, "token refresh counter should always be emitted")
+		})
+	}
+}
+
+// TestCollectorZoneDataPresent tests that zones missing a measured temperature are flagged as missing data
+func TestCollectorZoneDataPresent(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	zoneWithData, zoneWithoutData := 1, 2
+	temperature := float32(20.5)
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{Id: &zoneWithData}, {Id: &zoneWithoutData}}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{
+		"1": {SensorDataPoints: &tado.SensorDataPoints{InsideTemperature: &tado.TemperatureDataPoint{Celsius: &temperature}}},
+		"2": {SensorDataPoints: &tado.SensorDataPoints{}},
+	}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	log, err := logger.NewWithWriter("error", "text", io.Discard)
+	require.NoError(t, err)
+
+	collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log)
+
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	value, found := findGaugeValue(t, registry, "tado_zone_data_present", map[string]string{"zone_id": "1"})
+	require.True(t, found)
+	assert.Equal(t, 1.0, value)
+
+	value, found = findGaugeValue(t, registry, "tado_zone_data_present", map[string]string{"zone_id": "2"})
+	require.True(t, found)
+	assert.Equal(t, 0.0, value)
+}
+
+// TestCollectorZoneMissingFromZoneStates tests that a zone absent from GetZoneStates is reported
+// as having no data rather than failing with an error
+func TestCollectorZoneMissingFromZoneStates(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	reportedZone, missingZone := 1, 2
+	temperature := float32(20.5)
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{Id: &reportedZone}, {Id: &missingZone}}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{
+		"1": {SensorDataPoints: &tado.SensorDataPoints{InsideTemperature: &tado.TemperatureDataPoint{Celsius: &temperature}}},
+	}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	var logOutput bytes.Buffer
+	log, err := logger.NewWithWriter("warn", "json", &logOutput)
+	require.NoError(t, err)
+
+	collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log)
+
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	value, found := findGaugeValue(t, registry, "tado_zone_data_present", map[string]string{"zone_id": "2"})
+	require.True(t, found, "the missing zone should still emit a data present series")
+	assert.Equal(t, 0.0, value)
+
+	value, found = findGaugeValue(t, registry, "tado_zone_data_present", map[string]string{"zone_id": "1"})
+	require.True(t, found)
+	assert.Equal(t, 1.0, value)
+
+	_, found = findGaugeValue(t, registry, "tado_is_window_open", map[string]string{"zone_id": "2"})
+	assert.False(t, found, "no other series are invented for the missing zone")
+
+	assert.Empty(t, logOutput.String(), "a zone missing from zone states should not be logged as a warning or error")
+	assert.False(t, collector.LastSuccessfulScrape().IsZero(), "the scrape should succeed")
+}
+
+// TestCollectorUnexpectedTemperatureType tests that a measured temperature reported with a type other than
+// TEMPERATURE is skipped with a warning instead of being exported
+func TestCollectorUnexpectedTemperatureType(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	temperatureZone, percentageZone := 1, 2
+	value := float32(20.5)
+	temperatureType, percentageType := "TEMPERATURE", "PERCENTAGE"
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{Id: &temperatureZone}, {Id: &percentageZone}}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{
+		"1": {SensorDataPoints: &tado.SensorDataPoints{InsideTemperature: &tado.TemperatureDataPoint{Celsius: &value, Type: &temperatureType}}},
+		"2": {SensorDataPoints: &tado.SensorDataPoints{InsideTemperature: &tado.TemperatureDataPoint{Celsius: &value, Fahrenheit: &value, Type: &percentageType}}},
+	}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	var logOutput bytes.Buffer
+	log, err := logger.NewWithWriter("warn", "json", &logOutput)
+	require.NoError(t, err)
+
+	collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log)
+
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	measured, found := findGaugeValue(t, registry, "tado_temperature_measured_celsius", map[string]string{"zone_id": "1"})
+	require.True(t, found)
+	assert.Equal(t, 20.5, measured)
+
+	_, found = findGaugeValue(t, registry, "tado_temperature_measured_celsius", map[string]string{"zone_id": "2"})
+	assert.False(t, found, "a reading of an unexpected type should not be exported as a temperature")
+	_, found = findGaugeValue(t, registry, "tado_temperature_measured_fahrenheit", map[string]string{"zone_id": "2"})
+	assert.False(t, found)
+
+	assert.Contains(t, logOutput.String(), "Unexpected measured temperature type")
+	assert.Contains(t, logOutput.String(), "PERCENTAGE")
+}
+
+// TestCollectorIndoorOutdoorDelta tests that the zone delta is the measured minus the outside temperature
+func TestCollectorIndoorOutdoorDelta(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	warmZone, coldZone := 1, 2
+	warm, cold, outside := float32(21.5), float32(4.0), float32(6.5)
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{Id: &warmZone}, {Id: &coldZone}}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{
+		"1": {SensorDataPoints: &tado.SensorDataPoints{InsideTemperature: &tado.TemperatureDataPoint{Celsius: &warm}}},
+		"2": {SensorDataPoints: &tado.SensorDataPoints{InsideTemperature: &tado.TemperatureDataPoint{Celsius: &cold}}},
+	}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{OutsideTemperature: &tado.TemperatureDataPoint{Celsius: &outside}}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	collector := NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "")
+
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	delta, found := findGaugeValue(t, registry, "tado_zone_indoor_outdoor_delta_celsius", map[string]string{"zone_id": "1"})
+	require.True(t, found)
+	assert.Equal(t, 15.0, delta)
+
+	delta, found = findGaugeValue(t, registry, "tado_zone_indoor_outdoor_delta_celsius", map[string]string{"zone_id": "2"})
+	require.True(t, found)
+	assert.Equal(t, -2.5, delta)
+}
+
+// TestCollectorOutsideTemperatureFahrenheitOnly tests that the outside temperature in Celsius, and the
+// indoor-outdoor delta, are derived when the weather only reports Fahrenheit
+func TestCollectorOutsideTemperatureFahrenheitOnly(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	zoneID := 1
+	inside, outsideFahrenheit := float32(21.5), float32(50)
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{Id: &zoneID}}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{
+		"1": {SensorDataPoints: &tado.SensorDataPoints{InsideTemperature: &tado.TemperatureDataPoint{Celsius: &inside}}},
+	}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{OutsideTemperature: &tado.TemperatureDataPoint{Fahrenheit: &outsideFahrenheit}}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	collector := NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "")
+
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	celsius, found := findGaugeValue(t, registry, "tado_temperature_outside_celsius", map[string]string{})
+	require.True(t, found)
+	assert.Equal(t, metrics.FahrenheitToCelsius(50), celsius)
+	assert.InDelta(t, 10.0, celsius, 1e-9)
+
+	fahrenheit, found := findGaugeValue(t, registry, "tado_temperature_outside_fahrenheit", map[string]string{})
+	require.True(t, found)
+	assert.Equal(t, 50.0, fahrenheit)
+
+	delta, found := findGaugeValue(t, registry, "tado_zone_indoor_outdoor_delta_celsius", map[string]string{"zone_id": "1"})
+	require.True(t, found)
+	assert.InDelta(t, 11.5, delta, 1e-9)
+}
+
+// TestCollectorZoneSettingsMetrics tests the 0/1 mapping of child lock and dazzle mode, and that they are skipped when absent
+func TestCollectorZoneSettingsMetrics(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	// Zone details as returned by GetZones; the child lock is reported by each device in the zone
+	var zones []tado.Zone
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"id": 1, "dazzleMode": {"supported": true, "enabled": true}, "devices": [{"childLockEnabled": false}, {"childLockEnabled": true}]},
+		{"id": 2, "dazzleEnabled": false, "devices": [{"childLockEnabled": false}, {"serialNo": "RU123"}]},
+		{"id": 3, "devices": [{"serialNo": "BR456"}]}
+	]`), &zones))
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return(zones, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	collector := NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "")
+
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	tests := []struct {
+		metric string
+		zoneID string
+		want   float64
+		found  bool
+	}{
+		{metric: "tado_zone_child_lock_enabled", zoneID: "1", want: 1, found: true},
+		{metric: "tado_zone_child_lock_enabled", zoneID: "2", want: 0, found: true},
+		{metric: "tado_zone_child_lock_enabled", zoneID: "3", found: false},
+		{metric: "tado_zone_dazzle_mode_enabled", zoneID: "1", want: 1, found: true},
+		{metric: "tado_zone_dazzle_mode_enabled", zoneID: "2", want: 0, found: true},
+		{metric: "tado_zone_dazzle_mode_enabled", zoneID: "3", found: false},
+	}
+	for _, tt := range tests {
+		value, found := findGaugeValue(t, registry, tt.metric, map[string]string{"zone_id": tt.zoneID})
+		assert.Equal(t, tt.found, found, "%s for zone %s", tt.metric, tt.zoneID)
+		assert.Equal(t, tt.want, value, "%s for zone %s", tt.metric, tt.zoneID)
+	}
+}
+
+// TestCollectorZoneDevicesMetric tests that each zone reports the number of devices assigned to it
+func TestCollectorZoneDevicesMetric(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	// Zone details as returned by GetZones: two valves in zone 1, one thermostat in zone 2
+	var zones []tado.Zone
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"id": 1, "devices": [{"serialNo": "VA001"}, {"serialNo": "VA002"}]},
+		{"id": 2, "devices": [{"serialNo": "RU001"}]},
+		{"id": 3}
+	]`), &zones))
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return(zones, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	collector := NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "")
+
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	value, found := findGaugeValue(t, registry, "tado_zone_devices_total", map[string]string{"zone_id": "1"})
+	require.True(t, found)
+	assert.Equal(t, 2.0, value)
+
+	value, found = findGaugeValue(t, registry, "tado_zone_devices_total", map[string]string{"zone_id": "2"})
+	require.True(t, found)
+	assert.Equal(t, 1.0, value)
+
+	_, found = findGaugeValue(t, registry, "tado_zone_devices_total", map[string]string{"zone_id": "3"})
+	assert.False(t, found, "zones without device details report no count")
+}
+
+// TestCollectorLogsScrapeSummary tests that a completed scrape logs a structured summary
+func TestCollectorLogsScrapeSummary(t *testing.T) {
+	t.Parallel()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+
+	zoneID := 1
+	value := float32(20.5)
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{Id: &zoneID}}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{
+		"1": {SensorDataPoints: &tado.SensorDataPoints{InsideTemperature: &tado.TemperatureDataPoint{Celsius: &value}}},
+	}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	var logOutput bytes.Buffer
+	log, err := logger.NewWithWriter("info", "json", &logOutput)
+	require.NoError(t, err)
+
+	collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log)
+
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	var summary map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(logOutput.String()), "\n") {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		if entry["msg"] == "Scrape completed" {
+			summary = entry
+		}
+	}
+	require.NotNil(t, summary, "a completed scrape should log a summary")
+
+	assert.Equal(t, "info", summary["level"])
+	assert.Equal(t, 1.0, summary["total_homes"])
+	assert.Equal(t, 1.0, summary["total_zones"])
+	assert.Equal(t, 0.0, summary["errors"])
+	assert.Contains(t, summary, "duration_ms")
+	assert.NotEmpty(t, summary["request_id"])
+}
+
+// TestCollectorWarnsOnLowZoneTimeBudget tests that many zones and a short timeout log a warning and set the budget metric
+func TestCollectorWarnsOnLowZoneTimeBudget(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	exporterMetrics := metrics.NewExporterMetricsUnregistered()
+	require.NoError(t, exporterMetrics.RegisterWith(registry))
+
+	// 50 zones sharing a 1s scrape timeout leave at most 20ms per zone
+	zones := make([]tado.Zone, 50)
+	for i := range zones {
+		zoneID := i + 1
+		zones[i] = tado.Zone{Id: &zoneID}
+	}
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return(zones, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	var logOutput bytes.Buffer
+	log, err := logger.NewWithWriter("warn", "json", &logOutput)
+	require.NoError(t, err)
+
+	collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, time.Second, "", log).
+		WithExporterMetrics(exporterMetrics)
+
+	ch := make(chan prometheus.Metric, 1000)
+	collector.Collect(ch)
+	close(ch)
+
+	assert.Contains(t, logOutput.String(), "Scrape time budget per zone is low")
+	assert.Contains(t, logOutput.String(), `"zones":50`)
+
+	budget, found := findGaugeValue(t, registry, "tado_exporter_zone_time_budget_seconds", map[string]string{"home_id": "1"})
+	require.True(t, found)
+	assert.Greater(t, budget, 0.0)
+	assert.LessOrEqual(t, budget, 0.02)
+}
+
+// TestCollectorWarnsOnceWhenScrapeNearTimeout tests that a scrape slower than the timeout logs a throttled warning
+func TestCollectorWarnsOnceWhenScrapeNearTimeout(t *testing.T) {
+	t.Parallel()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+
+	// The mock ignores the context, so each scrape takes longer than the timeout
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.On("GetMe", mock.Anything).After(150*time.Millisecond).Return(nil, errors.New("context deadline exceeded"))
+
+	var logOutput bytes.Buffer
+	log, err := logger.NewWithWriter("warn", "json", &logOutput)
+	require.NoError(t, err)
+
+	collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 100*time.Millisecond, "", log)
+
+	for i := 0; i < 2; i++ {
+		ch := make(chan prometheus.Metric, 100)
+		collector.Collect(ch)
+		close(ch)
+	}
+
+	assert.Equal(t, 1, strings.Count(logOutput.String(), "Scrape took close to or longer than the scrape timeout"),
+		"the warning should be throttled")
+}
+
+// TestCollectorLastErrorInfo tests that a timed out call sets the last error reason and an error-free scrape clears it
+func TestCollectorLastErrorInfo(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	exporterMetrics := metrics.NewExporterMetricsUnregistered()
+	require.NoError(t, exporterMetrics.RegisterWith(registry))
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("failed to get zones: %w", context.DeadlineExceeded)).Once()
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	collector := NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "").WithExporterMetrics(exporterMetrics)
+	scrape := func() {
+		ch := make(chan prometheus.Metric, 100)
+		collector.Collect(ch)
+		close(ch)
+	}
+
+	scrape()
+	value, found := findGaugeValue(t, registry, "tado_exporter_last_error_info", map[string]string{"reason": "timeout"})
+	require.True(t, found)
+	assert.Equal(t, 1.0, value)
+
+	scrape()
+	_, found = findGaugeValue(t, registry, "tado_exporter_last_error_info", nil)
+	assert.False(t, found, "an error-free scrape should clear the last error")
+}
+
+// TestCollectorAirConditioningMetrics tests that AC mode, fan speed and power are exported for AC zones only
+func TestCollectorAirConditioningMetrics(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	acZone, heatingZone := 1, 2
+	acType, heatingType := tado.AIRCONDITIONING, tado.HEATING
+	coolMode := tado.AirConditioningModeCOOL
+	fanLevel := tado.FanLevelLEVEL3
+	on := tado.PowerON
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{Id: &acZone}, {Id: &heatingZone}}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{
+		"1": {Setting: &tado.ZoneSetting{Type: &acType, Mode: &coolMode, FanLevel: &fanLevel, Power: &on}},
+		"2": {Setting: &tado.ZoneSetting{Type: &heatingType, Power: &on}},
+	}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	log, err := logger.NewWithWriter("error", "text", io.Discard)
+	require.NoError(t, err)
+
+	collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log)
+
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	value, found := findGaugeValue(t, registry, "tado_zone_ac_mode", map[string]string{"zone_id": "1"})
+	require.True(t, found)
+	assert.Equal(t, 1.0, value, "COOL is encoded as 1")
+
+	value, found = findGaugeValue(t, registry, "tado_zone_ac_fan_speed", map[string]string{"zone_id": "1"})
+	require.True(t, found)
+	assert.Equal(t, 4.0, value, "LEVEL3 is encoded as 4")
+
+	value, found = findGaugeValue(t, registry, "tado_zone_ac_power", map[string]string{"zone_id": "1"})
+	require.True(t, found)
+	assert.Equal(t, 1.0, value)
+
+	_, found = findGaugeValue(t, registry, "tado_zone_ac_mode", map[string]string{"zone_id": "2"})
+	assert.False(t, found, "heating zones have no AC mode")
+	_, found = findGaugeValue(t, registry, "tado_zone_ac_fan_speed", map[string]string{"zone_id": "2"})
+	assert.False(t, found, "heating zones have no AC fan speed")
+	_, found = findGaugeValue(t, registry, "tado_zone_ac_power", map[string]string{"zone_id": "2"})
+	assert.False(t, found, "heating zones have no AC power")
+}
+
+// TestCollectorHotWaterOverlayMetric tests that the overlay state is exported for hot water zones only
+func TestCollectorHotWaterOverlayMetric(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	overriddenZone, scheduledZone, heatingZone := 1, 2, 3
+	hotWaterType, heatingType := tado.HOTWATER, tado.HEATING
+	on := tado.PowerON
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{Id: &overriddenZone}, {Id: &scheduledZone}, {Id: &heatingZone}}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{
+		"1": {Setting: &tado.ZoneSetting{Type: &hotWaterType, Power: &on}, Overlay: &tado.ZoneOverlay{}},
+		"2": {Setting: &tado.ZoneSetting{Type: &hotWaterType, Power: &on}},
+		"3": {Setting: &tado.ZoneSetting{Type: &heatingType, Power: &on}, Overlay: &tado.ZoneOverlay{}},
+	}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	collector := NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "")
+
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	value, found := findGaugeValue(t, registry, "tado_hot_water_overlay_active", map[string]string{"zone_id": "1"})
+	require.True(t, found)
+	assert.Equal(t, 1.0, value)
+
+	value, found = findGaugeValue(t, registry, "tado_hot_water_overlay_active", map[string]string{"zone_id": "2"})
+	require.True(t, found)
+	assert.Equal(t, 0.0, value)
+
+	_, found = findGaugeValue(t, registry, "tado_hot_water_overlay_active", map[string]string{"zone_id": "3"})
+	assert.False(t, found, "heating zones have no hot water overlay state")
+}
+
+// TestCollectorOverlayRemainingMetric tests that the countdown of timer overlays is exported, and that
+// overlays lasting until changed, or zones without an overlay, have no countdown
+func TestCollectorOverlayRemainingMetric(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	timerZone, expiryZone, manualZone, scheduledZone := 1, 2, 3, 4
+	timer, manual := tado.ZoneOverlayTerminationTypeTIMER, tado.ZoneOverlayTerminationTypeMANUAL
+	remaining := 1800
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	expiry := clock.Now().Add(time.Hour)
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{Id: &timerZone}, {Id: &expiryZone}, {Id: &manualZone}, {Id: &scheduledZone}}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{
+		"1": {Overlay: &tado.ZoneOverlay{Termination: &tado.ZoneOverlayTermination{Type: &timer, RemainingTimeInSeconds: &remaining}}},
+		"2": {Overlay: &tado.ZoneOverlay{Termination: &tado.ZoneOverlayTermination{Type: &timer, Expiry: &expiry}}},
+		"3": {Overlay: &tado.ZoneOverlay{Termination: &tado.ZoneOverlayTermination{Type: &manual}}},
+		"4": {},
+	}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	collector := NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "").WithClock(clock)
+
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	value, found := findGaugeValue(t, registry, "tado_zone_overlay_remaining_seconds", map[string]string{"zone_id": "1"})
+	require.True(t, found)
+	assert.Equal(t, 1800.0, value)
+
+	value, found = findGaugeValue(t, registry, "tado_zone_overlay_remaining_seconds", map[string]string{"zone_id": "2"})
+	require.True(t, found)
+	assert.Equal(t, 3600.0, value, "the countdown is derived from the expiry when no remaining time is reported")
+
+	_, found = findGaugeValue(t, registry, "tado_zone_overlay_remaining_seconds", map[string]string{"zone_id": "3"})
+	assert.False(t, found, "overlays lasting until changed have no countdown")
+
+	_, found = findGaugeValue(t, registry, "tado_zone_overlay_remaining_seconds", map[string]string{"zone_id": "4"})
+	assert.False(t, found, "zones following their schedule have no countdown")
+}
+
+// TestCollectorFrostProtectionMetric tests that heating zones powered on at the frost protection minimum are
+// reported as frost protected, and that hot water zones are skipped
+func TestCollectorFrostProtectionMetric(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	frostZone, comfortZone, offZone, hotWaterZone := 1, 2, 3, 4
+	heatingType, hotWaterType := tado.HEATING, tado.HOTWATER
+	on, off := tado.PowerON, tado.PowerOFF
+	frost, comfort := float32(5), float32(21)
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{Id: &frostZone}, {Id: &comfortZone}, {Id: &offZone}, {Id: &hotWaterZone}}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{
+		"1": {Setting: &tado.ZoneSetting{Type: &heatingType, Power: &on, Temperature: &tado.Temperature{Celsius: &frost}}},
+		"2": {Setting: &tado.ZoneSetting{Type: &heatingType, Power: &on, Temperature: &tado.Temperature{Celsius: &comfort}}},
+		"3": {Setting: &tado.ZoneSetting{Type: &heatingType, Power: &off}},
+		"4": {Setting: &tado.ZoneSetting{Type: &hotWaterType, Power: &on, Temperature: &tado.Temperature{Celsius: &frost}}},
+	}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	collector := NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "")
+
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	value, found := findGaugeValue(t, registry, "tado_zone_frost_protection_active", map[string]string{"zone_id": "1"})
+	require.True(t, found)
+	assert.Equal(t, 1.0, value)
+
+	value, found = findGaugeValue(t, registry, "tado_zone_frost_protection_active", map[string]string{"zone_id": "2"})
+	require.True(t, found)
+	assert.Equal(t, 0.0, value)
+
+	value, found = findGaugeValue(t, registry, "tado_zone_frost_protection_active", map[string]string{"zone_id": "3"})
+	require.True(t, found)
+	assert.Equal(t, 0.0, value, "a powered off zone is not frost protected")
+
+	_, found = findGaugeValue(t, registry, "tado_zone_frost_protection_active", map[string]string{"zone_id": "4"})
+	assert.False(t, found, "hot water zones have no frost protection state")
+}
+
+// TestCollectorTrackedSeriesEviction tests that series of a zone removed from its home are deleted
+// and tado_exporter_tracked_series_total follows the zones exported
+func TestCollectorTrackedSeriesEviction(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+	exporterMetrics := metrics.NewExporterMetricsUnregistered()
+	require.NoError(t, exporterMetrics.RegisterWith(registry))
+
+	zone1, zone2, zone3 := 1, 2, 3
+	temperature := float32(20.5)
+	sensorData := &tado.SensorDataPoints{InsideTemperature: &tado.TemperatureDataPoint{Celsius: &temperature}}
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{Id: &zone1}, {Id: &zone2}, {Id: &zone3}}, nil).Once()
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{Id: &zone1}, {Id: &zone2}}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{
+		"1": {SensorDataPoints: sensorData},
+		"2": {SensorDataPoints: sensorData},
+		"3": {SensorDataPoints: sensorData},
+	}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	log, err := logger.NewWithWriter("error", "text", io.Discard)
+	require.NoError(t, err)
+
+	collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log).
+		WithExporterMetrics(exporterMetrics)
+	scrape := func() {
+		ch := make(chan prometheus.Metric, 200)
+		collector.Collect(ch)
+		close(ch)
+	}
+
+	scrape()
+
+	tracked, found := findGaugeValue(t, registry, "tado_exporter_tracked_series_total", nil)
+	require.True(t, found)
+	assert.Equal(t, 3.0, tracked)
+	_, found = findGaugeValue(t, registry, "tado_temperature_measured_celsius", map[string]string{"zone_id": "3"})
+	assert.True(t, found)
+
+	// The second scrape no longer lists zone 3
+	scrape()
+	tracked, found = findGaugeValue(t, registry, "tado_exporter_tracked_series_total", nil)
+	require.True(t, found)
+	assert.Equal(t, 2.0, tracked)
+	_, found = findGaugeValue(t, registry, "tado_temperature_measured_celsius", map[string]string{"zone_id": "3"})
+	assert.False(t, found, "series of the removed zone are evicted")
+	_, found = findGaugeValue(t, registry, "tado_temperature_measured_celsius", map[string]string{"zone_id": "1"})
+	assert.True(t, found)
+}
+
+// TestCollectorZoneTypeChangeEviction tests that a zone changing type keeps only its series with the new zone_type
+func TestCollectorZoneTypeChangeEviction(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	zoneID := 1
+	heatingType, hotWaterType := tado.HEATING, tado.HOTWATER
+	temperature := float32(20.5)
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{Id: &zoneID, Type: &heatingType}}, nil).Once()
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{Id: &zoneID, Type: &hotWaterType}}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{
+		"1": {SensorDataPoints: &tado.SensorDataPoints{InsideTemperature: &tado.TemperatureDataPoint{Celsius: &temperature}}},
+	}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	log, err := logger.NewWithWriter("error", "text", io.Discard)
+	require.NoError(t, err)
+
+	collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log)
+	for i := 0; i < 2; i++ {
+		ch := make(chan prometheus.Metric, 200)
+		collector.Collect(ch)
+		close(ch)
+	}
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	var zoneTypes []string
+	for _, family := range families {
+		if family.GetName() != "tado_temperature_measured_celsius" {
+			continue
+		}
+		for _, m := range family.Metric {
+			for _, pair := range m.Label {
+				if pair.GetName() == "zone_type" {
+					zoneTypes = append(zoneTypes, pair.GetValue())
+				}
+			}
+		}
+	}
+	assert.Equal(t, []string{string(tado.HOTWATER)}, zoneTypes, "only the series with the new zone type remains")
+}
+
+// TestCollectorTemperatureUnits tests that Kelvin is only exported when configured and that
+// units left out of the configuration are not emitted
+func TestCollectorTemperatureUnits(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		units            []metrics.TemperatureUnit
+		expectKelvin     bool
+		expectFahrenheit bool
+	}{
+		{name: "default units", units: nil, expectKelvin: false, expectFahrenheit: true},
+		{name: "celsius and kelvin", units: []metrics.TemperatureUnit{metrics.TemperatureUnitCelsius, metrics.TemperatureUnitKelvin}, expectKelvin: true, expectFahrenheit: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+			require.NoError(t, err)
+
+			zoneID := 1
+			celsius, fahrenheit := float32(20), float32(68)
+
+			mockAPI := &mocks.MockTadoAPI{}
+			mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+			mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+			mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{Id: &zoneID}}, nil)
+			mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{
+				"1": {SensorDataPoints: &tado.SensorDataPoints{InsideTemperature: &tado.TemperatureDataPoint{Celsius: &celsius, Fahrenheit: &fahrenheit}}},
+			}}, nil)
+			mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+			mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+			mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+			collector := NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "").WithTemperatureUnits(tt.units)
+			registry := prometheus.NewRegistry()
+			require.NoError(t, collector.RegisterWith(registry))
+
+			kelvin, found := findGaugeValue(t, registry, "tado_temperature_measured_kelvin", map[string]string{"zone_id": "1"})
+			assert.Equal(t, tt.expectKelvin, found)
+			if tt.expectKelvin {
+				assert.InDelta(t, 293.15, kelvin, 1e-9)
+			}
+
+			_, found = findGaugeValue(t, registry, "tado_temperature_measured_fahrenheit", map[string]string{"zone_id": "1"})
+			assert.Equal(t, tt.expectFahrenheit, found)
+
+			value, found := findGaugeValue(t, registry, "tado_temperature_measured_celsius", map[string]string{"zone_id": "1"})
+			require.True(t, found)
+			assert.Equal(t, 20.0, value)
+		})
+	}
+}
+
+// TestCollectorTemperaturePrecision tests that temperatures are rounded to the configured decimal places
+// and left untouched by default
+func TestCollectorTemperaturePrecision(t *testing.T) {
+	t.Parallel()
+
+	measured, target, outside := float32(20.3400002), float32(21.0600001), float32(9.87)
+
+	tests := []struct {
+		name           string
+		precision      int
+		expectMeasured float64
+		expectTarget   float64
+		expectOutside  float64
+	}{
+		{name: "one decimal place", precision: 1, expectMeasured: 20.3, expectTarget: 21.1, expectOutside: 9.9},
+		{name: "rounding disabled", precision: -1, expectMeasured: float64(measured), expectTarget: float64(target), expectOutside: float64(outside)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+			require.NoError(t, err)
+
+			zoneID := 1
+			mockAPI := &mocks.MockTadoAPI{}
+			mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+			mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+			mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{Id: &zoneID}}, nil)
+			mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{
+				"1": {
+					SensorDataPoints: &tado.SensorDataPoints{InsideTemperature: &tado.TemperatureDataPoint{Celsius: &measured}},
+					Setting:          &tado.ZoneSetting{Temperature: &tado.Temperature{Celsius: &target}},
+				},
+			}}, nil)
+			mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{
+				OutsideTemperature: &tado.TemperatureDataPoint{Celsius: &outside},
+			}, nil)
+			mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+			mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+			collector := NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "").WithTemperaturePrecision(tt.precision)
+			registry := prometheus.NewRegistry()
+			require.NoError(t, collector.RegisterWith(registry))
+
+			value, found := findGaugeValue(t, registry, "tado_temperature_measured_celsius", map[string]string{"zone_id": "1"})
+			require.True(t, found)
+			assert.Equal(t, tt.expectMeasured, value)
+
+			value, found = findGaugeValue(t, registry, "tado_temperature_set_celsius", map[string]string{"zone_id": "1"})
+			require.True(t, found)
+			assert.Equal(t, tt.expectTarget, value)
+
+			value, found = findGaugeValue(t, registry, "tado_temperature_outside_celsius", map[string]string{})
+			require.True(t, found)
+			assert.Equal(t, tt.expectOutside, value)
+		})
+	}
+}
+
+// TestCollectorMaxLabelLength tests that overlong zone names are truncated to the configured label length
+func TestCollectorMaxLabelLength(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	zoneID := 1
+	zoneName := strings.Repeat("a", 1000)
+	temperature := float32(20.5)
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{Id: &zoneID, Name: &zoneName}}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{
+		"1": {SensorDataPoints: &tado.SensorDataPoints{InsideTemperature: &tado.TemperatureDataPoint{Celsius: &temperature}}},
+	}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	log, err := logger.NewWithWriter("error", "text", io.Discard)
+	require.NoError(t, err)
+
+	collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log).WithMaxLabelLength(64)
+
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	expected := strings.Repeat("a", 64-len(truncatedLabelSuffix)) + truncatedLabelSuffix
+	_, found := findGaugeValue(t, registry, "tado_temperature_measured_celsius", map[string]string{"zone_name": expected})
+	assert.True(t, found, "zone_name label should be truncated to 64 characters")
+	assert.Len(t, expected, 64)
+}
+
+// TestCollectorKeepsPresenceWhenMissing tests that a home state without presence leaves the previous value in place
+func TestCollectorKeepsPresenceWhenMissing(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	home := tado.HOME
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{Presence: &home}, nil).Once()
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil).Once()
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	collector := NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "")
+
+	for i := 0; i < 2; i++ {
+		ch := make(chan prometheus.Metric, 100)
+		collector.Collect(ch)
+		close(ch)
+	}
+
+	mockAPI.AssertNumberOfCalls(t, "GetHomeState", 2)
+	value, found := findGaugeValue(t, registry, "tado_is_resident_present", map[string]string{})
+	require.True(t, found)
+	assert.Equal(t, 1.0, value, "missing presence should not be reported as away")
+}
+
+// TestCollectorHomePresenceState tests that the raw presence is exported as a label next to the numeric presence
+func TestCollectorHomePresenceState(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	home, away := tado.HOME, tado.AWAY
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{Presence: &home}, nil).Once()
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{Presence: &away}, nil).Once()
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	collector := NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "")
+
+	for i := 0; i < 2; i++ {
+		ch := make(chan prometheus.Metric, 100)
+		collector.Collect(ch)
+		close(ch)
+	}
+
+	value, found := findGaugeValue(t, registry, "tado_home_presence_state", map[string]string{"home_id": "1", "state": "AWAY"})
+	require.True(t, found)
+	assert.Equal(t, 1.0, value)
+	_, found = findGaugeValue(t, registry, "tado_home_presence_state", map[string]string{"home_id": "1", "state": "HOME"})
+	assert.False(t, found, "the previous state should be replaced, not kept alongside")
+
+	value, found = findGaugeValue(t, registry, "tado_is_resident_present", map[string]string{})
+	require.True(t, found)
+	assert.Equal(t, 0.0, value, "the numeric presence should still report away")
+}
+
+// TestCollectorHomePresenceStatePerHome tests that each home keeps its own presence state
+func TestCollectorHomePresenceStatePerHome(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	home, away := tado.HOME, tado.AWAY
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1, 2})
+	mockAPI.On("GetHomeState", mock.Anything, tado.HomeId(1)).Return(&tado.HomeState{Presence: &home}, nil)
+	mockAPI.On("GetHomeState", mock.Anything, tado.HomeId(2)).Return(&tado.HomeState{Presence: &away}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	collector := NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "")
+
+	for i := 0; i < 2; i++ {
+		ch := make(chan prometheus.Metric, 100)
+		collector.Collect(ch)
+		close(ch)
+	}
+
+	value, found := findGaugeValue(t, registry, "tado_home_presence_state", map[string]string{"home_id": "1", "state": "HOME"})
+	require.True(t, found, "the first home's state should survive collecting the second")
+	assert.Equal(t, 1.0, value)
+	value, found = findGaugeValue(t, registry, "tado_home_presence_state", map[string]string{"home_id": "2", "state": "AWAY"})
+	require.True(t, found)
+	assert.Equal(t, 1.0, value)
+	_, found = findGaugeValue(t, registry, "tado_home_presence_state", map[string]string{"home_id": "1", "state": "AWAY"})
+	assert.False(t, found, "states should not leak between homes")
+}
+
+// TestCollectorDeviceMetricsLevel tests that the device metrics level selects which per-device series are exported
+func TestCollectorDeviceMetricsLevel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		level        metrics.DeviceMetricsLevel
+		wantBasic    bool
+		wantFirmware bool
+	}{
+		{level: metrics.DeviceMetricsOff},
+		{level: metrics.DeviceMetricsBasic, wantBasic: true},
+		{level: metrics.DeviceMetricsFull, wantBasic: true, wantFirmware: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.level), func(t *testing.T) {
+			t.Parallel()
+
+			registry := prometheus.NewRegistry()
+
+			metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+			require.NoError(t, err)
+			require.NoError(t, metricDescs.RegisterWith(registry))
+
+			valveSerial, bridgeSerial := tado.DeviceId("VA1234567890"), tado.DeviceId("IB1234567890")
+			valveType, bridgeType := tado.DeviceType("VA02"), tado.DeviceType("IB01")
+			batteryLow := tado.BatteryStateLOW
+			connected := true
+			valveFirmware, bridgeFirmware := "215.1", "119.2"
+			valve := tado.Device{SerialNo: &valveSerial, DeviceType: &valveType, BatteryState: &batteryLow, CurrentFwVersion: &valveFirmware}
+			valve.ConnectionState = &struct {
+				Timestamp *time.Time `json:"timestamp,omitempty"`
+				Value     *bool      `json:"value,omitempty"`
+			}{Value: &connected}
+			bridge := tado.Device{SerialNo: &bridgeSerial, DeviceType: &bridgeType, CurrentFwVersion: &bridgeFirmware}
+
+			mockAPI := &mocks.MockTadoAPI{}
+			mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+			mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+			mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
+			mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{}}, nil)
+			mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+			mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{valve, bridge}, nil)
+			mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+			collector := NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "").WithDeviceMetrics(tt.level)
+
+			ch := make(chan prometheus.Metric, 100)
+			collector.Collect(ch)
+			close(ch)
+
+			value, found := findGaugeValue(t, registry, "tado_device_battery_low", map[string]string{"home_id": "1", "device_id": "VA1234567890", "device_type": "VA02"})
+			assert.Equal(t, tt.wantBasic, found)
+			if found {
+				assert.Equal(t, 1.0, value)
+			}
+			_, found = findGaugeValue(t, registry, "tado_device_battery_low", map[string]string{"device_id": "IB1234567890"})
+			assert.False(t, found, "devices without a battery have no battery series")
+
+			value, found = findGaugeValue(t, registry, "tado_device_connected", map[string]string{"device_id": "VA1234567890"})
+			assert.Equal(t, tt.wantBasic, found)
+			if found {
+				assert.Equal(t, 1.0, value)
+			}
+
+			_, found = findGaugeValue(t, registry, "tado_device_firmware_info", map[string]string{"device_id": "VA1234567890", "firmware_version": "215.1"})
+			assert.Equal(t, tt.wantFirmware, found)
+			_, found = findGaugeValue(t, registry, "tado_device_firmware_info", map[string]string{"device_id": "IB1234567890", "firmware_version": "119.2"})
+			assert.Equal(t, tt.wantFirmware, found)
+		})
+	}
+}
+
+// TestCollectorDeviceMetricsPerHome tests that collecting one home keeps the device series of the others
+func TestCollectorDeviceMetricsPerHome(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	newDevice := func(serial, firmware string) tado.Device {
+		serialNo, deviceType := tado.DeviceId(serial), tado.DeviceType("VA02")
+		batteryState := tado.BatteryStateNORMAL
+		return tado.Device{SerialNo: &serialNo, DeviceType: &deviceType, BatteryState: &batteryState, CurrentFwVersion: &firmware}
+	}
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1, 2})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, tado.HomeId(1)).Return([]tado.Device{newDevice("VA1111111111", "215.1")}, nil)
+	mockAPI.On("GetDevices", mock.Anything, tado.HomeId(2)).Return([]tado.Device{newDevice("VA2222222222", "215.2")}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	collector := NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "").WithDeviceMetrics(metrics.DeviceMetricsFull)
+
+	for i := 0; i < 2; i++ {
+		ch := make(chan prometheus.Metric, 100)
+		collector.Collect(ch)
+		close(ch)
+	}
+
+	value, found := findGaugeValue(t, registry, "tado_device_battery_low", map[string]string{"home_id": "1", "device_id": "VA1111111111"})
+	require.True(t, found, "the first home's devices should survive collecting the second")
+	assert.Equal(t, 0.0, value)
+	_, found = findGaugeValue(t, registry, "tado_device_battery_low", map[string]string{"home_id": "2", "device_id": "VA2222222222"})
+	assert.True(t, found)
+
+	_, found = findGaugeValue(t, registry, "tado_device_firmware_info", map[string]string{"home_id": "1", "firmware_version": "215.1"})
+	assert.True(t, found)
+	_, found = findGaugeValue(t, registry, "tado_device_firmware_info", map[string]string{"home_id": "2", "firmware_version": "215.2"})
+	assert.True(t, found)
+}
+
+// TestCollectorHomePresenceLocked tests that the presence lock from the home state is exported
+func TestCollectorHomePresenceLocked(t *testing.T) {
+	t.Parallel()
+
+	locked, auto := true, false
+
+	tests := []struct {
+		name           string
+		presenceLocked *bool
+		expected       float64
+	}{
+		{name: "manually locked", presenceLocked: &locked, expected: 1.0},
+		{name: "auto geofencing", presenceLocked: &auto, expected: 0.0},
+		{name: "not reported", presenceLocked: nil, expected: 0.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := prometheus.NewRegistry()
+
+			metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+			require.NoError(t, err)
+			require.NoError(t, metricDescs.RegisterWith(registry))
+
+			mockAPI := &mocks.MockTadoAPI{}
+			mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+			mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{PresenceLocked: tt.presenceLocked}, nil)
+			mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
+			mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{}, nil)
+			mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+			mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+			mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+			log, err := logger.NewWithWriter("error", "text", io.Discard)
+			require.NoError(t, err)
+
+			collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log)
+
+			ch := make(chan prometheus.Metric, 100)
+			collector.Collect(ch)
+			close(ch)
+
+			value, found := findGaugeValue(t, registry, "tado_home_presence_locked", map[string]string{})
+			require.True(t, found)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+// TestCollectorWeatherIsDaytime tests that daytime is derived from the solar intensity
+func TestCollectorWeatherIsDaytime(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		solarIntensity float32
+		expected       float64
+	}{
+		{name: "night", solarIntensity: 0, expected: 0.0},
+		{name: "day", solarIntensity: 50, expected: 1.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := prometheus.NewRegistry()
+
+			metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+			require.NoError(t, err)
+			require.NoError(t, metricDescs.RegisterWith(registry))
+
+			// Start from the opposite value so the assertion proves the metric was set
+			metricDescs.WeatherIsDaytime.WithLabelValues("1").Set(1 - tt.expected)
+
+			solarIntensity := tt.solarIntensity
+			mockAPI := &mocks.MockTadoAPI{}
+			mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+			mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+			mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
+			mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{}, nil)
+			mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{
+				SolarIntensity: &tado.PercentageDataPoint{Percentage: &solarIntensity},
+			}, nil)
+			mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+			mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+			log, err := logger.NewWithWriter("error", "text", io.Discard)
+			require.NoError(t, err)
+
+			collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log)
+
+			ch := make(chan prometheus.Metric, 100)
+			collector.Collect(ch)
+			close(ch)
+
+			value, found := findGaugeValue(t, registry, "tado_weather_is_daytime", map[string]string{})
+			require.True(t, found)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+// TestCollectorHomeBridgeConnected tests that the internet bridge connection state is exported
+func TestCollectorHomeBridgeConnected(t *testing.T) {
+	t.Parallel()
+
+	bridge, thermostat := "IB01", "RU02"
+
+	device := func(deviceType *string, connected bool) tado.Device {
+		d := tado.Device{DeviceType: deviceType}
+		d.ConnectionState = &struct {
+			Timestamp *time.Time `json:"timestamp,omitempty"`
+			Value     *bool      `json:"value,omitempty"`
+		}{Value: &connected}
+		return d
+	}
+
+	tests := []struct {
+		name     string
+		devices  []tado.Device
+		expected float64
+	}{
+		{name: "bridge connected", devices: []tado.Device{device(&bridge, true)}, expected: 1.0},
+		{name: "bridge disconnected", devices: []tado.Device{device(&bridge, false), device(&thermostat, true)}, expected: 0.0},
+		{name: "other devices ignored", devices: []tado.Device{device(&bridge, true), device(&thermostat, false)}, expected: 1.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := prometheus.NewRegistry()
+
+			metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+			require.NoError(t, err)
+			require.NoError(t, metricDescs.RegisterWith(registry))
+
+			// Start from the opposite value so the assertion proves the metric was set
+			metricDescs.HomeBridgeConnected.WithLabelValues("1").Set(1 - tt.expected)
+
+			mockAPI := &mocks.MockTadoAPI{}
+			mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+			mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+			mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
+			mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{}, nil)
+			mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+			mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return(tt.devices, nil)
+			mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+			log, err := logger.NewWithWriter("error", "text", io.Discard)
+			require.NoError(t, err)
+
+			collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log)
+
+			ch := make(chan prometheus.Metric, 100)
+			collector.Collect(ch)
+			close(ch)
+
+			value, found := findGaugeValue(t, registry, "tado_home_bridge_connected", map[string]string{})
+			require.True(t, found)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+// TestCollectorHomeMetricsPerHome tests that the home-level metrics of an account with several homes
+// are kept apart by home_id rather than each home overwriting the previous one
+func TestCollectorHomeMetricsPerHome(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	locked, unlocked := true, false
+	atHome := true
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1, 2})
+	mockAPI.On("GetHomeState", mock.Anything, tado.HomeId(1)).Return(&tado.HomeState{PresenceLocked: &locked}, nil)
+	mockAPI.On("GetHomeState", mock.Anything, tado.HomeId(2)).Return(&tado.HomeState{PresenceLocked: &unlocked}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, tado.HomeId(1)).Return([]tado.Device{{}, {}, {}}, nil)
+	mockAPI.On("GetDevices", mock.Anything, tado.HomeId(2)).Return([]tado.Device{{}}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, tado.HomeId(1)).Return([]tado.MobileDevice{{Location: &tado.MobileDeviceLocation{AtHome: &atHome}}}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, tado.HomeId(2)).Return([]tado.MobileDevice{}, nil)
+
+	collector := NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "")
+
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	expected := map[string]map[string]float64{
+		"tado_home_devices_total":         {"1": 3, "2": 1},
+		"tado_home_devices_at_home_total": {"1": 1, "2": 0},
+		"tado_home_presence_locked":       {"1": 1, "2": 0},
+	}
+	for name, byHome := range expected {
+		for homeID, want := range byHome {
+			value, found := findGaugeValue(t, registry, name, map[string]string{"home_id": homeID})
+			require.True(t, found, "%s for home %s", name, homeID)
+			assert.Equal(t, want, value, "%s for home %s", name, homeID)
+		}
+	}
+}
+
+// TestCollectorHomeDevicesTotal tests that the home's device count is exported and kept when listing devices fails
+func TestCollectorHomeDevicesTotal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		devices    []tado.Device
+		devicesErr error
+		expected   float64
+	}{
+		{name: "three devices", devices: []tado.Device{{}, {}, {}}, expected: 3.0},
+		{name: "device list error leaves metric unchanged", devicesErr: fmt.Errorf("devices unavailable"), expected: 5.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := prometheus.NewRegistry()
+
+			metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+			require.NoError(t, err)
+			require.NoError(t, metricDescs.RegisterWith(registry))
+
+			// Value from a previous scrape
+			metricDescs.HomeDevicesTotal.WithLabelValues("1").Set(5)
+
+			mockAPI := &mocks.MockTadoAPI{}
+			mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+			mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+			mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
+			mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{}, nil)
+			mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+			if tt.devicesErr != nil {
+				mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return(nil, tt.devicesErr)
+			} else {
+				mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return(tt.devices, nil)
+			}
+			mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+			log, err := logger.NewWithWriter("error", "text", io.Discard)
+			require.NoError(t, err)
+
+			collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log)
+
+			ch := make(chan prometheus.Metric, 100)
+			collector.Collect(ch)
+			close(ch)
+
+			value, found := findGaugeValue(t, registry, "tado_home_devices_total", map[string]string{})
+			require.True(t, found)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+// TestCollectorHomeDevicesAtHome tests that only mobile devices located at home are counted
+func TestCollectorHomeDevicesAtHome(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	atHome, away := true, false
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{
+		{Location: &tado.MobileDeviceLocation{AtHome: &atHome}},
+		{Location: &tado.MobileDeviceLocation{AtHome: &away}},
+		{}, // Geo-tracking disabled, no location reported
+	}, nil)
+
+	collector := NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "")
+
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	value, found := findGaugeValue(t, registry, "tado_home_devices_at_home_total", map[string]string{})
+	require.True(t, found)
+	assert.Equal(t, 1.0, value)
+}
+
+// TestCollectorHomeDevicesAtHomeWhenDevicesFail tests that a failing GetDevices doesn't stop the at-home count
+func TestCollectorHomeDevicesAtHomeWhenDevicesFail(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	atHome := true
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return(nil, errors.New("devices unavailable"))
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{
+		{Location: &tado.MobileDeviceLocation{AtHome: &atHome}},
+		{Location: &tado.MobileDeviceLocation{AtHome: &atHome}},
+	}, nil)
+
+	collector := NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "")
+
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	mockAPI.AssertCalled(t, "GetMobileDevices", mock.Anything, mock.Anything)
+	value, found := findGaugeValue(t, registry, "tado_home_devices_at_home_total", map[string]string{"home_id": "1"})
+	require.True(t, found)
+	assert.Equal(t, 2.0, value)
+}
+
+// TestCollectorHomeCollectionDuration tests that each home's collection time is recorded under its own home_id
+func TestCollectorHomeCollectionDuration(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	exporterMetrics := metrics.NewExporterMetricsUnregistered()
+	require.NoError(t, exporterMetrics.RegisterWith(registry))
+
+	// Home 2's API is slow
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1, 2})
+	mockAPI.On("GetHomeState", mock.Anything, tado.HomeId(1)).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetHomeState", mock.Anything, tado.HomeId(2)).Return(&tado.HomeState{}, nil).After(150 * time.Millisecond)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	log, err := logger.NewWithWriter("error", "text", io.Discard)
+	require.NoError(t, err)
+
+	collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log).
+		WithExporterMetrics(exporterMetrics)
+
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	sums := map[string]float64{}
+	for _, family := range families {
+		if family.GetName() != "tado_exporter_home_collection_duration_seconds" {
+			continue
+		}
+		for _, m := range family.Metric {
+			assert.Equal(t, uint64(1), m.GetHistogram().GetSampleCount())
+			sums[m.Label[0].GetValue()] = m.GetHistogram().GetSampleSum()
+		}
+	}
+
+	require.Len(t, sums, 2, "both homes should have a duration sample")
+	assert.GreaterOrEqual(t, sums["2"], 0.15)
+	assert.Less(t, sums["1"], sums["2"])
+}
+
+// TestCollectorTokenValidSeconds tests that the remaining token lifetime is reported on each scrape
+func TestCollectorTokenValidSeconds(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	exporterMetrics := metrics.NewExporterMetricsUnregistered()
+	require.NoError(t, exporterMetrics.RegisterWith(registry))
+
+	mockAPI := (&mocks.MockTadoAPI{}).ExpectAllAPICalls()
+
+	log, err := logger.NewWithWriter("error", "text", io.Discard)
+	require.NoError(t, err)
+
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	expiry := clock.Now().Add(2 * time.Hour)
+	collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log).
+		WithExporterMetrics(exporterMetrics).
+		WithTokenExpiry(func() (time.Time, bool) { return expiry, true }).
+		WithClock(clock)
+
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	value, found := findGaugeValue(t, registry, "tado_exporter_token_valid_seconds", nil)
+	require.True(t, found)
+	assert.Equal(t, 7200.0, value)
+}
+
+// TestCollectorRegisterWithIsolatedRegistry tests that the whole pipeline can be served from an isolated
+// registry without registering anything with the Prometheus default registry
+func TestCollectorRegisterWithIsolatedRegistry(t *testing.T) {
+	t.Parallel()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	exporterMetrics := metrics.NewExporterMetricsUnregistered()
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{{}, {}}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	collector := NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "").
+		WithExporterMetrics(exporterMetrics)
+
+	registry := prometheus.NewRegistry()
+	require.NoError(t, collector.RegisterWith(registry))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	names := make(map[string]bool, len(families))
+	for _, family := range families {
+		names[family.GetName()] = true
+	}
+	assert.True(t, names["tado_home_devices_total"], "Tado metrics are served from the isolated registry")
+	assert.True(t, names["tado_exporter_scrape_duration_seconds"], "exporter metrics are served from the isolated registry")
+
+	defaultFamilies, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	for _, family := range defaultFamilies {
+		assert.False(t, strings.HasPrefix(family.GetName(), "tado_"), "%s registered with the default registry", family.GetName())
+	}
+}
+
+// TestCollectorObservedScrapeInterval tests that the time between Collect calls is exported, starting from the second call
+func TestCollectorObservedScrapeInterval(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	exporterMetrics := metrics.NewExporterMetricsUnregistered()
+	require.NoError(t, exporterMetrics.RegisterWith(registry))
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	collector := NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "").
+		WithExporterMetrics(exporterMetrics)
+
+	collect := func() {
+		ch := make(chan prometheus.Metric, 100)
+		collector.Collect(ch)
+		close(ch)
+	}
+
+	collect()
+	value, found := findGaugeValue(t, registry, "tado_exporter_observed_scrape_interval_seconds", map[string]string{})
+	require.True(t, found)
+	assert.Equal(t, 0.0, value, "no interval is known after the first scrape")
+
+	interval := 100 * time.Millisecond
+	time.Sleep(interval)
+	collect()
+
+	value, found = findGaugeValue(t, registry, "tado_exporter_observed_scrape_interval_seconds", map[string]string{})
+	require.True(t, found)
+	assert.GreaterOrEqual(t, value, interval.Seconds())
+	assert.InDelta(t, interval.Seconds(), value, 0.05)
+}
+
+// TestCollectorRecoversFromPanic tests that a panic during collection is counted and logged,
+// and that the scrape still serves the last known values
+func TestCollectorRecoversFromPanic(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	exporterMetrics := metrics.NewExporterMetricsUnregistered()
+	require.NoError(t, exporterMetrics.RegisterWith(registry))
+
+	// Value from a previous scrape
+	metricDescs.TemperatureMeasuredCelsius.WithLabelValues("1", "1", "Living Room", "HEATING").Set(20.5)
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		panic("malformed zones response")
+	})
+
+	var logOutput bytes.Buffer
+	log, err := logger.NewWithWriter("error", "json", &logOutput)
+	require.NoError(t, err)
+
+	collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log).
+		WithExporterMetrics(exporterMetrics)
+
+	ch := make(chan prometheus.Metric, 100)
+	require.NotPanics(t, func() { collector.Collect(ch) })
+	close(ch)
+	assert.Greater(t, len(ch), 0)
+
+	assert.Equal(t, 1.0, findCounterValue(t, registry, "tado_exporter_collector_panics_total"))
+	assert.Equal(t, 1.0, findCounterValue(t, registry, "tado_exporter_scrape_errors_total"))
+
+	value, found := findGaugeValue(t, registry, "tado_temperature_measured_celsius", map[string]string{"zone_id": "1"})
+	require.True(t, found, "the last known values should still be served")
+	assert.Equal(t, 20.5, value)
+
+	assert.Contains(t, logOutput.String(), "Recovered from panic while collecting Tado metrics")
+	assert.Contains(t, logOutput.String(), "malformed zones response")
+	assert.True(t, collector.LastSuccessfulScrape().IsZero(), "a panicking scrape must not count as successful")
+}
+
+// TestCollectorSkipWeather tests that GetWeather is not called when weather collection is skipped
+func TestCollectorSkipWeather(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{{}, {}}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	collector := NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "").
+		WithSkipWeather(true)
+
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	mockAPI.AssertNotCalled(t, "GetWeather", mock.Anything, mock.Anything)
+	assert.False(t, collector.LastSuccessfulScrape().IsZero(), "skipping weather should not fail the scrape")
+
+	// The rest of the home is still collected
+	value, found := findGaugeValue(t, registry, "tado_home_devices_total", map[string]string{})
+	require.True(t, found)
+	assert.Equal(t, 2.0, value)
+}
+
+// TestCollectorScrapeSuccess tests that the scrape success gauge reflects whether collection returned an error
+func TestCollectorScrapeSuccess(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		setup    func(m *mocks.MockTadoAPI)
+		expected float64
+	}{
+		{
+			name: "successful scrape",
+			setup: func(m *mocks.MockTadoAPI) {
+				m.ExpectGetMeReturnsHomes([]int64{1})
+				m.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+				m.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
+				m.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{}}, nil)
+				m.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+				m.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+				m.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+			},
+			expected: 1.0,
+		},
+		{
+			name:     "failed scrape",
+			setup:    func(m *mocks.MockTadoAPI) { m.ExpectGetMeReturnsError(fmt.Errorf("API error")) },
+			expected: 0.0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := prometheus.NewRegistry()
+
+			metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+			require.NoError(t, err)
+
+			exporterMetrics := metrics.NewExporterMetricsUnregistered()
+			require.NoError(t, exporterMetrics.RegisterWith(registry))
+
+			mockAPI := &mocks.MockTadoAPI{}
+			tt.setup(mockAPI)
+
+			collector := NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "").
+				WithExporterMetrics(exporterMetrics)
+
+			ch := make(chan prometheus.Metric, 100)
+			collector.Collect(ch)
+			close(ch)
+
+			value, found := findGaugeValue(t, registry, "tado_exporter_scrape_success", map[string]string{})
+			require.True(t, found)
+			assert.Equal(t, tt.expected, value)
+
+			// Only a successfully fetched home reports a data age
+			_, found = findGaugeValue(t, registry, "tado_exporter_data_age_seconds", map[string]string{"home_id": "1"})
+			assert.Equal(t, tt.expected == 1.0, found)
+		})
+	}
+}
+
+// TestCollectorAccountInfo tests that the account email is hashed by default and only exposed raw when opted in
+func TestCollectorAccountInfo(t *testing.T) {
+	t.Parallel()
+
+	const email = "Someone@Example.com"
+
+	tests := []struct {
+		name          string
+		exposeEmail   bool
+		expectedEmail string
+	}{
+		{"email hashed by default", false, ""},
+		{"raw email exposed when enabled", true, email},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := prometheus.NewRegistry()
+
+			metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+			require.NoError(t, err)
+
+			exporterMetrics := metrics.NewExporterMetricsUnregistered()
+			require.NoError(t, exporterMetrics.RegisterWith(registry))
+
+			homeID := tado.HomeId(1)
+			homes := []tado.HomeBase{{Id: &homeID}}
+			userEmail := email
+
+			mockAPI := &mocks.MockTadoAPI{}
+			mockAPI.On("GetMe", mock.Anything).Return(&tado.User{Email: &userEmail, Homes: &homes}, nil)
+			mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+			mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
+			mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{}, nil)
+			mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+			mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+			mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+			collector := NewTadoCollector(mockAPI, metricDescs, 5*time.Second, "").
+				WithExporterMetrics(exporterMetrics).
+				WithExposeAccountEmail(tt.exposeEmail)
+
+			ch := make(chan prometheus.Metric, 100)
+			collector.Collect(ch)
+			close(ch)
+
+			hash := sha256.Sum256([]byte("someone@example.com"))
+			value, found := findGaugeValue(t, registry, "tado_exporter_account_info", map[string]string{
+				"account_hash": hex.EncodeToString(hash[:])[:16],
+				"email":        tt.expectedEmail,
+			})
+			require.True(t, found)
+			assert.Equal(t, 1.0, value)
+
+			if !tt.exposeEmail {
+				families, err := registry.Gather()
+				require.NoError(t, err)
+				for _, family := range families {
+					for _, m := range family.Metric {
+						for _, label := range m.Label {
+							assert.NotContains(t, label.GetValue(), "@", "raw email leaked on %s", family.GetName())
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestCollectorOldestZoneReading tests that tado_exporter_oldest_zone_reading_unix is the oldest timestamp across all zones
+func TestCollectorOldestZoneReading(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	exporterMetrics := metrics.NewExporterMetricsUnregistered()
+
+	now := time.Now().Truncate(time.Second)
+	oldest := now.Add(-2 * time.Hour)
+	recent := now.Add(-5 * time.Minute)
+	sensorAt := func(ts time.Time) *tado.SensorDataPoints {
+		temperature := float32(20.5)
+		return &tado.SensorDataPoints{InsideTemperature: &tado.TemperatureDataPoint{Celsius: &temperature, Timestamp: &ts}}
+	}
+
+	zone1, zone2, zone3 := 1, 2, 3
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{Id: &zone1}, {Id: &zone2}, {Id: &zone3}}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{
+		"1": {SensorDataPoints: sensorAt(recent)},
+		"2": {SensorDataPoints: sensorAt(oldest)},
+		"3": {SensorDataPoints: sensorAt(now)},
+	}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	log, err := logger.NewWithWriter("error", "text", io.Discard)
+	require.NoError(t, err)
+
+	collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log).
+		WithExporterMetrics(exporterMetrics)
+	require.NoError(t, registry.Register(collector))
+
+	value, found := findGaugeValue(t, registry, "tado_exporter_oldest_zone_reading_unix", nil)
+	require.True(t, found)
+	assert.Equal(t, float64(oldest.Unix()), value)
+}
+
+// lockedBuffer is a bytes.Buffer that may be read while a logger writes to it
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// TestCollectorConcurrentScrapesShareCollection tests that a scrape arriving while another is fetching
+// from the Tado API joins that fetch instead of calling the API again, unless it may run longer than that fetch
+func TestCollectorConcurrentScrapesShareCollection(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		leaderTimeout time.Duration
+		joinerTimeout time.Duration
+		expectJoin    bool
+	}{
+		{"joiner with an earlier deadline shares the fetch", 10 * time.Second, 5 * time.Second, true},
+		{"joiner with a later deadline fetches separately", 5 * time.Second, 10 * time.Second, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+			require.NoError(t, err)
+
+			started := make(chan struct{})
+			release := make(chan struct{})
+			homeID := tado.HomeId(1)
+			homes := []tado.HomeBase{{Id: &homeID}}
+
+			// Only the first fetch blocks, until released
+			mockAPI := &mocks.MockTadoAPI{}
+			mockAPI.On("GetMe", mock.Anything).Run(func(mock.Arguments) {
+				close(started)
+				<-release
+			}).Return(&tado.User{Homes: &homes}, nil).Once()
+			mockAPI.On("GetMe", mock.Anything).Return(&tado.User{Homes: &homes}, nil)
+			mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+			mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
+			mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{}, nil)
+			mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+			mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+			mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+			var logs lockedBuffer
+			log, err := logger.NewWithWriter("debug", "text", &logs)
+			require.NoError(t, err)
+
+			collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log)
+			scrape := func(wg *sync.WaitGroup, timeout time.Duration) {
+				defer wg.Done()
+				ch := make(chan prometheus.Metric, 200)
+				collector.collect(ch, timeout)
+				close(ch)
+			}
+
+			var leader, joiner sync.WaitGroup
+			leader.Add(1)
+			joiner.Add(1)
+			go scrape(&leader, tt.leaderTimeout)
+			<-started
+			go scrape(&joiner, tt.joinerTimeout)
+
+			if tt.expectJoin {
+				// Only let the first fetch finish once the second scrape has joined it
+				assert.Eventually(t, func() bool {
+					return strings.Contains(logs.String(), "Joining in-flight Tado API collection")
+				}, time.Second, time.Millisecond)
+				close(release)
+				joiner.Wait()
+				leader.Wait()
+				mockAPI.AssertNumberOfCalls(t, "GetMe", 1)
+				mockAPI.AssertNumberOfCalls(t, "GetZones", 1)
+			} else {
+				// The second scrape finishes its own fetch while the first is still blocked
+				joiner.Wait()
+				close(release)
+				leader.Wait()
+				assert.NotContains(t, logs.String(), "Joining in-flight Tado API collection")
+				mockAPI.AssertNumberOfCalls(t, "GetMe", 2)
+				mockAPI.AssertNumberOfCalls(t, "GetZones", 2)
+			}
+			assert.False(t, collector.LastSuccessfulScrape().IsZero(), "both scrapes succeed")
+		})
+	}
+}
+
+// TestCollectorZonesHeating tests that tado_exporter_zones_heating_total counts only zones with heating power above 0%
+func TestCollectorZonesHeating(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	exporterMetrics := metrics.NewExporterMetricsUnregistered()
+
+	heatingAt := func(percentage float32) *tado.ActivityDataPoints {
+		return &tado.ActivityDataPoints{HeatingPower: &tado.PercentageDataPoint{Percentage: &percentage}}
+	}
+
+	zone1, zone2, zone3, zone4 := 1, 2, 3, 4
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{Id: &zone1}, {Id: &zone2}, {Id: &zone3}, {Id: &zone4}}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{
+		"1": {ActivityDataPoints: heatingAt(45)},
+		"2": {ActivityDataPoints: heatingAt(0)},
+		"3": {ActivityDataPoints: heatingAt(100)},
+		"4": {},
+	}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	log, err := logger.NewWithWriter("error", "text", io.Discard)
+	require.NoError(t, err)
+
+	collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log).
+		WithExporterMetrics(exporterMetrics)
+	require.NoError(t, registry.Register(collector))
+
+	value, found := findGaugeValue(t, registry, "tado_exporter_zones_heating_total", nil)
+	require.True(t, found)
+	assert.Equal(t, 2.0, value)
+}
+
+// TestCollectorTemperatureOffsetsSlowDevice tests that a per-zone device call exceeding its timeout only
+// loses its own zone's temperature offset while the other zones' calls complete
+func TestCollectorTemperatureOffsetsSlowDevice(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+
+	leaderDevice := func(serial string) *[]tado.DeviceExtra {
+		return &[]tado.DeviceExtra{{SerialNo: &serial, Duties: &[]string{"ZONE_UI", "ZONE_LEADER"}}}
+	}
+	offset := func(celsius float32) *tado.Temperature {
+		return &tado.Temperature{Celsius: &celsius}
+	}
+
+	zone1, zone2, zone3 := 1, 2, 3
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{
+		{Id: &zone1, Devices: leaderDevice("VA1")},
+		{Id: &zone2, Devices: leaderDevice("VA2")},
+		{Id: &zone3, Devices: leaderDevice("VA3")},
+	}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{
+		"1": {}, "2": {}, "3": {},
+	}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+	mockAPI.On("GetTemperatureOffset", mock.Anything, "VA1").Return(offset(-0.5), nil)
+	mockAPI.On("GetTemperatureOffset", mock.Anything, "VA2").Return(nil, context.DeadlineExceeded).
+		Run(func(args mock.Arguments) { <-args.Get(0).(context.Context).Done() })
+	mockAPI.On("GetTemperatureOffset", mock.Anything, "VA3").Return(offset(1), nil)
+
+	log, err := logger.NewWithWriter("error", "text", io.Discard)
+	require.NoError(t, err)
+
+	collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log).
+		WithTemperatureOffsets(true).
+		WithZoneDeviceCalls(1, 50*time.Millisecond)
+	require.NoError(t, registry.Register(collector))
+
+	start := time.Now()
+	value, found := findGaugeValue(t, registry, "tado_zone_temperature_offset_celsius", map[string]string{"zone_id": "1"})
+	assert.Less(t, time.Since(start), time.Second, "the slow device call is bounded by its own timeout")
+	require.True(t, found)
+	assert.Equal(t, -0.5, value)
+
+	_, found = findGaugeValue(t, registry, "tado_zone_temperature_offset_celsius", map[string]string{"zone_id": "2"})
+	assert.False(t, found, "the slow device's zone has no offset")
+
+	value, found = findGaugeValue(t, registry, "tado_zone_temperature_offset_celsius", map[string]string{"zone_id": "3"})
+	require.True(t, found)
+	assert.Equal(t, 1.0, value)
+}
+
+// TestCollectorRefreshIfStale tests that a refresh fetches only when no fetch is recent, and that the
+// last values are collected without calling the Tado API or counting as a scrape
+func TestCollectorRefreshIfStale(t *testing.T) {
+	t.Parallel()
+
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	exporterMetrics := metrics.NewExporterMetricsUnregistered()
+
+	temperature := float32(20.5)
+	zoneID := 1
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.ExpectGetMeReturnsHomes([]int64{1})
+	mockAPI.On("GetHomeState", mock.Anything, mock.Anything).Return(&tado.HomeState{}, nil)
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{Id: &zoneID}}, nil)
+	mockAPI.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &map[string]tado.ZoneState{
+		"1": {SensorDataPoints: &tado.SensorDataPoints{InsideTemperature: &tado.TemperatureDataPoint{Celsius: &temperature}}},
+	}}, nil)
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	mockAPI.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	mockAPI.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
+
+	log, err := logger.NewWithWriter("error", "text", io.Discard)
+	require.NoError(t, err)
+
+	collector := NewTadoCollectorWithLogger(mockAPI, metricDescs, 5*time.Second, "", log).
+		WithExporterMetrics(exporterMetrics)
+	registry := prometheus.NewRegistry()
+	require.NoError(t, collector.RegisterLastValuesWith(registry))
+
+	_, found := findGaugeValue(t, registry, "tado_temperature_measured_celsius", nil)
+	assert.False(t, found, "nothing is fetched before the first refresh")
+	mockAPI.AssertNotCalled(t, "GetMe", mock.Anything)
+
+	require.NoError(t, collector.RefreshIfStale(context.Background(), time.Minute))
+	require.NoError(t, collector.RefreshIfStale(context.Background(), time.Minute))
+	mockAPI.AssertNumberOfCalls(t, "GetMe", 1)
+	assert.False(t, collector.LastSuccessfulScrape().IsZero())
+
+	value, found := findGaugeValue(t, registry, "tado_temperature_measured_celsius", map[string]string{"zone_id": "1"})
+	require.True(t, found)
+	assert.Equal(t, 20.5, value)
+	value, found = findGaugeValue(t, registry, "tado_exporter_scrape_success", nil)
+	require.True(t, found)
+	assert.Equal(t, 1.0, value)
+	mockAPI.AssertNumberOfCalls(t, "GetMe", 1)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() == "tado_exporter_scrape_duration_seconds" {
+			assert.Zero(t, family.Metric[0].GetHistogram().GetSampleCount(), "refreshes aren't scrapes")
+		}
+	}
+
+	// A refresh with no maximum age always fetches
+	require.NoError(t, collector.RefreshIfStale(context.Background(), 0))
+	mockAPI.AssertNumberOfCalls(t, "GetMe", 2)
 }
@@ -0,0 +1,41 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	"golang.org/x/oauth2"
+)
+
+// Error categories exposed as the reason label of tado_exporter_last_error_info
+const (
+	errorCategoryAuth      = "auth"
+	errorCategoryTimeout   = "timeout"
+	errorCategoryRateLimit = "rate_limit"
+	errorCategoryAPI       = "api"
+)
+
+// categorizeError returns the category of a collection error
+// Errors that are neither timeouts, rate limiting nor authentication failures count as API errors
+func categorizeError(err error) string {
+	var netErr net.Error
+	var statusErr *StatusError
+	var retrieveErr *oauth2.RetrieveError
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return errorCategoryTimeout
+	case errors.Is(err, ErrRateLimited):
+		return errorCategoryRateLimit
+	case errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusTooManyRequests:
+		return errorCategoryRateLimit
+	case errors.As(err, &statusErr) && (statusErr.StatusCode == http.StatusUnauthorized || statusErr.StatusCode == http.StatusForbidden):
+		return errorCategoryAuth
+	case errors.As(err, &retrieveErr):
+		return errorCategoryAuth
+	default:
+		return errorCategoryAPI
+	}
+}
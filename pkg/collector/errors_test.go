@@ -0,0 +1,37 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+// TestCategorizeError tests that collection errors map to the reasons of tado_exporter_last_error_info
+func TestCategorizeError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "deadline exceeded", err: fmt.Errorf("failed to get zones: %w", context.DeadlineExceeded), want: "timeout"},
+		{name: "rate limiter", err: fmt.Errorf("%w: next request allowed in 2s", ErrRateLimited), want: "rate_limit"},
+		{name: "too many requests", err: &StatusError{Operation: "get zones", StatusCode: 429}, want: "rate_limit"},
+		{name: "unauthorized", err: &StatusError{Operation: "get me", StatusCode: 401}, want: "auth"},
+		{name: "token refresh", err: fmt.Errorf("failed to get me: %w", &oauth2.RetrieveError{}), want: "auth"},
+		{name: "server error", err: &StatusError{Operation: "get zones", StatusCode: 500}, want: "api"},
+		{name: "maintenance", err: &MaintenanceError{Operation: "get zones", StatusCode: 503}, want: "api"},
+		{name: "other", err: errors.New("zone states are nil"), want: "api"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, categorizeError(tt.err))
+		})
+	}
+}
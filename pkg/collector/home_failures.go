@@ -0,0 +1,128 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// DefaultHomeFailureCooldown is the default time a consistently failing home is skipped for before it is retried
+const DefaultHomeFailureCooldown = 15 * time.Minute
+
+// homeFailures tracks a home's consecutive failed scrapes
+type homeFailures struct {
+	consecutive int       // Scrapes in a row in which collecting the home failed
+	skipUntil   time.Time // The home is skipped until this time; zero while it is collected
+}
+
+// WithHomeFailureSkip skips a home for cooldown once threshold scrapes in a row lost access to it,
+// e.g. after access to a shared home was revoked, so it stops using up the API budget. Only failures
+// reported by isHomeAccessLost count; an auxiliary endpoint such as the weather failing never skips the home. Once the cooldown
+// ends the home is tried again: a success resumes collecting it, a failure skips it for another cooldown
+// A threshold of 0 disables skipping, the default; a non-positive cooldown keeps DefaultHomeFailureCooldown
+func (tc *TadoCollector) WithHomeFailureSkip(threshold int, cooldown time.Duration) *TadoCollector {
+	tc.homeFailureThreshold = threshold
+	if cooldown > 0 {
+		tc.homeFailureCooldown = cooldown
+	}
+	return tc
+}
+
+// skipFailingHome reports whether the home should be skipped by this scrape because it is in its cooldown
+func (tc *TadoCollector) skipFailingHome(ctx context.Context, homeIDStr string) bool {
+	if tc.homeFailureThreshold <= 0 {
+		return false
+	}
+
+	tc.mu.Lock()
+	failures := tc.homeFailures[homeIDStr]
+	tc.mu.Unlock()
+
+	if failures == nil || failures.skipUntil.IsZero() {
+		return false
+	}
+	if tc.clock.Now().Before(failures.skipUntil) {
+		tc.log.DebugContext(ctx, "Skipping consistently failing home", "home_id", homeIDStr, "retry_at", failures.skipUntil.Format(time.RFC3339))
+		return true
+	}
+
+	tc.log.InfoContext(ctx, "Retrying consistently failing home after its cooldown", "home_id", homeIDStr, "consecutive_failures", failures.consecutive)
+	return false
+}
+
+// recordHomeOutcome updates the home's consecutive failures after it was collected, starting a cooldown
+// once they reach the threshold
+func (tc *TadoCollector) recordHomeOutcome(ctx context.Context, homeIDStr string, failed bool) {
+	if tc.homeFailureThreshold <= 0 {
+		return
+	}
+
+	tc.mu.Lock()
+	if tc.homeFailures == nil {
+		tc.homeFailures = make(map[string]*homeFailures)
+	}
+	failures := tc.homeFailures[homeIDStr]
+	if failures == nil {
+		failures = &homeFailures{}
+		tc.homeFailures[homeIDStr] = failures
+	}
+	if failed {
+		failures.consecutive++
+	} else {
+		failures.consecutive = 0
+	}
+	failures.skipUntil = time.Time{}
+	if failures.consecutive >= tc.homeFailureThreshold {
+		failures.skipUntil = tc.clock.Now().Add(tc.homeFailureCooldown)
+	}
+	consecutive, skipUntil := failures.consecutive, failures.skipUntil
+	tc.mu.Unlock()
+
+	if !skipUntil.IsZero() {
+		tc.log.WarnContext(ctx, "Skipping consistently failing home until its cooldown ends",
+			"home_id", homeIDStr,
+			"consecutive_failures", consecutive,
+			"cooldown", tc.homeFailureCooldown.String())
+	}
+	if tc.exporterMetrics != nil {
+		tc.exporterMetrics.SetHomeSkipped(homeIDStr, !skipUntil.IsZero())
+	}
+}
+
+// homeAccessError wraps the failure of a call a home can't be collected without, GetHomeState or GetZones,
+// as opposed to an auxiliary endpoint such as the weather failing on its own
+type homeAccessError struct {
+	err error
+}
+
+func (e *homeAccessError) Error() string {
+	return e.err.Error()
+}
+
+func (e *homeAccessError) Unwrap() error {
+	return e.err
+}
+
+// isHomeAccessLost reports whether err, or any error it joins, means the home itself can't be collected:
+// a failed GetHomeState or GetZones, or any call answered with 401, 403 or 404
+func isHomeAccessLost(err error) bool {
+	switch e := err.(type) {
+	case nil:
+		return false
+	case *homeAccessError:
+		return true
+	case *StatusError:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden || e.StatusCode == http.StatusNotFound
+	case interface{ Unwrap() []error }:
+		for _, joined := range e.Unwrap() {
+			if isHomeAccessLost(joined) {
+				return true
+			}
+		}
+		return false
+	case interface{ Unwrap() error }:
+		return isHomeAccessLost(e.Unwrap())
+	default:
+		return false
+	}
+}
@@ -24,4 +24,13 @@ type TadoAPI interface {
 
 	// GetWeather retrieves weather information for a home
 	GetWeather(ctx context.Context, homeID tado.HomeId) (*tado.Weather, error)
+
+	// GetDevices retrieves all devices in a home, including the internet bridge
+	GetDevices(ctx context.Context, homeID tado.HomeId) ([]tado.Device, error)
+
+	// GetMobileDevices retrieves the mobile devices of a home's users, including their geofencing location
+	GetMobileDevices(ctx context.Context, homeID tado.HomeId) ([]tado.MobileDevice, error)
+
+	// GetTemperatureOffset retrieves the temperature offset configured on a single device
+	GetTemperatureOffset(ctx context.Context, deviceID tado.DeviceId) (*tado.Temperature, error)
 }
@@ -0,0 +1,39 @@
+// Package collector provides label value sanitization helpers.
+package collector
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultMaxLabelLength is the default maximum length (in characters) of a label value
+const DefaultMaxLabelLength = 128
+
+// truncatedLabelSuffix marks label values that were shortened to fit the maximum length
+const truncatedLabelSuffix = "..."
+
+// sanitizeLabelValue makes a user-controlled value safe to use as a label value.
+// Invalid UTF-8 is replaced and values longer than maxLength characters are
+// truncated to exactly maxLength characters, ending with truncatedLabelSuffix.
+// A maxLength of 0 or less disables truncation.
+func sanitizeLabelValue(value string, maxLength int) string {
+	value = strings.ToValidUTF8(value, "�")
+
+	if maxLength <= 0 || utf8.RuneCountInString(value) <= maxLength {
+		return value
+	}
+
+	suffix := truncatedLabelSuffix
+	if maxLength <= len(suffix) {
+		suffix = ""
+	}
+
+	runes := []rune(value)
+	return string(runes[:maxLength-len(suffix)]) + suffix
+}
+
+// zoneLabelValues returns the label values of a zone-level metric in the order of metrics.ZoneLabelNames
+// Always build zone labels with it so the values can't drift out of line with the declared label names
+func zoneLabelValues(homeID, zoneID, zoneName, zoneType string) []string {
+	return []string{homeID, zoneID, zoneName, zoneType}
+}
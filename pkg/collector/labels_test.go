@@ -0,0 +1,83 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSanitizeLabelValue tests truncation and UTF-8 repair of label values
+func TestSanitizeLabelValue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		value     string
+		maxLength int
+		expected  string
+	}{
+		{"short value unchanged", "Living Room", 128, "Living Room"},
+		{"exact length unchanged", "abcdefgh", 8, "abcdefgh"},
+		{"long value truncated", "abcdefghij", 8, "abcde..."},
+		{"zero disables truncation", strings.Repeat("a", 1000), 0, strings.Repeat("a", 1000)},
+		{"multi-byte characters counted once", "Küchenbereich", 8, "Küche..."},
+		{"tiny limit omits suffix", "abcdef", 2, "ab"},
+		{"invalid UTF-8 replaced", "bad\xffname", 128, "bad�name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, sanitizeLabelValue(tt.value, tt.maxLength))
+		})
+	}
+}
+
+// TestSanitizeLabelValue_LongZoneName tests that a 1000-character zone name is truncated to the configured length
+func TestSanitizeLabelValue_LongZoneName(t *testing.T) {
+	t.Parallel()
+
+	result := sanitizeLabelValue(strings.Repeat("z", 1000), DefaultMaxLabelLength)
+
+	assert.Equal(t, DefaultMaxLabelLength, utf8.RuneCountInString(result))
+	assert.True(t, strings.HasSuffix(result, truncatedLabelSuffix))
+}
+
+// TestZoneLabelValues tests that zone label values line up with the label names declared by the zone metrics
+func TestZoneLabelValues(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+	metricDescs, err := metrics.NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	require.NoError(t, metricDescs.RegisterWith(registry))
+
+	labels := zoneLabelValues("home", "zone", "name", "type")
+	require.Len(t, labels, len(metrics.ZoneLabelNames))
+	metricDescs.TemperatureMeasuredCelsius.WithLabelValues(labels...).Set(20)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	gathered := map[string]string{}
+	for _, family := range families {
+		if family.GetName() != "tado_temperature_measured_celsius" {
+			continue
+		}
+		require.Len(t, family.Metric, 1)
+		for _, pair := range family.Metric[0].Label {
+			gathered[pair.GetName()] = pair.GetValue()
+		}
+	}
+
+	assert.Equal(t, map[string]string{
+		"home_id":   "home",
+		"zone_id":   "zone",
+		"zone_name": "name",
+		"zone_type": "type",
+	}, gathered)
+}
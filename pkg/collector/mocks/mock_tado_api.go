@@ -58,6 +58,33 @@ func (m *MockTadoAPI) GetWeather(ctx context.Context, homeID tado.HomeId) (*tado
 	return args.Get(0).(*tado.Weather), args.Error(1)
 }
 
+// GetDevices implements TadoAPI.GetDevices
+func (m *MockTadoAPI) GetDevices(ctx context.Context, homeID tado.HomeId) ([]tado.Device, error) {
+	args := m.Called(ctx, homeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]tado.Device), args.Error(1)
+}
+
+// GetMobileDevices implements TadoAPI.GetMobileDevices
+func (m *MockTadoAPI) GetMobileDevices(ctx context.Context, homeID tado.HomeId) ([]tado.MobileDevice, error) {
+	args := m.Called(ctx, homeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]tado.MobileDevice), args.Error(1)
+}
+
+// GetTemperatureOffset implements TadoAPI.GetTemperatureOffset
+func (m *MockTadoAPI) GetTemperatureOffset(ctx context.Context, deviceID tado.DeviceId) (*tado.Temperature, error) {
+	args := m.Called(ctx, deviceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*tado.Temperature), args.Error(1)
+}
+
 // ExpectGetMeReturnsHomes sets up expectation for GetMe to return homes
 func (m *MockTadoAPI) ExpectGetMeReturnsHomes(homeIDs []tado.HomeId) *MockTadoAPI {
 	homes := make([]tado.HomeBase, len(homeIDs))
@@ -81,6 +108,12 @@ func (m *MockTadoAPI) ExpectGetMeReturnsEmptyHomes() *MockTadoAPI {
 	return m
 }
 
+// ExpectGetMeReturnsNilHomes sets up expectation for GetMe to return a user without a homes field
+func (m *MockTadoAPI) ExpectGetMeReturnsNilHomes() *MockTadoAPI {
+	m.On("GetMe", mock.Anything).Return(&tado.User{}, nil)
+	return m
+}
+
 // ExpectAllAPICalls sets up default expectations for all API calls
 func (m *MockTadoAPI) ExpectAllAPICalls() *MockTadoAPI {
 	// Default: return empty but valid responses
@@ -91,5 +124,7 @@ func (m *MockTadoAPI) ExpectAllAPICalls() *MockTadoAPI {
 	emptyZoneStates := map[string]tado.ZoneState{}
 	m.On("GetZoneStates", mock.Anything, mock.Anything).Return(&tado.ZoneStates{ZoneStates: &emptyZoneStates}, nil)
 	m.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+	m.On("GetDevices", mock.Anything, mock.Anything).Return([]tado.Device{}, nil)
+	m.On("GetMobileDevices", mock.Anything, mock.Anything).Return([]tado.MobileDevice{}, nil)
 	return m
 }
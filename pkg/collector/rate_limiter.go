@@ -0,0 +1,178 @@
+// Package collector provides a rate limiter for Tado API calls.
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/clambin/tado/v2"
+)
+
+// ErrRateLimited is returned without calling the Tado API when the request rate limit
+// would not free up a request before the call's context deadline
+var ErrRateLimited = errors.New("tado request rate limit exceeded")
+
+// RateLimiterSettings configures the rate limiter created by NewRateLimitedAPI
+type RateLimiterSettings struct {
+	// RequestsPerMinute is the sustained number of Tado API calls allowed per minute
+	// Up to this many calls can be made back to back before calls start waiting
+	RequestsPerMinute int
+
+	// OnThrottle, if non-nil, is called for every call that had to wait or was rejected
+	OnThrottle func()
+}
+
+// tokenBucket allows bursts of up to capacity calls, refilling one token every interval
+type tokenBucket struct {
+	capacity float64
+	interval time.Duration
+	now      func() time.Time
+
+	mu       sync.Mutex
+	tokens   float64
+	lastFill time.Time
+}
+
+// newTokenBucket creates a full token bucket allowing requestsPerMinute calls per minute
+func newTokenBucket(requestsPerMinute int) *tokenBucket {
+	return &tokenBucket{
+		capacity: float64(requestsPerMinute),
+		interval: time.Minute / time.Duration(requestsPerMinute),
+		now:      time.Now,
+		tokens:   float64(requestsPerMinute),
+		lastFill: time.Now(),
+	}
+}
+
+// Wait takes a token, blocking until one is available or ctx is done
+// throttled reports whether the call could not take a token immediately. ErrRateLimited is
+// returned straight away if no token frees up before ctx's deadline
+func (b *tokenBucket) Wait(ctx context.Context) (throttled bool, err error) {
+	b.mu.Lock()
+	now := b.now()
+	b.refill(now)
+	if b.tokens >= 1 {
+		b.tokens--
+		b.mu.Unlock()
+		return false, nil
+	}
+
+	wait := time.Duration((1 - b.tokens) * float64(b.interval))
+	if deadline, ok := ctx.Deadline(); ok && now.Add(wait).After(deadline) {
+		b.mu.Unlock()
+		return true, fmt.Errorf("%w: next request allowed in %s", ErrRateLimited, wait.Round(time.Millisecond))
+	}
+	// Reserve the token now so concurrent callers queue up behind this one
+	b.tokens--
+	b.mu.Unlock()
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true, nil
+	case <-ctx.Done():
+		// Give the reserved token back for the next caller
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return true, ctx.Err()
+	}
+}
+
+// refill adds the tokens earned since the last refill, up to capacity
+// b.mu must be held
+func (b *tokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(b.lastFill)
+	if elapsed <= 0 {
+		return
+	}
+	b.tokens += float64(elapsed) / float64(b.interval)
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.lastFill = now
+}
+
+// rateLimitedAPI wraps a TadoAPI so that calls stay under a fixed request rate
+type rateLimitedAPI struct {
+	api        TadoAPI
+	bucket     *tokenBucket
+	onThrottle func()
+}
+
+// NewRateLimitedAPI wraps api with a token-bucket rate limiter shared by all of its endpoints
+// Calls over the limit wait for a free request, or fail with ErrRateLimited if none frees up
+// before their context deadline
+func NewRateLimitedAPI(api TadoAPI, settings RateLimiterSettings) TadoAPI {
+	return &rateLimitedAPI{api: api, bucket: newTokenBucket(settings.RequestsPerMinute), onThrottle: settings.OnThrottle}
+}
+
+// wait blocks until the call may be made, reporting throttled calls
+func (r *rateLimitedAPI) wait(ctx context.Context) error {
+	throttled, err := r.bucket.Wait(ctx)
+	if throttled && r.onThrottle != nil {
+		r.onThrottle()
+	}
+	return err
+}
+
+func (r *rateLimitedAPI) GetMe(ctx context.Context) (*tado.User, error) {
+	if err := r.wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.api.GetMe(ctx)
+}
+
+func (r *rateLimitedAPI) GetHomeState(ctx context.Context, homeID tado.HomeId) (*tado.HomeState, error) {
+	if err := r.wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.api.GetHomeState(ctx, homeID)
+}
+
+func (r *rateLimitedAPI) GetZones(ctx context.Context, homeID tado.HomeId) ([]tado.Zone, error) {
+	if err := r.wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.api.GetZones(ctx, homeID)
+}
+
+func (r *rateLimitedAPI) GetZoneStates(ctx context.Context, homeID tado.HomeId) (*tado.ZoneStates, error) {
+	if err := r.wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.api.GetZoneStates(ctx, homeID)
+}
+
+func (r *rateLimitedAPI) GetWeather(ctx context.Context, homeID tado.HomeId) (*tado.Weather, error) {
+	if err := r.wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.api.GetWeather(ctx, homeID)
+}
+
+func (r *rateLimitedAPI) GetDevices(ctx context.Context, homeID tado.HomeId) ([]tado.Device, error) {
+	if err := r.wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.api.GetDevices(ctx, homeID)
+}
+
+func (r *rateLimitedAPI) GetMobileDevices(ctx context.Context, homeID tado.HomeId) ([]tado.MobileDevice, error) {
+	if err := r.wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.api.GetMobileDevices(ctx, homeID)
+}
+
+func (r *rateLimitedAPI) GetTemperatureOffset(ctx context.Context, deviceID tado.DeviceId) (*tado.Temperature, error) {
+	if err := r.wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.api.GetTemperatureOffset(ctx, deviceID)
+}
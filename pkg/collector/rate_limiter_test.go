@@ -0,0 +1,67 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/collector/mocks"
+	"github.com/clambin/tado/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRateLimitedAPI_ThrottlesOverLimit tests that calls over the limit are throttled and that context deadlines are respected
+func TestRateLimitedAPI_ThrottlesOverLimit(t *testing.T) {
+	t.Parallel()
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{}, nil)
+
+	throttled := 0
+	api := NewRateLimitedAPI(mockAPI, RateLimiterSettings{RequestsPerMinute: 2, OnThrottle: func() { throttled++ }})
+
+	// The burst allowance passes straight through
+	for i := 0; i < 2; i++ {
+		_, err := api.GetZones(context.Background(), 1)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 0, throttled)
+
+	// The next request is 30s away, past the deadline, so the call fails without waiting
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := api.GetZones(ctx, 1)
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Less(t, time.Since(start), 50*time.Millisecond, "a call that cannot be served before its deadline should fail immediately")
+
+	// Without a deadline the call waits until the context is cancelled
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	_, err = api.GetZones(ctx, 1)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	assert.Equal(t, 2, throttled)
+	mockAPI.AssertNumberOfCalls(t, "GetZones", 2)
+}
+
+// TestRateLimitedAPI_WaitsForToken tests that a throttled call goes through once a request frees up
+func TestRateLimitedAPI_WaitsForToken(t *testing.T) {
+	t.Parallel()
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(&tado.Weather{}, nil)
+
+	throttled := 0
+	api := NewRateLimitedAPI(mockAPI, RateLimiterSettings{RequestsPerMinute: 1200, OnThrottle: func() { throttled++ }})
+	api.(*rateLimitedAPI).bucket.tokens = 0
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := api.GetWeather(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, throttled)
+	mockAPI.AssertNumberOfCalls(t, "GetWeather", 1)
+}
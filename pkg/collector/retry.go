@@ -0,0 +1,112 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/clambin/tado/v2"
+)
+
+// RetrySettings configures the retrying wrapper created by NewRetryingAPI
+type RetrySettings struct {
+	// MaxRetries is the number of times a failed call is retried
+	MaxRetries int
+
+	// Backoff is the wait before the first retry; it doubles for every further retry
+	Backoff time.Duration
+
+	// OnRetry, if non-nil, is called with the endpoint, e.g. "GetZones", before every retry
+	OnRetry func(endpoint string)
+}
+
+// retryingAPI wraps a TadoAPI so that calls failing with a transient error are retried
+type retryingAPI struct {
+	api      TadoAPI
+	settings RetrySettings
+}
+
+// NewRetryingAPI wraps api so that calls failing with a transient error, such as a network error or
+// a 5xx or 429 status, are retried up to settings.MaxRetries times with exponential backoff
+// Retries stop early when the call's context would expire during the backoff
+func NewRetryingAPI(api TadoAPI, settings RetrySettings) TadoAPI {
+	return &retryingAPI{api: api, settings: settings}
+}
+
+// isRetryable reports whether err is worth retrying
+// Tado maintenance, client errors and errors from the exporter's own limits won't clear up within a scrape
+func isRetryable(err error) bool {
+	var statusErr *StatusError
+	switch {
+	case err == nil,
+		errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, ErrRateLimited),
+		errors.Is(err, ErrCircuitOpen),
+		IsMaintenanceError(err):
+		return false
+	case errors.As(err, &statusErr):
+		return statusErr.StatusCode >= 500 || statusErr.StatusCode == http.StatusTooManyRequests
+	default:
+		return true
+	}
+}
+
+// retry calls fn until it succeeds, fails with an error that isn't retryable, or runs out of retries
+func retry[T any](ctx context.Context, r *retryingAPI, endpoint string, fn func() (T, error)) (T, error) {
+	backoff := r.settings.Backoff
+	result, err := fn()
+	for attempt := 0; attempt < r.settings.MaxRetries && isRetryable(err); attempt++ {
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(backoff).After(deadline) {
+			break
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		case <-timer.C:
+		}
+
+		if r.settings.OnRetry != nil {
+			r.settings.OnRetry(endpoint)
+		}
+		result, err = fn()
+		backoff *= 2
+	}
+	return result, err
+}
+
+func (r *retryingAPI) GetMe(ctx context.Context) (*tado.User, error) {
+	return retry(ctx, r, "GetMe", func() (*tado.User, error) { return r.api.GetMe(ctx) })
+}
+
+func (r *retryingAPI) GetHomeState(ctx context.Context, homeID tado.HomeId) (*tado.HomeState, error) {
+	return retry(ctx, r, "GetHomeState", func() (*tado.HomeState, error) { return r.api.GetHomeState(ctx, homeID) })
+}
+
+func (r *retryingAPI) GetZones(ctx context.Context, homeID tado.HomeId) ([]tado.Zone, error) {
+	return retry(ctx, r, "GetZones", func() ([]tado.Zone, error) { return r.api.GetZones(ctx, homeID) })
+}
+
+func (r *retryingAPI) GetZoneStates(ctx context.Context, homeID tado.HomeId) (*tado.ZoneStates, error) {
+	return retry(ctx, r, "GetZoneStates", func() (*tado.ZoneStates, error) { return r.api.GetZoneStates(ctx, homeID) })
+}
+
+func (r *retryingAPI) GetWeather(ctx context.Context, homeID tado.HomeId) (*tado.Weather, error) {
+	return retry(ctx, r, "GetWeather", func() (*tado.Weather, error) { return r.api.GetWeather(ctx, homeID) })
+}
+
+func (r *retryingAPI) GetDevices(ctx context.Context, homeID tado.HomeId) ([]tado.Device, error) {
+	return retry(ctx, r, "GetDevices", func() ([]tado.Device, error) { return r.api.GetDevices(ctx, homeID) })
+}
+
+func (r *retryingAPI) GetMobileDevices(ctx context.Context, homeID tado.HomeId) ([]tado.MobileDevice, error) {
+	return retry(ctx, r, "GetMobileDevices", func() ([]tado.MobileDevice, error) { return r.api.GetMobileDevices(ctx, homeID) })
+}
+
+func (r *retryingAPI) GetTemperatureOffset(ctx context.Context, deviceID tado.DeviceId) (*tado.Temperature, error) {
+	return retry(ctx, r, "GetTemperatureOffset", func() (*tado.Temperature, error) { return r.api.GetTemperatureOffset(ctx, deviceID) })
+}
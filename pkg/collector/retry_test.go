@@ -0,0 +1,91 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/collector/mocks"
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/metrics"
+	"github.com/clambin/tado/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRetryingAPI_RetriesTransientErrors tests that a call failing twice then succeeding is retried twice and counted per endpoint
+func TestRetryingAPI_RetriesTransientErrors(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+	exporterMetrics := metrics.NewExporterMetricsUnregistered()
+	require.NoError(t, exporterMetrics.RegisterWith(registry))
+
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return(nil, errors.New("connection reset by peer")).Twice()
+	mockAPI.On("GetZones", mock.Anything, mock.Anything).Return([]tado.Zone{{}}, nil)
+
+	api := NewRetryingAPI(mockAPI, RetrySettings{MaxRetries: 3, Backoff: time.Millisecond, OnRetry: exporterMetrics.IncrementRetries})
+
+	zones, err := api.GetZones(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Len(t, zones, 1)
+	mockAPI.AssertNumberOfCalls(t, "GetZones", 3)
+
+	// Only GetZones was retried, so its series is the only one
+	assert.Equal(t, 2.0, findCounterValue(t, registry, "tado_exporter_retries_total"))
+	_, found := findGaugeValue(t, registry, "tado_exporter_retries_total", map[string]string{"endpoint": "GetZones"})
+	assert.True(t, found, "the series should be labelled with the endpoint")
+}
+
+// TestRetryingAPI_DoesNotRetryPermanentErrors tests that client errors, maintenance and the exporter's own limits fail straight away
+func TestRetryingAPI_DoesNotRetryPermanentErrors(t *testing.T) {
+	t.Parallel()
+
+	for name, callErr := range map[string]error{
+		"client error":  &StatusError{Operation: "GetZones", StatusCode: 401},
+		"maintenance":   &MaintenanceError{},
+		"rate limited":  ErrRateLimited,
+		"circuit open":  ErrCircuitOpen,
+		"ctx cancelled": context.Canceled,
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockAPI := &mocks.MockTadoAPI{}
+			mockAPI.On("GetZones", mock.Anything, mock.Anything).Return(nil, callErr)
+
+			retries := 0
+			api := NewRetryingAPI(mockAPI, RetrySettings{MaxRetries: 3, Backoff: time.Millisecond, OnRetry: func(string) { retries++ }})
+
+			_, err := api.GetZones(context.Background(), 1)
+			assert.ErrorIs(t, err, callErr)
+			assert.Equal(t, 0, retries)
+			mockAPI.AssertNumberOfCalls(t, "GetZones", 1)
+		})
+	}
+}
+
+// TestRetryingAPI_GivesUp tests that retries stop after MaxRetries and before the backoff would pass the deadline
+func TestRetryingAPI_GivesUp(t *testing.T) {
+	t.Parallel()
+
+	serverErr := &StatusError{Operation: "GetWeather", StatusCode: 502}
+	mockAPI := &mocks.MockTadoAPI{}
+	mockAPI.On("GetWeather", mock.Anything, mock.Anything).Return(nil, serverErr)
+
+	api := NewRetryingAPI(mockAPI, RetrySettings{MaxRetries: 2, Backoff: time.Millisecond})
+	_, err := api.GetWeather(context.Background(), 1)
+	assert.ErrorIs(t, err, serverErr)
+	mockAPI.AssertNumberOfCalls(t, "GetWeather", 3)
+
+	// A backoff past the deadline isn't waited for
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	api = NewRetryingAPI(mockAPI, RetrySettings{MaxRetries: 2, Backoff: time.Second})
+	start := time.Now()
+	_, err = api.GetWeather(ctx, 1)
+	assert.ErrorIs(t, err, serverErr)
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+	mockAPI.AssertNumberOfCalls(t, "GetWeather", 4)
+}
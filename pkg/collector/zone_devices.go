@@ -0,0 +1,87 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/clambin/tado/v2"
+)
+
+// DefaultZoneDeviceWorkers is the default number of per-zone device calls made concurrently
+const DefaultZoneDeviceWorkers = 4
+
+// DefaultZoneDeviceTimeout is the default time a single per-zone device call may take
+const DefaultZoneDeviceTimeout = 2 * time.Second
+
+// zoneLeaderDuty is the duty of the device measuring a zone's temperature
+const zoneLeaderDuty = "ZONE_LEADER"
+
+// zoneDeviceCall is a per-zone device call to make for the zone with the given label values
+type zoneDeviceCall struct {
+	zoneID   string
+	labels   []string
+	deviceID tado.DeviceId
+}
+
+// zoneLeaderDevice returns the serial number of the device leading the zone, i.e. measuring its temperature
+// ok is false if the zone details list no leading device
+func zoneLeaderDevice(zone tado.Zone) (deviceID tado.DeviceId, ok bool) {
+	if zone.Devices == nil {
+		return "", false
+	}
+	for _, device := range *zone.Devices {
+		if device.SerialNo != nil && device.Duties != nil && slices.Contains(*device.Duties, zoneLeaderDuty) {
+			return *device.SerialNo, true
+		}
+	}
+	return "", false
+}
+
+// collectZoneDeviceMetrics makes the per-zone device calls on a pool of at most zoneDeviceWorkers
+// concurrent calls, each with its own zoneDeviceTimeout within the scrape's deadline, so a slow
+// device only loses its own zone's metrics instead of holding up every other zone
+// It returns the number of calls that failed; their zones' device metrics are removed rather than left stale
+func (tc *TadoCollector) collectZoneDeviceMetrics(ctx context.Context, homeIDStr string, calls []zoneDeviceCall) int {
+	var failed atomic.Int32
+	var wg sync.WaitGroup
+	workers := make(chan struct{}, tc.zoneDeviceWorkers)
+
+	for _, call := range calls {
+		workers <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-workers }()
+
+			if err := tc.collectTemperatureOffset(ctx, call); err != nil {
+				failed.Add(1)
+				tc.metricDescriptors.ZoneTemperatureOffsetCelsius.DeleteLabelValues(call.labels...)
+				tc.log.WarnContext(ctx, "Failed to collect zone device metrics", "home_id", homeIDStr, "zone_id", call.zoneID, "device", call.deviceID, "error", err.Error())
+			}
+		}()
+	}
+	wg.Wait()
+
+	return int(failed.Load())
+}
+
+// collectTemperatureOffset records the temperature offset of the zone's leading device
+func (tc *TadoCollector) collectTemperatureOffset(ctx context.Context, call zoneDeviceCall) error {
+	ctx, cancel := context.WithTimeout(ctx, tc.zoneDeviceTimeout)
+	defer cancel()
+
+	offset, err := tc.tadoClient.GetTemperatureOffset(ctx, call.deviceID)
+	if err != nil {
+		return err
+	}
+	if offset == nil || offset.Celsius == nil {
+		return errors.New("temperature offset has no Celsius value")
+	}
+
+	tc.metricDescriptors.ZoneTemperatureOffsetCelsius.WithLabelValues(call.labels...).Set(tc.roundTemperature(float64(*offset.Celsius)))
+	return nil
+}
@@ -3,6 +3,7 @@ package collector
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/clambin/tado/v2"
 )
@@ -22,6 +23,35 @@ const (
 	MaxValidPower float32 = 100
 )
 
+// FrostProtectionCelsius is the minimum heating setpoint, which Tado holds zones at to protect them from frost,
+// e.g. while everyone is away
+const FrostProtectionCelsius float32 = 5
+
+// temperatureDataPointType is the type Tado reports on temperature readings
+// Readings of any other type (e.g. PERCENTAGE) are not temperatures and are skipped
+const temperatureDataPointType = "TEMPERATURE"
+
+// acModeValues encodes air conditioning modes as tado_zone_ac_mode gauge values
+var acModeValues = map[tado.AirConditioningMode]float32{
+	tado.AirConditioningModeCOOL: 1,
+	tado.AirConditioningModeHEAT: 2,
+	tado.AirConditioningModeDRY:  3,
+	tado.AirConditioningModeFAN:  4,
+	tado.AirConditioningModeAUTO: 5,
+}
+
+// acFanLevelValues encodes air conditioning fan levels as tado_zone_ac_fan_speed gauge values
+// Fixed levels increase with fan speed; AUTO is encoded above them
+var acFanLevelValues = map[tado.FanLevel]float32{
+	tado.FanLevelSILENT: 1,
+	tado.FanLevelLEVEL1: 2,
+	tado.FanLevelLEVEL2: 3,
+	tado.FanLevelLEVEL3: 4,
+	tado.FanLevelLEVEL4: 5,
+	tado.FanLevelLEVEL5: 6,
+	tado.FanLevelAUTO:   7,
+}
+
 // ZoneMetrics holds extracted metrics for a single zone
 type ZoneMetrics struct {
 	MeasuredTemperatureCelsius    *float32
@@ -32,6 +62,18 @@ type ZoneMetrics struct {
 	HeatingPowerPercentage        *float32
 	IsWindowOpen                  bool
 	IsZonePowered                 bool
+	SensorTimestamp               *time.Time // Newest timestamp across the zone's sensor readings
+	IsAirConditioning             bool       // The zone setting is an air conditioning setting
+	ACMode                        *float32   // Encoded AC mode (see acModeValues); nil for non-AC zones or unknown modes
+	ACFanSpeed                    *float32   // Encoded AC fan level (see acFanLevelValues); nil for non-AC zones or unknown levels
+	IsHeating                     bool       // The zone setting is a heating setting
+	IsHotWater                    bool       // The zone setting is a hot water setting
+	IsOverlayActive               bool       // A manual overlay overrides the zone's schedule
+	OverlayRemainingSeconds       *float32   // Seconds until a timer overlay expires; nil without a timer overlay
+
+	// UnexpectedTemperatureType is the measured temperature's type when it is not TEMPERATURE
+	// The measured temperature is then left unset; empty for readings of the expected type
+	UnexpectedTemperatureType string
 }
 
 // extractZoneTemperature extracts the measured temperature from zone sensor data
@@ -46,6 +88,19 @@ func extractZoneTemperature(zoneState *tado.ZoneState) (*float32, *float32) {
 		zoneState.SensorDataPoints.InsideTemperature.Fahrenheit
 }
 
+// extractUnexpectedTemperatureType returns the measured temperature's type if it is reported and is not TEMPERATURE
+// Readings without a type are accepted, as older API responses omit it
+func extractUnexpectedTemperatureType(zoneState *tado.ZoneState) string {
+	if zoneState == nil || zoneState.SensorDataPoints == nil {
+		return ""
+	}
+	reading := zoneState.SensorDataPoints.InsideTemperature
+	if reading == nil || reading.Type == nil || *reading.Type == temperatureDataPointType {
+		return ""
+	}
+	return *reading.Type
+}
+
 // extractZoneHumidity extracts the measured humidity from zone sensor data
 func extractZoneHumidity(zoneState *tado.ZoneState) *float32 {
 	if zoneState == nil || zoneState.SensorDataPoints == nil {
@@ -99,9 +154,127 @@ func extractZonePowerStatus(zoneState *tado.ZoneState) bool {
 	return string(*zoneState.Setting.Power) == "ON"
 }
 
+// extractIsAirConditioning determines if the zone's current setting is an air conditioning setting
+func extractIsAirConditioning(zoneState *tado.ZoneState) bool {
+	if zoneState == nil || zoneState.Setting == nil {
+		return false
+	}
+	if zoneState.Setting.Type == nil {
+		return false
+	}
+	return *zoneState.Setting.Type == tado.AIRCONDITIONING
+}
+
+// extractIsHeating determines if the zone's current setting is a heating setting
+func extractIsHeating(zoneState *tado.ZoneState) bool {
+	if zoneState == nil || zoneState.Setting == nil {
+		return false
+	}
+	if zoneState.Setting.Type == nil {
+		return false
+	}
+	return *zoneState.Setting.Type == tado.HEATING
+}
+
+// extractIsHotWater determines if the zone's current setting is a hot water setting
+func extractIsHotWater(zoneState *tado.ZoneState) bool {
+	if zoneState == nil || zoneState.Setting == nil {
+		return false
+	}
+	if zoneState.Setting.Type == nil {
+		return false
+	}
+	return *zoneState.Setting.Type == tado.HOTWATER
+}
+
+// extractOverlayActive determines if a manual overlay overrides the zone's schedule
+func extractOverlayActive(zoneState *tado.ZoneState) bool {
+	if zoneState == nil {
+		return false
+	}
+	return zoneState.Overlay != nil
+}
+
+// extractOverlayRemainingSeconds extracts the seconds until a timer overlay expires
+// Returns nil without an overlay or for overlays not ending on a timer, e.g. those lasting until changed
+// now is the current time, to count down to the expiry when Tado reports no remaining time
+func extractOverlayRemainingSeconds(zoneState *tado.ZoneState, now time.Time) *float32 {
+	if zoneState == nil || zoneState.Overlay == nil || zoneState.Overlay.Termination == nil {
+		return nil
+	}
+	termination := zoneState.Overlay.Termination
+	if termination.Type == nil || *termination.Type != tado.ZoneOverlayTerminationTypeTIMER {
+		return nil
+	}
+
+	var remaining float32
+	switch {
+	case termination.RemainingTimeInSeconds != nil:
+		remaining = float32(*termination.RemainingTimeInSeconds)
+	case termination.Expiry != nil:
+		remaining = float32(termination.Expiry.Sub(now).Seconds())
+	default:
+		return nil
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &remaining
+}
+
+// extractACMode extracts the encoded air conditioning mode from zone settings
+// Returns nil for non-AC zones, when no mode is set (e.g. the unit is off) or the mode is unknown
+func extractACMode(zoneState *tado.ZoneState) *float32 {
+	if !extractIsAirConditioning(zoneState) || zoneState.Setting.Mode == nil {
+		return nil
+	}
+	value, ok := acModeValues[*zoneState.Setting.Mode]
+	if !ok {
+		return nil
+	}
+	return &value
+}
+
+// extractACFanSpeed extracts the encoded air conditioning fan level from zone settings
+// Returns nil for non-AC zones, when no fan level is set (e.g. the unit is off) or the level is unknown
+func extractACFanSpeed(zoneState *tado.ZoneState) *float32 {
+	if !extractIsAirConditioning(zoneState) || zoneState.Setting.FanLevel == nil {
+		return nil
+	}
+	value, ok := acFanLevelValues[*zoneState.Setting.FanLevel]
+	if !ok {
+		return nil
+	}
+	return &value
+}
+
+// extractSensorTimestamp returns the newest timestamp across the zone's sensor readings
+func extractSensorTimestamp(zoneState *tado.ZoneState) *time.Time {
+	if zoneState == nil || zoneState.SensorDataPoints == nil {
+		return nil
+	}
+
+	var newest *time.Time
+	if zoneState.SensorDataPoints.InsideTemperature != nil {
+		newest = zoneState.SensorDataPoints.InsideTemperature.Timestamp
+	}
+	if zoneState.SensorDataPoints.Humidity != nil {
+		if ts := zoneState.SensorDataPoints.Humidity.Timestamp; ts != nil && (newest == nil || ts.After(*newest)) {
+			newest = ts
+		}
+	}
+	return newest
+}
+
 // ExtractAllZoneMetrics extracts all metrics from a zone state
-func ExtractAllZoneMetrics(zoneState *tado.ZoneState) *ZoneMetrics {
+// zoneState and any of its sub-structs may be nil; the corresponding metrics are then left unset
+// now is the current time, which time-dependent metrics such as the overlay countdown are relative to
+func ExtractAllZoneMetrics(zoneState *tado.ZoneState, now time.Time) *ZoneMetrics {
 	tempC, tempF := extractZoneTemperature(zoneState)
+	unexpectedTemperatureType := extractUnexpectedTemperatureType(zoneState)
+	if unexpectedTemperatureType != "" {
+		tempC, tempF = nil, nil
+	}
 	targetC, targetF := extractTargetTemperature(zoneState)
 
 	return &ZoneMetrics{
@@ -113,9 +286,24 @@ func ExtractAllZoneMetrics(zoneState *tado.ZoneState) *ZoneMetrics {
 		HeatingPowerPercentage:        extractHeatingPower(zoneState),
 		IsWindowOpen:                  extractWindowOpenStatus(zoneState),
 		IsZonePowered:                 extractZonePowerStatus(zoneState),
+		SensorTimestamp:               extractSensorTimestamp(zoneState),
+		IsAirConditioning:             extractIsAirConditioning(zoneState),
+		ACMode:                        extractACMode(zoneState),
+		ACFanSpeed:                    extractACFanSpeed(zoneState),
+		IsHeating:                     extractIsHeating(zoneState),
+		IsHotWater:                    extractIsHotWater(zoneState),
+		IsOverlayActive:               extractOverlayActive(zoneState),
+		OverlayRemainingSeconds:       extractOverlayRemainingSeconds(zoneState, now),
+		UnexpectedTemperatureType:     unexpectedTemperatureType,
 	}
 }
 
+// IsFrostProtectionActive reports whether a heating zone is powered on with its setpoint at FrostProtectionCelsius
+func (m *ZoneMetrics) IsFrostProtectionActive() bool {
+	return m.IsHeating && m.IsZonePowered &&
+		m.TargetTemperatureCelsius != nil && *m.TargetTemperatureCelsius == FrostProtectionCelsius
+}
+
 // ValidationError represents a validation error for a metric
 type ValidationError struct {
 	Field  string
@@ -2,7 +2,9 @@ package collector
 
 import (
 	"testing"
+	"time"
 
+	"github.com/clambin/tado/v2"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -253,3 +255,126 @@ func TestValidationErrorError(t *testing.T) {
 	assert.Contains(t, errorMsg, "temperature")
 	assert.Contains(t, errorMsg, "100")
 }
+
+// TestExtractSensorTimestamp tests that the newest sensor timestamp is selected
+func TestExtractSensorTimestamp(t *testing.T) {
+	t.Parallel()
+
+	older := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	newer := older.Add(5 * time.Minute)
+
+	tests := []struct {
+		name      string
+		zoneState *tado.ZoneState
+		expected  *time.Time
+	}{
+		{
+			name:      "nil zone state",
+			zoneState: nil,
+			expected:  nil,
+		},
+		{
+			name:      "no sensor data",
+			zoneState: &tado.ZoneState{},
+			expected:  nil,
+		},
+		{
+			name: "temperature only",
+			zoneState: &tado.ZoneState{SensorDataPoints: &tado.SensorDataPoints{
+				InsideTemperature: &tado.TemperatureDataPoint{Timestamp: &older},
+			}},
+			expected: &older,
+		},
+		{
+			name: "humidity newer than temperature",
+			zoneState: &tado.ZoneState{SensorDataPoints: &tado.SensorDataPoints{
+				InsideTemperature: &tado.TemperatureDataPoint{Timestamp: &older},
+				Humidity:          &tado.PercentageDataPoint{Timestamp: &newer},
+			}},
+			expected: &newer,
+		},
+		{
+			name: "temperature newer than humidity",
+			zoneState: &tado.ZoneState{SensorDataPoints: &tado.SensorDataPoints{
+				InsideTemperature: &tado.TemperatureDataPoint{Timestamp: &newer},
+				Humidity:          &tado.PercentageDataPoint{Timestamp: &older},
+			}},
+			expected: &newer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, extractSensorTimestamp(tt.zoneState))
+		})
+	}
+}
+
+// TestExtractACMode tests the air conditioning mode encoding and that non-AC zones are skipped
+func TestExtractACMode(t *testing.T) {
+	t.Parallel()
+
+	acSetting := func(mode tado.AirConditioningMode) *tado.ZoneState {
+		zoneType := tado.AIRCONDITIONING
+		return &tado.ZoneState{Setting: &tado.ZoneSetting{Type: &zoneType, Mode: &mode}}
+	}
+	heatingType := tado.HEATING
+	cool, heat, dry, fan, auto := float32(1), float32(2), float32(3), float32(4), float32(5)
+
+	tests := []struct {
+		name       string
+		zoneState  *tado.ZoneState
+		expectedAC bool
+		expected   *float32
+	}{
+		{name: "cool", zoneState: acSetting(tado.AirConditioningModeCOOL), expectedAC: true, expected: &cool},
+		{name: "heat", zoneState: acSetting(tado.AirConditioningModeHEAT), expectedAC: true, expected: &heat},
+		{name: "dry", zoneState: acSetting(tado.AirConditioningModeDRY), expectedAC: true, expected: &dry},
+		{name: "fan", zoneState: acSetting(tado.AirConditioningModeFAN), expectedAC: true, expected: &fan},
+		{name: "auto", zoneState: acSetting(tado.AirConditioningModeAUTO), expectedAC: true, expected: &auto},
+		{name: "unknown mode", zoneState: acSetting("TURBO"), expectedAC: true, expected: nil},
+		{name: "heating zone", zoneState: &tado.ZoneState{Setting: &tado.ZoneSetting{Type: &heatingType}}, expectedAC: false, expected: nil},
+		{name: "no setting", zoneState: &tado.ZoneState{}, expectedAC: false, expected: nil},
+		{name: "nil zone state", zoneState: nil, expectedAC: false, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := ExtractAllZoneMetrics(tt.zoneState, time.Now())
+			assert.Equal(t, tt.expectedAC, metrics.IsAirConditioning)
+			assert.Equal(t, tt.expected, metrics.ACMode)
+		})
+	}
+}
+
+// TestExtractACFanSpeed tests the air conditioning fan level encoding and that non-AC zones are skipped
+func TestExtractACFanSpeed(t *testing.T) {
+	t.Parallel()
+
+	acSetting := func(level tado.FanLevel) *tado.ZoneState {
+		zoneType := tado.AIRCONDITIONING
+		return &tado.ZoneState{Setting: &tado.ZoneSetting{Type: &zoneType, FanLevel: &level}}
+	}
+	heatingType := tado.HEATING
+	silent, level1, level5, auto := float32(1), float32(2), float32(6), float32(7)
+
+	tests := []struct {
+		name      string
+		zoneState *tado.ZoneState
+		expected  *float32
+	}{
+		{name: "silent", zoneState: acSetting(tado.FanLevelSILENT), expected: &silent},
+		{name: "level 1", zoneState: acSetting(tado.FanLevelLEVEL1), expected: &level1},
+		{name: "level 5", zoneState: acSetting(tado.FanLevelLEVEL5), expected: &level5},
+		{name: "auto", zoneState: acSetting(tado.FanLevelAUTO), expected: &auto},
+		{name: "unknown level", zoneState: acSetting("TURBO"), expected: nil},
+		{name: "heating zone", zoneState: &tado.ZoneState{Setting: &tado.ZoneSetting{Type: &heatingType}}, expected: nil},
+		{name: "nil zone state", zoneState: nil, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ExtractAllZoneMetrics(tt.zoneState, time.Now()).ACFanSpeed)
+		})
+	}
+}
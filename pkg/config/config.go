@@ -9,10 +9,51 @@
 // Supported environment variables:
 //   - TADO_TOKEN_PATH: Path to token storage file
 //   - TADO_TOKEN_PASSPHRASE: Passphrase for token encryption
+//   - TADO_ACCOUNTS: Separate Tado accounts to collect, as comma-separated name=token_path[:passphrase] entries
+//   - TADO_AUTH_URL_FILE: File the device-flow verification URL is written to, for non-interactive deployments
+//   - TADO_DEVICE_FLOW_TIMEOUT: How long to wait for the device-flow authentication to be completed (e.g. 10m, 0 waits indefinitely)
 //   - TADO_PORT: HTTP server port
+//   - TADO_READY_MAX_AGE: How recently a scrape must have succeeded for /ready to report ready (e.g. 5m)
+//   - TADO_SERVER_URL: Tado API base URL, e.g. a local stub server for integration tests
 //   - TADO_HOME_ID: Filter to specific Tado home
+//   - TADO_EXCLUDE_HOME_IDS: Comma-separated Tado home IDs to skip (e.g. 123,456)
 //   - TADO_SCRAPE_TIMEOUT: Timeout for API requests (seconds)
+//   - TADO_MAX_SCRAPE_TIMEOUT: Maximum scrape timeout a /metrics request may ask for with X-Scrape-Timeout (seconds, 0 disables the header)
 //   - TADO_LOG_LEVEL: Logging level (debug, info, warn, error)
+//   - TADO_LOG_LEVEL_COLLECTOR, TADO_LOG_LEVEL_AUTH, TADO_LOG_LEVEL_SERVER: Per-subsystem logging level overriding TADO_LOG_LEVEL
+//   - TADO_SLOW_CALL_THRESHOLD: Duration after which a single Tado API call is logged as slow (e.g. 2s)
+//   - TADO_CIRCUIT_BREAKER_MAX_FAILURES: Consecutive failed Tado API calls that stop calls to Tado (0 disables)
+//   - TADO_CIRCUIT_BREAKER_OPEN_TIMEOUT: How long calls stay stopped before a trial call (e.g. 1m)
+//   - TADO_HOME_FAILURE_THRESHOLD: Scrapes in a row losing access to a home after which a home is skipped for a cooldown (0 disables)
+//   - TADO_HOME_FAILURE_COOLDOWN: How long a consistently failing home is skipped before it is retried (e.g. 15m)
+//   - TADO_MAX_RETRIES: Times a Tado API call failing with a transient error is retried (0 disables)
+//   - TADO_RETRY_BACKOFF: Wait before the first retry, doubling for every further retry (e.g. 500ms)
+//   - TADO_MAX_REQUESTS_PER_MINUTE: Maximum Tado API calls per minute, excess calls wait (0 disables)
+//   - TADO_MAX_LABEL_LENGTH: Maximum length of label values such as zone names (longer values are truncated)
+//   - TADO_CONSTANT_LABELS: Labels added to every metric, as comma-separated name=value pairs (e.g. site=london,env=prod)
+//   - TADO_TEMPERATURE_UNITS: Comma-separated temperature units to export: celsius, fahrenheit, kelvin (default: celsius,fahrenheit)
+//   - TADO_TEMPERATURE_PRECISION: Decimal places temperature metrics are rounded to (default: -1, no rounding)
+//   - TADO_DEVICE_METRICS: Per-device metrics to collect: off, basic (battery, connection) or full (adds firmware) (default: off)
+//   - TADO_METRIC_COMPAT: Metric naming scheme, v1 (legacy names, default) or v2 (Prometheus convention-aligned names)
+//   - TADO_ADMIN_TOKEN: Bearer token enabling the admin endpoints (e.g. POST /scrape)
+//   - TADO_STRICT_MODE: Fail the whole scrape on any collection error (true/false)
+//   - TADO_SKIP_WEATHER: Skip weather collection, saving one Tado API call per home per scrape (true/false)
+//   - TADO_COLLECT_TEMPERATURE_OFFSETS: Collect each zone's temperature offset, one Tado API call per zone per scrape (true/false)
+//   - TADO_ZONE_DEVICE_WORKERS: Maximum number of per-zone device calls made at once
+//   - TADO_ZONE_DEVICE_TIMEOUT: Timeout of a single per-zone device call (e.g. 2s)
+//   - TADO_ALLOW_NO_HOMES: Treat an account without homes as valid instead of an authentication error (true/false)
+//   - TADO_EXPOSE_ACCOUNT_EMAIL: Add the raw account email to tado_exporter_account_info (true/false, default hashed only)
+//   - TADO_PER_HOME_METRICS: Also serve each home from its own registry at /metrics/<home_id> (true/false)
+//   - TADO_SEPARATE_EXPORTER_METRICS: Serve exporter health metrics at /metrics/exporter instead of /metrics (true/false)
+//   - TADO_ENABLE_GO_METRICS: Also expose the Go runtime (go_*) and process (process_*) metrics on /metrics (true/false)
+//   - TADO_ENABLE_OPENMETRICS: Negotiate the OpenMetrics exposition format with scrapers (true/false, default true)
+//   - TADO_SNAPSHOT_PATH: File used to save metric values on shutdown and restore them on startup
+//   - TADO_SNAPSHOT_MAX_AGE: Ignore snapshots older than this on startup (e.g. 15m, 0 accepts any age)
+//   - TADO_OTLP_ENDPOINT: OTLP/HTTP receiver to also push the metrics to, e.g. http://otel-collector:4318
+//   - TADO_OTLP_INTERVAL: How often metrics are collected and pushed to the OTLP receiver (e.g. 60s)
+//   - TADO_TLS_CERT_FILE: Server certificate file, enables HTTPS when set with TADO_TLS_KEY_FILE
+//   - TADO_TLS_KEY_FILE: Server private key file
+//   - TADO_TLS_CLIENT_CA: CA bundle used to require client certificates (mTLS) for /metrics
 //
 // Example usage:
 //
@@ -25,8 +66,16 @@ package config
 import (
 	"flag"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/metrics"
+	"github.com/clambin/tado/v2"
 )
 
 // Config holds the application configuration
@@ -34,18 +83,109 @@ type Config struct {
 	// Token storage
 	TokenPath       string
 	TokenPassphrase string
+	AuthURLFile     string // Optional: the device-flow verification URL is also written here
+
+	// DeviceFlowTimeout is how long startup waits for the device-flow authentication (0 waits indefinitely)
+	DeviceFlowTimeout time.Duration
+
+	// Accounts lists separate Tado accounts to collect instead of the single TokenPath account
+	Accounts []Account
 
 	// Server configuration
-	Port int
+	Port       int
+	AdminToken string // Optional: enables admin endpoints when set
+
+	// ReadyMaxAge is how recently a scrape must have succeeded for /ready to report ready (0 accepts any age)
+	ReadyMaxAge time.Duration
+
+	// HealthCheck probes the running exporter's /ready endpoint and exits instead of starting the exporter
+	HealthCheck bool
+
+	// Version prints the build details and exits instead of starting the exporter
+	Version bool
+
+	// ListMetrics prints every exported metric as JSON and exits instead of starting the exporter
+	ListMetrics bool
+
+	// ValidateOnly checks the configuration, stored token and Tado API access, then exits instead of starting the exporter
+	ValidateOnly bool
+
+	// ListHomes prints the ID and name of every home of the account and exits instead of starting the exporter
+	ListHomes bool
+
+	// EnableOpenMetrics serves OpenMetrics to scrapers that accept it; classic text format otherwise
+	EnableOpenMetrics bool
+
+	// EnableGoMetrics also exposes the Go runtime and process metrics on /metrics
+	EnableGoMetrics bool
+
+	// TLS configuration (optional)
+	TLSCertFile string
+	TLSKeyFile  string
+	TLSClientCA string // Requires verified client certificates for /metrics when set
 
 	// Tado API configuration
-	HomeID string
+	ServerURL      string // Tado API base URL (defaults to tado.ServerURL)
+	HomeID         string
+	ExcludeHomeIDs []string // Homes never collected, even without a HomeID filter
 
 	// Collection configuration
-	ScrapeTimeout int
+	ScrapeTimeout           int
+	MaxScrapeTimeout        int           // Cap on the X-Scrape-Timeout header of /metrics requests (0 ignores the header)
+	StrictMode              bool          // Emit no Tado metrics if any collection error occurs
+	SkipWeather             bool          // Don't call the weather endpoint; weather metrics are not exported
+	AllowNoHomes            bool          // Treat an account without homes as valid rather than an authentication error
+	TemperatureOffsets      bool          // Collect each zone's temperature offset, one Tado API call per zone
+	ZoneDeviceWorkers       int           // Maximum number of concurrent per-zone device calls
+	ZoneDeviceTimeout       time.Duration // Timeout of a single per-zone device call
+	ExposeEmail             bool          // Expose the raw account email on tado_exporter_account_info, not just its hash
+	PerHomeMetrics          bool          // Serve each home from an isolated registry at /metrics/<home_id>
+	SeparateExporterMetrics bool          // Serve exporter health metrics from their own registry at /metrics/exporter
+	SlowCallThreshold       time.Duration // Log Tado API calls slower than this (0 disables)
+	MaxLabelLength          int           // Truncate label values longer than this
+	TemperatureUnits        []string      // Temperature units to export: celsius, fahrenheit and/or kelvin
+	TemperaturePrecision    int           // Decimal places temperature metrics are rounded to (-1 disables rounding)
+	DeviceMetrics           string        // Per-device metrics to collect: off, basic or full
+	MetricCompat            string        // Metric naming scheme: v1 (legacy names) or v2 (convention-aligned names)
+
+	// ConstantLabels are added to every metric, e.g. to tell sites apart (nil for none)
+	ConstantLabels map[string]string
+
+	// Circuit breaker configuration (optional)
+	CircuitBreakerMaxFailures int           // Consecutive failed Tado API calls that open the breaker (0 disables)
+	CircuitBreakerOpenTimeout time.Duration // How long the breaker stays open before a trial call
+
+	// Rate limiter configuration (optional)
+	MaxRequestsPerMinute int // Maximum Tado API calls per minute (0 disables)
+
+	// Failing home configuration (optional)
+	HomeFailureThreshold int           // Scrapes in a row losing access to a home after which it is skipped (0 disables)
+	HomeFailureCooldown  time.Duration // How long a consistently failing home is skipped before it is retried
+
+	// Retry configuration (optional)
+	MaxRetries   int           // Times a Tado API call failing with a transient error is retried (0 disables)
+	RetryBackoff time.Duration // Wait before the first retry, doubling for every further retry
+
+	// Snapshot configuration (optional)
+	SnapshotPath   string        // Metric values are saved here on shutdown and restored on startup when set
+	SnapshotMaxAge time.Duration // Snapshots older than this are not restored (0 accepts any age)
+
+	// OTLP export configuration (optional)
+	OTLPEndpoint string        // Metrics are also pushed to this OTLP/HTTP receiver when set
+	OTLPInterval time.Duration // How often metrics are collected and pushed to the OTLP receiver
 
 	// Logging
-	LogLevel string
+	LogLevel          string
+	LogLevelCollector string // Overrides LogLevel for collection and Tado API logs when set
+	LogLevelAuth      string // Overrides LogLevel for authentication logs when set
+	LogLevelServer    string // Overrides LogLevel for HTTP server logs when set
+}
+
+// Account is a separate Tado account with its own token file
+type Account struct {
+	Name            string // Value of the account label on the account's metrics
+	TokenPath       string
+	TokenPassphrase string // Defaults to the top-level TokenPassphrase
 }
 
 // Load parses environment variables and command-line flags and returns a Config
@@ -61,10 +201,53 @@ func LoadWithArgs(args []string) *Config {
 	// Read environment variables
 	envTokenPath := os.Getenv("TADO_TOKEN_PATH")
 	envTokenPassphrase := os.Getenv("TADO_TOKEN_PASSPHRASE")
+	envAuthURLFile := os.Getenv("TADO_AUTH_URL_FILE")
+	envDeviceFlowTimeout := os.Getenv("TADO_DEVICE_FLOW_TIMEOUT")
+	envAccounts := os.Getenv("TADO_ACCOUNTS")
 	envPort := os.Getenv("TADO_PORT")
+	envReadyMaxAge := os.Getenv("TADO_READY_MAX_AGE")
+	envServerURL := os.Getenv("TADO_SERVER_URL")
 	envHomeID := os.Getenv("TADO_HOME_ID")
+	envExcludeHomeIDs := os.Getenv("TADO_EXCLUDE_HOME_IDS")
 	envScrapeTimeout := os.Getenv("TADO_SCRAPE_TIMEOUT")
+	envMaxScrapeTimeout := os.Getenv("TADO_MAX_SCRAPE_TIMEOUT")
 	envLogLevel := os.Getenv("TADO_LOG_LEVEL")
+	envLogLevelCollector := os.Getenv("TADO_LOG_LEVEL_COLLECTOR")
+	envLogLevelAuth := os.Getenv("TADO_LOG_LEVEL_AUTH")
+	envLogLevelServer := os.Getenv("TADO_LOG_LEVEL_SERVER")
+	envAdminToken := os.Getenv("TADO_ADMIN_TOKEN")
+	envStrictMode := os.Getenv("TADO_STRICT_MODE")
+	envSkipWeather := os.Getenv("TADO_SKIP_WEATHER")
+	envAllowNoHomes := os.Getenv("TADO_ALLOW_NO_HOMES")
+	envTemperatureOffsets := os.Getenv("TADO_COLLECT_TEMPERATURE_OFFSETS")
+	envZoneDeviceWorkers := os.Getenv("TADO_ZONE_DEVICE_WORKERS")
+	envZoneDeviceTimeout := os.Getenv("TADO_ZONE_DEVICE_TIMEOUT")
+	envExposeAccountEmail := os.Getenv("TADO_EXPOSE_ACCOUNT_EMAIL")
+	envPerHomeMetrics := os.Getenv("TADO_PER_HOME_METRICS")
+	envSeparateExporterMetrics := os.Getenv("TADO_SEPARATE_EXPORTER_METRICS")
+	envEnableOpenMetrics := os.Getenv("TADO_ENABLE_OPENMETRICS")
+	envEnableGoMetrics := os.Getenv("TADO_ENABLE_GO_METRICS")
+	envSlowCallThreshold := os.Getenv("TADO_SLOW_CALL_THRESHOLD")
+	envCircuitBreakerMaxFailures := os.Getenv("TADO_CIRCUIT_BREAKER_MAX_FAILURES")
+	envCircuitBreakerOpenTimeout := os.Getenv("TADO_CIRCUIT_BREAKER_OPEN_TIMEOUT")
+	envMaxRequestsPerMinute := os.Getenv("TADO_MAX_REQUESTS_PER_MINUTE")
+	envHomeFailureThreshold := os.Getenv("TADO_HOME_FAILURE_THRESHOLD")
+	envHomeFailureCooldown := os.Getenv("TADO_HOME_FAILURE_COOLDOWN")
+	envMaxRetries := os.Getenv("TADO_MAX_RETRIES")
+	envRetryBackoff := os.Getenv("TADO_RETRY_BACKOFF")
+	envMaxLabelLength := os.Getenv("TADO_MAX_LABEL_LENGTH")
+	envMetricCompat := os.Getenv("TADO_METRIC_COMPAT")
+	envTemperatureUnits := os.Getenv("TADO_TEMPERATURE_UNITS")
+	envTemperaturePrecision := os.Getenv("TADO_TEMPERATURE_PRECISION")
+	envDeviceMetrics := os.Getenv("TADO_DEVICE_METRICS")
+	envConstantLabels := os.Getenv("TADO_CONSTANT_LABELS")
+	envSnapshotPath := os.Getenv("TADO_SNAPSHOT_PATH")
+	envSnapshotMaxAge := os.Getenv("TADO_SNAPSHOT_MAX_AGE")
+	envOTLPEndpoint := os.Getenv("TADO_OTLP_ENDPOINT")
+	envOTLPInterval := os.Getenv("TADO_OTLP_INTERVAL")
+	envTLSCertFile := os.Getenv("TADO_TLS_CERT_FILE")
+	envTLSKeyFile := os.Getenv("TADO_TLS_KEY_FILE")
+	envTLSClientCA := os.Getenv("TADO_TLS_CLIENT_CA")
 
 	// Determine defaults
 	homeDir := os.Getenv("HOME")
@@ -89,6 +272,18 @@ func LoadWithArgs(args []string) *Config {
 	if envLogLevel == "" {
 		envLogLevel = "info"
 	}
+	if envServerURL == "" {
+		envServerURL = tado.ServerURL
+	}
+	if envMetricCompat == "" {
+		envMetricCompat = string(metrics.MetricCompatV1)
+	}
+	if envTemperatureUnits == "" {
+		envTemperatureUnits = "celsius,fahrenheit"
+	}
+	if envDeviceMetrics == "" {
+		envDeviceMetrics = string(metrics.DefaultDeviceMetricsLevel)
+	}
 
 	// Create a new FlagSet for this invocation (allows multiple calls in tests)
 	fs := flag.NewFlagSet("config", flag.ContinueOnError)
@@ -96,20 +291,118 @@ func LoadWithArgs(args []string) *Config {
 	// Parse command-line flags (these override env vars)
 	fs.StringVar(&cfg.TokenPath, "token-path", defaultTokenPath, "Path to store the encrypted token (env: TADO_TOKEN_PATH)")
 	fs.StringVar(&cfg.TokenPassphrase, "token-passphrase", envTokenPassphrase, "Passphrase to encrypt/decrypt the token (env: TADO_TOKEN_PASSPHRASE, required)")
+	accounts := fs.String("accounts", envAccounts, "Separate Tado accounts to collect, as comma-separated name=token_path[:passphrase] entries; the passphrase defaults to -token-passphrase (env: TADO_ACCOUNTS, optional)")
+	fs.StringVar(&cfg.AuthURLFile, "auth-url-file", envAuthURLFile, "Also write the authentication verification URL to this file, for non-interactive deployments (env: TADO_AUTH_URL_FILE, optional)")
+	fs.DurationVar(&cfg.DeviceFlowTimeout, "device-flow-timeout", parseEnvDuration(envDeviceFlowTimeout, 10*time.Minute), "Fail startup if the authentication verification URL is not visited within this duration, 0 waits indefinitely (env: TADO_DEVICE_FLOW_TIMEOUT)")
 
 	// Server configuration
 	fs.IntVar(&cfg.Port, "port", parseEnvInt(envPort, 9100), "HTTP server listen port (env: TADO_PORT)")
+	fs.DurationVar(&cfg.ReadyMaxAge, "ready-max-age", parseEnvDuration(envReadyMaxAge, 5*time.Minute), "Report /ready as not ready when no scrape has succeeded within this duration, 0 accepts any age (env: TADO_READY_MAX_AGE)")
+	fs.BoolVar(&cfg.HealthCheck, "health-check", false, "Check the exporter running on -port via /ready and exit 0 if ready, 1 otherwise; for container health checks")
+	fs.BoolVar(&cfg.Version, "version", false, "Print the version, commit and build date, then exit")
+	fs.BoolVar(&cfg.ListMetrics, "list-metrics", false, "Print the name, type, help and labels of every exported metric as JSON, honouring -metric-compat, then exit")
+	fs.BoolVar(&cfg.ValidateOnly, "validate", false, "Check the configuration, load the stored token without starting device authentication and call the Tado API once, print a pass/fail checklist, then exit 0 if all passed, 1 otherwise")
+	fs.BoolVar(&cfg.ListHomes, "list-homes", false, "Authenticate, starting device authentication if no token is stored, print the ID and name of every home of the account for -home-id and -exclude-home-ids, then exit")
+	fs.BoolVar(&cfg.EnableOpenMetrics, "enable-openmetrics", parseEnvBool(envEnableOpenMetrics, true), "Serve the OpenMetrics format to scrapers requesting it; false always serves classic Prometheus text (env: TADO_ENABLE_OPENMETRICS)")
+	fs.BoolVar(&cfg.EnableGoMetrics, "enable-go-metrics", parseEnvBool(envEnableGoMetrics, false), "Also expose the Go runtime (go_*) and process (process_*) metrics on /metrics (env: TADO_ENABLE_GO_METRICS)")
+	fs.StringVar(&cfg.AdminToken, "admin-token", envAdminToken, "Bearer token required by admin endpoints such as POST /scrape; endpoints are disabled when empty (env: TADO_ADMIN_TOKEN, optional)")
+	fs.StringVar(&cfg.TLSCertFile, "tls-cert-file", envTLSCertFile, "TLS certificate file; serves HTTPS when set together with -tls-key-file (env: TADO_TLS_CERT_FILE, optional)")
+	fs.StringVar(&cfg.TLSKeyFile, "tls-key-file", envTLSKeyFile, "TLS private key file (env: TADO_TLS_KEY_FILE, optional)")
+	fs.StringVar(&cfg.TLSClientCA, "tls-client-ca", envTLSClientCA, "CA bundle used to verify client certificates; /metrics then requires mTLS (env: TADO_TLS_CLIENT_CA, optional)")
+	fs.StringVar(&cfg.ServerURL, "server-url", envServerURL, "Tado API base URL, e.g. a stub server for integration tests (env: TADO_SERVER_URL)")
 	fs.StringVar(&cfg.HomeID, "home-id", envHomeID, "Tado Home ID (env: TADO_HOME_ID, optional)")
+	excludeHomeIDs := fs.String("exclude-home-ids", envExcludeHomeIDs, "Comma-separated Tado Home IDs to skip (env: TADO_EXCLUDE_HOME_IDS, optional)")
 	fs.IntVar(&cfg.ScrapeTimeout, "scrape-timeout", parseEnvInt(envScrapeTimeout, 10), "Maximum time in seconds to wait for API response (env: TADO_SCRAPE_TIMEOUT)")
+	fs.IntVar(&cfg.MaxScrapeTimeout, "max-scrape-timeout", parseEnvInt(envMaxScrapeTimeout, 0), "Maximum scrape timeout in seconds a /metrics request may ask for with the X-Scrape-Timeout header; 0 ignores the header (env: TADO_MAX_SCRAPE_TIMEOUT)")
+	fs.DurationVar(&cfg.SlowCallThreshold, "slow-call-threshold", parseEnvDuration(envSlowCallThreshold, 2*time.Second), "Log a warning for Tado API calls slower than this duration, 0 disables (env: TADO_SLOW_CALL_THRESHOLD)")
+	fs.IntVar(&cfg.CircuitBreakerMaxFailures, "circuit-breaker-max-failures", parseEnvInt(envCircuitBreakerMaxFailures, 0), "Stop calling the Tado API after this many consecutive failed calls, 0 disables the circuit breaker (env: TADO_CIRCUIT_BREAKER_MAX_FAILURES)")
+	fs.DurationVar(&cfg.CircuitBreakerOpenTimeout, "circuit-breaker-open-timeout", parseEnvDuration(envCircuitBreakerOpenTimeout, time.Minute), "How long the circuit breaker stops Tado API calls before letting a trial call through (env: TADO_CIRCUIT_BREAKER_OPEN_TIMEOUT)")
+	fs.IntVar(&cfg.MaxRequestsPerMinute, "max-requests-per-minute", parseEnvInt(envMaxRequestsPerMinute, 0), "Maximum Tado API calls per minute; calls over the limit wait, or fail if the scrape timeout would pass first, 0 disables (env: TADO_MAX_REQUESTS_PER_MINUTE)")
+	fs.IntVar(&cfg.HomeFailureThreshold, "home-failure-threshold", parseEnvInt(envHomeFailureThreshold, 0), "Skip a home after this many scrapes in a row lost access to it (home state or zones failing, or 401/403/404), e.g. after access was revoked, 0 disables (env: TADO_HOME_FAILURE_THRESHOLD)")
+	fs.DurationVar(&cfg.HomeFailureCooldown, "home-failure-cooldown", parseEnvDuration(envHomeFailureCooldown, 15*time.Minute), "How long a consistently failing home is skipped before it is tried again (env: TADO_HOME_FAILURE_COOLDOWN)")
+	fs.IntVar(&cfg.MaxRetries, "max-retries", parseEnvInt(envMaxRetries, 0), "Retry Tado API calls failing with a network error, 5xx or 429 status up to this many times within the scrape timeout, 0 disables (env: TADO_MAX_RETRIES)")
+	fs.DurationVar(&cfg.RetryBackoff, "retry-backoff", parseEnvDuration(envRetryBackoff, 500*time.Millisecond), "Wait before the first retry of a Tado API call, doubling for every further retry (env: TADO_RETRY_BACKOFF)")
+	fs.IntVar(&cfg.MaxLabelLength, "max-label-length", parseEnvInt(envMaxLabelLength, 128), "Maximum length of label values such as zone names; longer values are truncated, 0 disables (env: TADO_MAX_LABEL_LENGTH)")
+	constantLabels := fs.String("constant-labels", envConstantLabels, "Labels added to every metric, as comma-separated name=value pairs such as site=london,env=prod (env: TADO_CONSTANT_LABELS, optional)")
+	fs.StringVar(&cfg.MetricCompat, "metric-compat", envMetricCompat, "Metric naming scheme: v1 keeps the legacy names, v2 uses Prometheus convention-aligned names (env: TADO_METRIC_COMPAT)")
+	temperatureUnits := fs.String("temperature-units", envTemperatureUnits, "Comma-separated temperature units to export: celsius, fahrenheit, kelvin (env: TADO_TEMPERATURE_UNITS)")
+	fs.IntVar(&cfg.TemperaturePrecision, "temperature-precision", parseEnvInt(envTemperaturePrecision, -1), "Decimal places temperature metrics are rounded to, e.g. 1 reports 20.3400002 as 20.3; -1 disables rounding (env: TADO_TEMPERATURE_PRECISION)")
+	fs.StringVar(&cfg.DeviceMetrics, "device-metrics", envDeviceMetrics, "Per-device metrics to collect, each device adding its own series: off, basic (battery and connection state) or full (adds the firmware version) (env: TADO_DEVICE_METRICS)")
+	fs.BoolVar(&cfg.StrictMode, "strict-mode", parseEnvBool(envStrictMode, false), "Emit no Tado metrics when any collection error occurs, so failures are visible (env: TADO_STRICT_MODE)")
+	fs.BoolVar(&cfg.SkipWeather, "skip-weather", parseEnvBool(envSkipWeather, false), "Skip weather collection (solar intensity, outside temperature), saving one Tado API call per home per scrape (env: TADO_SKIP_WEATHER)")
+	fs.BoolVar(&cfg.AllowNoHomes, "allow-no-homes", parseEnvBool(envAllowNoHomes, false), "Treat an account without homes as valid, e.g. before the home is set up, instead of reporting an authentication error (env: TADO_ALLOW_NO_HOMES)")
+	fs.BoolVar(&cfg.TemperatureOffsets, "collect-temperature-offsets", parseEnvBool(envTemperatureOffsets, false), "Collect the temperature offset of each zone's leading device, costing one Tado API call per zone per scrape (env: TADO_COLLECT_TEMPERATURE_OFFSETS)")
+	fs.IntVar(&cfg.ZoneDeviceWorkers, "zone-device-workers", parseEnvInt(envZoneDeviceWorkers, 4), "Maximum number of per-zone device calls, such as temperature offsets, made at once (env: TADO_ZONE_DEVICE_WORKERS)")
+	fs.DurationVar(&cfg.ZoneDeviceTimeout, "zone-device-timeout", parseEnvDuration(envZoneDeviceTimeout, 2*time.Second), "Timeout of a single per-zone device call; a slow device only loses its own zone's metrics (env: TADO_ZONE_DEVICE_TIMEOUT)")
+	fs.BoolVar(&cfg.ExposeEmail, "expose-account-email", parseEnvBool(envExposeAccountEmail, false), "Add the raw account email to tado_exporter_account_info; by default only a hash is exposed (env: TADO_EXPOSE_ACCOUNT_EMAIL)")
+	fs.BoolVar(&cfg.PerHomeMetrics, "per-home-metrics", parseEnvBool(envPerHomeMetrics, false), "Also serve each home's metrics from an isolated registry at /metrics/<home_id> (env: TADO_PER_HOME_METRICS)")
+	fs.BoolVar(&cfg.SeparateExporterMetrics, "separate-exporter-metrics", parseEnvBool(envSeparateExporterMetrics, false), "Serve exporter health metrics at /metrics/exporter, leaving only Tado metrics on /metrics (env: TADO_SEPARATE_EXPORTER_METRICS)")
+	fs.StringVar(&cfg.SnapshotPath, "snapshot-path", envSnapshotPath, "File to save metric values to on shutdown and restore them from on startup (env: TADO_SNAPSHOT_PATH, optional)")
+	fs.DurationVar(&cfg.SnapshotMaxAge, "snapshot-max-age", parseEnvDuration(envSnapshotMaxAge, 15*time.Minute), "Ignore snapshots older than this on startup, 0 accepts any age (env: TADO_SNAPSHOT_MAX_AGE)")
+	fs.StringVar(&cfg.OTLPEndpoint, "otlp-endpoint", envOTLPEndpoint, "OTLP/HTTP receiver to also push the metrics to, e.g. http://otel-collector:4318 (env: TADO_OTLP_ENDPOINT, optional)")
+	fs.DurationVar(&cfg.OTLPInterval, "otlp-interval", parseEnvDuration(envOTLPInterval, 60*time.Second), "How often metrics are collected and pushed to the OTLP receiver (env: TADO_OTLP_INTERVAL)")
 	fs.StringVar(&cfg.LogLevel, "log-level", envLogLevel, "Logging verbosity: debug, info, warn, error (env: TADO_LOG_LEVEL)")
+	fs.StringVar(&cfg.LogLevelCollector, "log-level-collector", envLogLevelCollector, "Logging verbosity for collection and Tado API calls, overriding -log-level (env: TADO_LOG_LEVEL_COLLECTOR, optional)")
+	fs.StringVar(&cfg.LogLevelAuth, "log-level-auth", envLogLevelAuth, "Logging verbosity for authentication, overriding -log-level (env: TADO_LOG_LEVEL_AUTH, optional)")
+	fs.StringVar(&cfg.LogLevelServer, "log-level-server", envLogLevelServer, "Logging verbosity for the HTTP server, overriding -log-level (env: TADO_LOG_LEVEL_SERVER, optional)")
 
 	// Parse args - in production this will be os.Args, in tests can be empty or custom
 	// FlagSet is configured with ContinueOnError, so parse errors are handled gracefully
 	_ = fs.Parse(args)
 
+	cfg.ExcludeHomeIDs = parseList(*excludeHomeIDs)
+	cfg.TemperatureUnits = parseList(*temperatureUnits)
+	cfg.Accounts = parseAccounts(*accounts, cfg.TokenPassphrase)
+	cfg.ConstantLabels = parseConstantLabels(*constantLabels)
+
 	return cfg
 }
 
+// parseList splits a comma-separated value into its trimmed, non-empty items
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseAccounts parses comma-separated name=token_path[:passphrase] entries
+// Accounts without a passphrase use defaultPassphrase; malformed entries are left for Validate to reject
+func parseAccounts(value, defaultPassphrase string) []Account {
+	var accounts []Account
+	for _, entry := range parseList(value) {
+		name, location, _ := strings.Cut(entry, "=")
+		tokenPath, passphrase, _ := strings.Cut(location, ":")
+		if passphrase == "" {
+			passphrase = defaultPassphrase
+		}
+		accounts = append(accounts, Account{
+			Name:            strings.TrimSpace(name),
+			TokenPath:       strings.TrimSpace(tokenPath),
+			TokenPassphrase: passphrase,
+		})
+	}
+	return accounts
+}
+
+// parseConstantLabels parses comma-separated name=value pairs, returning nil if there are none
+// Malformed entries are kept with an empty value for Validate to reject
+func parseConstantLabels(value string) map[string]string {
+	entries := parseList(value)
+	if len(entries) == 0 {
+		return nil
+	}
+	labels := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		name, labelValue, _ := strings.Cut(entry, "=")
+		labels[strings.TrimSpace(name)] = strings.TrimSpace(labelValue)
+	}
+	return labels
+}
+
 // parseEnvInt parses an environment variable as an integer, returning default if invalid
 func parseEnvInt(envValue string, defaultValue int) int {
 	if envValue == "" {
@@ -123,20 +416,160 @@ func parseEnvInt(envValue string, defaultValue int) int {
 	return result
 }
 
+// parseEnvBool parses an environment variable as a boolean, returning default if invalid
+func parseEnvBool(envValue string, defaultValue bool) bool {
+	if envValue == "" {
+		return defaultValue
+	}
+	result, err := strconv.ParseBool(envValue)
+	if err != nil {
+		return defaultValue
+	}
+	return result
+}
+
+// parseEnvDuration parses an environment variable as a duration (e.g. "2s"), returning default if invalid
+func parseEnvDuration(envValue string, defaultValue time.Duration) time.Duration {
+	if envValue == "" {
+		return defaultValue
+	}
+	result, err := time.ParseDuration(envValue)
+	if err != nil {
+		return defaultValue
+	}
+	return result
+}
+
 // Validate checks if the configuration is valid
 func (c *Config) Validate() error {
-	if c.TokenPassphrase == "" {
+	if c.TokenPassphrase == "" && len(c.Accounts) == 0 {
 		return fmt.Errorf("token-passphrase is required (use -token-passphrase flag or TADO_TOKEN_PASSPHRASE env var)")
 	}
 
+	if err := c.validateAccounts(); err != nil {
+		return err
+	}
+
 	if c.Port < 1 || c.Port > 65535 {
 		return fmt.Errorf("invalid port: %d (must be between 1 and 65535)", c.Port)
 	}
 
+	if c.DeviceFlowTimeout < 0 {
+		return fmt.Errorf("invalid device-flow-timeout: %s (must not be negative, 0 waits indefinitely)", c.DeviceFlowTimeout)
+	}
+
+	if c.ReadyMaxAge < 0 {
+		return fmt.Errorf("invalid ready-max-age: %s (must not be negative)", c.ReadyMaxAge)
+	}
+
 	if c.ScrapeTimeout < 1 {
 		return fmt.Errorf("invalid scrape-timeout: %d (must be at least 1 second)", c.ScrapeTimeout)
 	}
 
+	if c.MaxScrapeTimeout != 0 && c.MaxScrapeTimeout < c.ScrapeTimeout {
+		return fmt.Errorf("invalid max-scrape-timeout: %d (must be 0 or at least scrape-timeout %d)", c.MaxScrapeTimeout, c.ScrapeTimeout)
+	}
+
+	if c.ServerURL != "" {
+		serverURL, err := url.Parse(c.ServerURL)
+		if err != nil || (serverURL.Scheme != "http" && serverURL.Scheme != "https") || serverURL.Host == "" {
+			return fmt.Errorf("invalid server-url: %q (must be an absolute http or https URL)", c.ServerURL)
+		}
+	}
+
+	if c.MaxLabelLength < 0 {
+		return fmt.Errorf("invalid max-label-length: %d (must be non-negative, 0 disables truncation)", c.MaxLabelLength)
+	}
+
+	if _, err := metrics.ParseMetricCompat(c.MetricCompat); err != nil {
+		return fmt.Errorf("invalid metric-compat: %w", err)
+	}
+
+	if _, err := metrics.ParseTemperatureUnits(c.TemperatureUnits); err != nil {
+		return fmt.Errorf("invalid temperature-units: %w", err)
+	}
+
+	if c.TemperaturePrecision < -1 || c.TemperaturePrecision > 6 {
+		return fmt.Errorf("invalid temperature-precision: %d (must be between 0 and 6 decimal places, or -1 to disable rounding)", c.TemperaturePrecision)
+	}
+
+	if _, err := metrics.ParseDeviceMetricsLevel(c.DeviceMetrics); err != nil {
+		return fmt.Errorf("invalid device-metrics: %w", err)
+	}
+
+	if c.TemperatureOffsets {
+		if c.ZoneDeviceWorkers < 1 {
+			return fmt.Errorf("invalid zone-device-workers: %d (must be at least 1)", c.ZoneDeviceWorkers)
+		}
+		if c.ZoneDeviceTimeout <= 0 {
+			return fmt.Errorf("invalid zone-device-timeout: %s (must be positive)", c.ZoneDeviceTimeout)
+		}
+	}
+
+	if c.SlowCallThreshold < 0 {
+		return fmt.Errorf("invalid slow-call-threshold: %s (must not be negative)", c.SlowCallThreshold)
+	}
+
+	for _, homeID := range c.ExcludeHomeIDs {
+		if homeID == c.HomeID {
+			return fmt.Errorf("home-id %s is also listed in exclude-home-ids", homeID)
+		}
+	}
+
+	if c.CircuitBreakerMaxFailures < 0 {
+		return fmt.Errorf("invalid circuit-breaker-max-failures: %d (must be non-negative, 0 disables the circuit breaker)", c.CircuitBreakerMaxFailures)
+	}
+
+	if c.CircuitBreakerMaxFailures > 0 && c.CircuitBreakerOpenTimeout <= 0 {
+		return fmt.Errorf("invalid circuit-breaker-open-timeout: %s (must be positive when the circuit breaker is enabled)", c.CircuitBreakerOpenTimeout)
+	}
+
+	if err := validateConstantLabels(c.ConstantLabels); err != nil {
+		return err
+	}
+
+	if c.HomeFailureThreshold < 0 {
+		return fmt.Errorf("invalid home-failure-threshold: %d (must be non-negative, 0 disables skipping failing homes)", c.HomeFailureThreshold)
+	}
+
+	if c.HomeFailureThreshold > 0 && c.HomeFailureCooldown <= 0 {
+		return fmt.Errorf("invalid home-failure-cooldown: %s (must be positive when skipping failing homes is enabled)", c.HomeFailureCooldown)
+	}
+
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("invalid max-retries: %d (must be non-negative, 0 disables retries)", c.MaxRetries)
+	}
+
+	if c.MaxRetries > 0 && c.RetryBackoff <= 0 {
+		return fmt.Errorf("invalid retry-backoff: %s (must be positive when retries are enabled)", c.RetryBackoff)
+	}
+
+	if c.MaxRequestsPerMinute < 0 {
+		return fmt.Errorf("invalid max-requests-per-minute: %d (must be non-negative, 0 disables rate limiting)", c.MaxRequestsPerMinute)
+	}
+
+	if c.SnapshotMaxAge < 0 {
+		return fmt.Errorf("invalid snapshot-max-age: %s (must not be negative)", c.SnapshotMaxAge)
+	}
+
+	if c.OTLPEndpoint != "" {
+		otlpURL, err := url.Parse(c.OTLPEndpoint)
+		if err != nil || (otlpURL.Scheme != "http" && otlpURL.Scheme != "https") || otlpURL.Host == "" {
+			return fmt.Errorf("invalid otlp-endpoint: %q (must be an absolute http or https URL)", c.OTLPEndpoint)
+		}
+		if c.OTLPInterval < time.Second {
+			return fmt.Errorf("invalid otlp-interval: %s (must be at least 1s)", c.OTLPInterval)
+		}
+	}
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return fmt.Errorf("tls-cert-file and tls-key-file must be set together")
+	}
+
+	if c.TLSClientCA != "" && c.TLSCertFile == "" {
+		return fmt.Errorf("tls-client-ca requires tls-cert-file and tls-key-file to be set")
+	}
+
 	validLogLevels := map[string]bool{
 		"debug": true,
 		"info":  true,
@@ -147,11 +580,161 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid log-level: %s (must be one of: debug, info, warn, error)", c.LogLevel)
 	}
 
+	subsystemLogLevels := []struct {
+		name  string
+		level string
+	}{
+		{"log-level-collector", c.LogLevelCollector},
+		{"log-level-auth", c.LogLevelAuth},
+		{"log-level-server", c.LogLevelServer},
+	}
+	for _, subsystem := range subsystemLogLevels {
+		if subsystem.level != "" && !validLogLevels[subsystem.level] {
+			return fmt.Errorf("invalid %s: %s (must be one of: debug, info, warn, error)", subsystem.name, subsystem.level)
+		}
+	}
+
+	return nil
+}
+
+// labelNamePattern matches valid Prometheus label names
+var labelNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// validateConstantLabels checks that every constant label has a valid name and a value
+// Clashes with the exporter's own label names are caught when the metrics are validated on startup
+func validateConstantLabels(labels map[string]string) error {
+	for name, value := range labels {
+		if !labelNamePattern.MatchString(name) || strings.HasPrefix(name, "__") {
+			return fmt.Errorf("invalid constant-labels: %q is not a valid label name", name)
+		}
+		if value == "" {
+			return fmt.Errorf("invalid constant-labels: label %q has no value (format: name=value)", name)
+		}
+	}
+	return nil
+}
+
+// validateAccounts checks the accounts list and the options that can't be combined with it
+func (c *Config) validateAccounts() error {
+	if len(c.Accounts) == 0 {
+		return nil
+	}
+
+	names := make(map[string]bool, len(c.Accounts))
+	for _, account := range c.Accounts {
+		if account.Name == "" || account.TokenPath == "" {
+			return fmt.Errorf("invalid accounts entry for %q: name and token path are required (format: name=token_path[:passphrase])", account.Name)
+		}
+		if names[account.Name] {
+			return fmt.Errorf("invalid accounts: account %q is listed more than once", account.Name)
+		}
+		names[account.Name] = true
+		if account.TokenPassphrase == "" {
+			return fmt.Errorf("account %q has no passphrase (add one to its accounts entry or set token-passphrase)", account.Name)
+		}
+	}
+
+	// Both rely on a single set of metric descriptors
+	if c.PerHomeMetrics {
+		return fmt.Errorf("accounts and per-home-metrics cannot be used together")
+	}
+	if c.SnapshotPath != "" {
+		return fmt.Errorf("accounts and snapshot-path cannot be used together")
+	}
 	return nil
 }
 
 // String returns a string representation of the config (without sensitive data)
 func (c *Config) String() string {
-	return fmt.Sprintf("Config{Port: %d, TokenPath: %s, HomeID: %s, ScrapeTimeout: %ds, LogLevel: %s}",
-		c.Port, c.TokenPath, c.HomeID, c.ScrapeTimeout, c.LogLevel)
+	return fmt.Sprintf("Config{Port: %d, TLS: %t, ClientCertAuth: %t, TokenPath: %s, HomeID: %s, ScrapeTimeout: %ds, StrictMode: %t, PerHomeMetrics: %t, LogLevel: %s}",
+		c.Port, c.TLSCertFile != "", c.TLSClientCA != "", c.TokenPath, c.HomeID, c.ScrapeTimeout, c.StrictMode, c.PerHomeMetrics, c.LogLevel)
+}
+
+// Feature is an optional feature and whether the configuration enables it
+type Feature struct {
+	Name    string
+	Enabled bool
+}
+
+// Features lists the optional features, in a fixed order, and whether each is enabled, so each instance
+// can report which of them are active
+func (c *Config) Features() []Feature {
+	return []Feature{
+		{Name: "tls", Enabled: c.TLSCertFile != ""},
+		{Name: "client_cert_auth", Enabled: c.TLSClientCA != ""},
+		{Name: "admin_endpoints", Enabled: c.AdminToken != ""},
+		{Name: "multi_account", Enabled: len(c.Accounts) > 0},
+		{Name: "snapshot", Enabled: c.SnapshotPath != ""},
+		{Name: "circuit_breaker", Enabled: c.CircuitBreakerMaxFailures > 0},
+		{Name: "rate_limiter", Enabled: c.MaxRequestsPerMinute > 0},
+		{Name: "retries", Enabled: c.MaxRetries > 0},
+		{Name: "home_failure_skip", Enabled: c.HomeFailureThreshold > 0},
+		{Name: "otlp", Enabled: c.OTLPEndpoint != ""},
+		{Name: "per_home_metrics", Enabled: c.PerHomeMetrics},
+		{Name: "separate_exporter_metrics", Enabled: c.SeparateExporterMetrics},
+		{Name: "openmetrics", Enabled: c.EnableOpenMetrics},
+		{Name: "go_metrics", Enabled: c.EnableGoMetrics},
+		{Name: "strict_mode", Enabled: c.StrictMode},
+		{Name: "skip_weather", Enabled: c.SkipWeather},
+		{Name: "temperature_offsets", Enabled: c.TemperatureOffsets},
+		{Name: "device_metrics", Enabled: c.DeviceMetrics != "" && c.DeviceMetrics != string(metrics.DeviceMetricsOff)},
+	}
+}
+
+// LogFields returns the effective configuration as key-value pairs for a structured log, one key per setting
+// Secrets are never included: the token passphrase and admin token are only reported as set or not
+func (c *Config) LogFields() []interface{} {
+	accountNames := make([]string, 0, len(c.Accounts))
+	for _, account := range c.Accounts {
+		accountNames = append(accountNames, account.Name)
+	}
+
+	return []interface{}{
+		"port", c.Port,
+		"tls", c.TLSCertFile != "",
+		"client_cert_auth", c.TLSClientCA != "",
+		"openmetrics", c.EnableOpenMetrics,
+		"go_metrics", c.EnableGoMetrics,
+		"admin_endpoints", c.AdminToken != "",
+		"ready_max_age", c.ReadyMaxAge.String(),
+		"token_path", c.TokenPath,
+		"token_passphrase_set", c.TokenPassphrase != "",
+		"accounts", accountNames,
+		"server_url", c.ServerURL,
+		"home_id", c.HomeID,
+		"exclude_home_ids", c.ExcludeHomeIDs,
+		"scrape_timeout_seconds", c.ScrapeTimeout,
+		"max_scrape_timeout_seconds", c.MaxScrapeTimeout,
+		"strict_mode", c.StrictMode,
+		"skip_weather", c.SkipWeather,
+		"allow_no_homes", c.AllowNoHomes,
+		"collect_temperature_offsets", c.TemperatureOffsets,
+		"zone_device_workers", c.ZoneDeviceWorkers,
+		"zone_device_timeout", c.ZoneDeviceTimeout.String(),
+		"expose_account_email", c.ExposeEmail,
+		"per_home_metrics", c.PerHomeMetrics,
+		"separate_exporter_metrics", c.SeparateExporterMetrics,
+		"slow_call_threshold", c.SlowCallThreshold.String(),
+		"max_label_length", c.MaxLabelLength,
+		"metric_compat", c.MetricCompat,
+		"temperature_units", c.TemperatureUnits,
+		"temperature_precision", c.TemperaturePrecision,
+		"device_metrics", c.DeviceMetrics,
+		"constant_labels", c.ConstantLabels,
+		"circuit_breaker_max_failures", c.CircuitBreakerMaxFailures,
+		"circuit_breaker_open_timeout", c.CircuitBreakerOpenTimeout.String(),
+		"max_requests_per_minute", c.MaxRequestsPerMinute,
+		"home_failure_threshold", c.HomeFailureThreshold,
+		"home_failure_cooldown", c.HomeFailureCooldown.String(),
+		"max_retries", c.MaxRetries,
+		"retry_backoff", c.RetryBackoff.String(),
+		"snapshot_path", c.SnapshotPath,
+		"snapshot_max_age", c.SnapshotMaxAge.String(),
+		"otlp_endpoint", c.OTLPEndpoint,
+		"otlp_interval", c.OTLPInterval.String(),
+		"log_level", c.LogLevel,
+		"log_level_collector", c.LogLevelCollector,
+		"log_level_auth", c.LogLevelAuth,
+		"log_level_server", c.LogLevelServer,
+	}
 }
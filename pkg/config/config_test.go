@@ -1,9 +1,13 @@
 package config
 
 import (
+	"bytes"
+	"encoding/json"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/andreweacott/tado-prometheus-exporter/pkg/logger"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -253,3 +257,728 @@ func TestString(t *testing.T) {
 	assert.Contains(t, str, "ScrapeTimeout: 10s")
 	assert.NotContains(t, str, "secret") // Don't leak password
 }
+
+// TestLoad_StrictMode tests strict mode from environment variables and CLI flags
+func TestLoad_StrictMode(t *testing.T) {
+	_ = os.Unsetenv("TADO_STRICT_MODE")
+	assert.False(t, LoadWithArgs([]string{}).StrictMode)
+
+	_ = os.Setenv("TADO_STRICT_MODE", "true")
+	defer func() { _ = os.Unsetenv("TADO_STRICT_MODE") }()
+	assert.True(t, LoadWithArgs([]string{}).StrictMode)
+
+	// CLI flag overrides environment variable
+	assert.False(t, LoadWithArgs([]string{"-strict-mode=false"}).StrictMode)
+}
+
+// TestLoad_DeviceFlowTimeout tests the device flow timeout default, environment variable and validation
+func TestLoad_DeviceFlowTimeout(t *testing.T) {
+	_ = os.Unsetenv("TADO_DEVICE_FLOW_TIMEOUT")
+	assert.Equal(t, 10*time.Minute, LoadWithArgs([]string{}).DeviceFlowTimeout)
+
+	_ = os.Setenv("TADO_DEVICE_FLOW_TIMEOUT", "2m")
+	defer func() { _ = os.Unsetenv("TADO_DEVICE_FLOW_TIMEOUT") }()
+	assert.Equal(t, 2*time.Minute, LoadWithArgs([]string{}).DeviceFlowTimeout)
+
+	cfg := LoadWithArgs([]string{"-token-passphrase=secret", "-device-flow-timeout=-1s"})
+	err := cfg.Validate()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "device-flow-timeout")
+	}
+}
+
+// TestLoad_SkipWeather tests the skip weather flag and environment variable
+func TestLoad_SkipWeather(t *testing.T) {
+	_ = os.Unsetenv("TADO_SKIP_WEATHER")
+	assert.False(t, LoadWithArgs([]string{}).SkipWeather)
+
+	_ = os.Setenv("TADO_SKIP_WEATHER", "true")
+	defer func() { _ = os.Unsetenv("TADO_SKIP_WEATHER") }()
+	assert.True(t, LoadWithArgs([]string{}).SkipWeather)
+
+	// CLI flag overrides environment variable
+	assert.False(t, LoadWithArgs([]string{"-skip-weather=false"}).SkipWeather)
+}
+
+// TestLoad_AllowNoHomes tests the allow no homes flag and environment variable
+func TestLoad_AllowNoHomes(t *testing.T) {
+	_ = os.Unsetenv("TADO_ALLOW_NO_HOMES")
+	assert.False(t, LoadWithArgs([]string{}).AllowNoHomes)
+
+	_ = os.Setenv("TADO_ALLOW_NO_HOMES", "true")
+	defer func() { _ = os.Unsetenv("TADO_ALLOW_NO_HOMES") }()
+	assert.True(t, LoadWithArgs([]string{}).AllowNoHomes)
+
+	// CLI flag overrides environment variable
+	assert.False(t, LoadWithArgs([]string{"-allow-no-homes=false"}).AllowNoHomes)
+}
+
+// TestLoad_SeparateExporterMetrics tests the separate exporter metrics flag and environment variable
+func TestLoad_SeparateExporterMetrics(t *testing.T) {
+	_ = os.Unsetenv("TADO_SEPARATE_EXPORTER_METRICS")
+	assert.False(t, LoadWithArgs([]string{}).SeparateExporterMetrics)
+
+	_ = os.Setenv("TADO_SEPARATE_EXPORTER_METRICS", "true")
+	defer func() { _ = os.Unsetenv("TADO_SEPARATE_EXPORTER_METRICS") }()
+	assert.True(t, LoadWithArgs([]string{}).SeparateExporterMetrics)
+
+	// CLI flag overrides environment variable
+	assert.False(t, LoadWithArgs([]string{"-separate-exporter-metrics=false"}).SeparateExporterMetrics)
+}
+
+// TestLoad_PerHomeMetrics tests the per-home metrics flag and environment variable
+func TestLoad_PerHomeMetrics(t *testing.T) {
+	_ = os.Unsetenv("TADO_PER_HOME_METRICS")
+	assert.False(t, LoadWithArgs([]string{}).PerHomeMetrics)
+
+	_ = os.Setenv("TADO_PER_HOME_METRICS", "true")
+	defer func() { _ = os.Unsetenv("TADO_PER_HOME_METRICS") }()
+	assert.True(t, LoadWithArgs([]string{}).PerHomeMetrics)
+
+	// CLI flag overrides environment variable
+	assert.False(t, LoadWithArgs([]string{"-per-home-metrics=false"}).PerHomeMetrics)
+}
+
+// TestParseEnvBool tests boolean parsing from environment values
+func TestParseEnvBool(t *testing.T) {
+	tests := []struct {
+		name         string
+		envValue     string
+		defaultValue bool
+		expected     bool
+	}{
+		{"true value", "true", false, true},
+		{"false value", "false", true, false},
+		{"numeric true", "1", false, true},
+		{"empty value uses default", "", true, true},
+		{"invalid value uses default", "maybe", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := parseEnvBool(tt.envValue, tt.defaultValue)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+// TestValidate_TLS tests validation of TLS options
+func TestValidate_TLS(t *testing.T) {
+	tests := []struct {
+		name        string
+		certFile    string
+		keyFile     string
+		clientCA    string
+		expectedErr string
+	}{
+		{"no TLS", "", "", "", ""},
+		{"TLS with cert and key", "server.crt", "server.key", "", ""},
+		{"mTLS with cert, key and client CA", "server.crt", "server.key", "ca.crt", ""},
+		{"cert without key", "server.crt", "", "", "must be set together"},
+		{"key without cert", "", "server.key", "", "must be set together"},
+		{"client CA without server TLS", "", "", "ca.crt", "tls-client-ca requires"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				TokenPath:       "/tmp/token.json",
+				TokenPassphrase: "test",
+				Port:            9100,
+				ScrapeTimeout:   10,
+				LogLevel:        "info",
+				TLSCertFile:     tt.certFile,
+				TLSKeyFile:      tt.keyFile,
+				TLSClientCA:     tt.clientCA,
+			}
+
+			err := cfg.Validate()
+
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+			}
+		})
+	}
+}
+
+// TestLoad_SlowCallThreshold tests the slow call threshold default, env var and flag
+func TestLoad_SlowCallThreshold(t *testing.T) {
+	_ = os.Unsetenv("TADO_SLOW_CALL_THRESHOLD")
+	assert.Equal(t, 2*time.Second, LoadWithArgs([]string{}).SlowCallThreshold)
+
+	_ = os.Setenv("TADO_SLOW_CALL_THRESHOLD", "500ms")
+	defer func() { _ = os.Unsetenv("TADO_SLOW_CALL_THRESHOLD") }()
+	assert.Equal(t, 500*time.Millisecond, LoadWithArgs([]string{}).SlowCallThreshold)
+
+	// CLI flag overrides environment variable
+	assert.Equal(t, 5*time.Second, LoadWithArgs([]string{"-slow-call-threshold=5s"}).SlowCallThreshold)
+
+	// Invalid env value falls back to default
+	_ = os.Setenv("TADO_SLOW_CALL_THRESHOLD", "slow")
+	assert.Equal(t, 2*time.Second, LoadWithArgs([]string{}).SlowCallThreshold)
+}
+
+// TestLoad_MaxLabelLength tests the max label length default, env var, flag and validation
+func TestLoad_MaxLabelLength(t *testing.T) {
+	_ = os.Unsetenv("TADO_MAX_LABEL_LENGTH")
+	assert.Equal(t, 128, LoadWithArgs([]string{}).MaxLabelLength)
+
+	_ = os.Setenv("TADO_MAX_LABEL_LENGTH", "64")
+	defer func() { _ = os.Unsetenv("TADO_MAX_LABEL_LENGTH") }()
+	assert.Equal(t, 64, LoadWithArgs([]string{}).MaxLabelLength)
+
+	// CLI flag overrides environment variable
+	assert.Equal(t, 32, LoadWithArgs([]string{"-max-label-length=32"}).MaxLabelLength)
+
+	cfg := &Config{Port: 9100, ScrapeTimeout: 10, LogLevel: "info", TokenPassphrase: "test", MaxLabelLength: -1}
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "max-label-length")
+}
+
+// TestLoad_EnableOpenMetrics tests the OpenMetrics toggle default, env var and flag
+func TestLoad_EnableOpenMetrics(t *testing.T) {
+	_ = os.Unsetenv("TADO_ENABLE_OPENMETRICS")
+	assert.True(t, LoadWithArgs([]string{}).EnableOpenMetrics)
+
+	_ = os.Setenv("TADO_ENABLE_OPENMETRICS", "false")
+	defer func() { _ = os.Unsetenv("TADO_ENABLE_OPENMETRICS") }()
+	assert.False(t, LoadWithArgs([]string{}).EnableOpenMetrics)
+
+	// CLI flag overrides environment variable
+	assert.True(t, LoadWithArgs([]string{"-enable-openmetrics=true"}).EnableOpenMetrics)
+}
+
+// TestLoad_Snapshot tests the snapshot path and max age options and validation
+func TestLoad_Snapshot(t *testing.T) {
+	_ = os.Unsetenv("TADO_SNAPSHOT_PATH")
+	_ = os.Unsetenv("TADO_SNAPSHOT_MAX_AGE")
+	cfg := LoadWithArgs([]string{})
+	assert.Equal(t, "", cfg.SnapshotPath)
+	assert.Equal(t, 15*time.Minute, cfg.SnapshotMaxAge)
+
+	_ = os.Setenv("TADO_SNAPSHOT_PATH", "/tmp/tado-snapshot.json")
+	_ = os.Setenv("TADO_SNAPSHOT_MAX_AGE", "1h")
+	defer func() {
+		_ = os.Unsetenv("TADO_SNAPSHOT_PATH")
+		_ = os.Unsetenv("TADO_SNAPSHOT_MAX_AGE")
+	}()
+	cfg = LoadWithArgs([]string{})
+	assert.Equal(t, "/tmp/tado-snapshot.json", cfg.SnapshotPath)
+	assert.Equal(t, time.Hour, cfg.SnapshotMaxAge)
+
+	// CLI flag overrides environment variable
+	assert.Equal(t, 5*time.Minute, LoadWithArgs([]string{"-snapshot-max-age=5m"}).SnapshotMaxAge)
+
+	invalid := &Config{Port: 9100, ScrapeTimeout: 10, LogLevel: "info", TokenPassphrase: "test", SnapshotMaxAge: -time.Minute}
+	err := invalid.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "snapshot-max-age")
+}
+
+// TestLoad_ExcludeHomeIDs tests parsing of the home exclude list and its validation
+func TestLoad_ExcludeHomeIDs(t *testing.T) {
+	_ = os.Unsetenv("TADO_EXCLUDE_HOME_IDS")
+	assert.Empty(t, LoadWithArgs([]string{}).ExcludeHomeIDs)
+
+	_ = os.Setenv("TADO_EXCLUDE_HOME_IDS", "123, 456,,789")
+	defer func() { _ = os.Unsetenv("TADO_EXCLUDE_HOME_IDS") }()
+	assert.Equal(t, []string{"123", "456", "789"}, LoadWithArgs([]string{}).ExcludeHomeIDs)
+
+	// CLI flag overrides environment variable
+	assert.Equal(t, []string{"42"}, LoadWithArgs([]string{"-exclude-home-ids=42"}).ExcludeHomeIDs)
+
+	// Excluding the only included home is a configuration error
+	cfg := &Config{Port: 9100, ScrapeTimeout: 10, LogLevel: "info", TokenPassphrase: "test", HomeID: "42", ExcludeHomeIDs: []string{"42"}}
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "exclude-home-ids")
+}
+
+// TestLoad_ServerURL tests the Tado server URL default, env var, flag and validation
+func TestLoad_ServerURL(t *testing.T) {
+	_ = os.Unsetenv("TADO_SERVER_URL")
+	assert.Equal(t, "https://my.tado.com/api/v2", LoadWithArgs([]string{}).ServerURL)
+
+	_ = os.Setenv("TADO_SERVER_URL", "http://localhost:8080/api/v2")
+	defer func() { _ = os.Unsetenv("TADO_SERVER_URL") }()
+	assert.Equal(t, "http://localhost:8080/api/v2", LoadWithArgs([]string{}).ServerURL)
+
+	// CLI flag overrides environment variable
+	assert.Equal(t, "https://staging.example.com", LoadWithArgs([]string{"-server-url=https://staging.example.com"}).ServerURL)
+
+	for _, invalid := range []string{"localhost:8080", "ftp://example.com", "/api/v2"} {
+		cfg := &Config{Port: 9100, ScrapeTimeout: 10, LogLevel: "info", TokenPassphrase: "test", ServerURL: invalid}
+		err := cfg.Validate()
+		assert.Error(t, err, invalid)
+		assert.Contains(t, err.Error(), "server-url")
+	}
+}
+
+// TestLoad_HealthCheck tests the health check mode flag and the /ready max age option
+func TestLoad_HealthCheck(t *testing.T) {
+	_ = os.Unsetenv("TADO_READY_MAX_AGE")
+	cfg := LoadWithArgs([]string{})
+	assert.False(t, cfg.HealthCheck)
+	assert.Equal(t, 5*time.Minute, cfg.ReadyMaxAge)
+
+	assert.True(t, LoadWithArgs([]string{"-health-check"}).HealthCheck)
+
+	_ = os.Setenv("TADO_READY_MAX_AGE", "10m")
+	defer func() { _ = os.Unsetenv("TADO_READY_MAX_AGE") }()
+	assert.Equal(t, 10*time.Minute, LoadWithArgs([]string{}).ReadyMaxAge)
+
+	// CLI flag overrides environment variable
+	assert.Equal(t, time.Minute, LoadWithArgs([]string{"-ready-max-age=1m"}).ReadyMaxAge)
+
+	invalid := &Config{Port: 9100, ScrapeTimeout: 10, LogLevel: "info", TokenPassphrase: "test", ReadyMaxAge: -time.Minute}
+	err := invalid.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "ready-max-age")
+}
+
+// TestLoad_AuthURLFile tests the verification URL file option
+func TestLoad_AuthURLFile(t *testing.T) {
+	_ = os.Unsetenv("TADO_AUTH_URL_FILE")
+	assert.Equal(t, "", LoadWithArgs([]string{}).AuthURLFile)
+
+	_ = os.Setenv("TADO_AUTH_URL_FILE", "/data/auth-url")
+	defer func() { _ = os.Unsetenv("TADO_AUTH_URL_FILE") }()
+	assert.Equal(t, "/data/auth-url", LoadWithArgs([]string{}).AuthURLFile)
+
+	// CLI flag overrides environment variable
+	assert.Equal(t, "/tmp/auth-url", LoadWithArgs([]string{"-auth-url-file=/tmp/auth-url"}).AuthURLFile)
+}
+
+// TestLoad_MetricCompat tests the metric naming scheme option and its validation
+func TestLoad_MetricCompat(t *testing.T) {
+	_ = os.Unsetenv("TADO_METRIC_COMPAT")
+	assert.Equal(t, "v1", LoadWithArgs([]string{}).MetricCompat)
+
+	_ = os.Setenv("TADO_METRIC_COMPAT", "v2")
+	defer func() { _ = os.Unsetenv("TADO_METRIC_COMPAT") }()
+	assert.Equal(t, "v2", LoadWithArgs([]string{}).MetricCompat)
+
+	// CLI flag overrides environment variable
+	assert.Equal(t, "v1", LoadWithArgs([]string{"-metric-compat=v1"}).MetricCompat)
+
+	invalid := &Config{Port: 9100, ScrapeTimeout: 10, LogLevel: "info", TokenPassphrase: "test", MetricCompat: "v3"}
+	err := invalid.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "metric-compat")
+}
+
+// TestLoad_CircuitBreaker tests the circuit breaker options and their validation
+func TestLoad_CircuitBreaker(t *testing.T) {
+	_ = os.Unsetenv("TADO_CIRCUIT_BREAKER_MAX_FAILURES")
+	_ = os.Unsetenv("TADO_CIRCUIT_BREAKER_OPEN_TIMEOUT")
+	cfg := LoadWithArgs([]string{})
+	assert.Equal(t, 0, cfg.CircuitBreakerMaxFailures, "the circuit breaker is disabled by default")
+	assert.Equal(t, time.Minute, cfg.CircuitBreakerOpenTimeout)
+
+	_ = os.Setenv("TADO_CIRCUIT_BREAKER_MAX_FAILURES", "5")
+	_ = os.Setenv("TADO_CIRCUIT_BREAKER_OPEN_TIMEOUT", "30s")
+	defer func() {
+		_ = os.Unsetenv("TADO_CIRCUIT_BREAKER_MAX_FAILURES")
+		_ = os.Unsetenv("TADO_CIRCUIT_BREAKER_OPEN_TIMEOUT")
+	}()
+	cfg = LoadWithArgs([]string{})
+	assert.Equal(t, 5, cfg.CircuitBreakerMaxFailures)
+	assert.Equal(t, 30*time.Second, cfg.CircuitBreakerOpenTimeout)
+
+	// CLI flag overrides environment variable
+	assert.Equal(t, 3, LoadWithArgs([]string{"-circuit-breaker-max-failures=3"}).CircuitBreakerMaxFailures)
+
+	for _, invalid := range []*Config{
+		{Port: 9100, ScrapeTimeout: 10, LogLevel: "info", TokenPassphrase: "test", CircuitBreakerMaxFailures: -1},
+		{Port: 9100, ScrapeTimeout: 10, LogLevel: "info", TokenPassphrase: "test", CircuitBreakerMaxFailures: 5},
+	} {
+		err := invalid.Validate()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "circuit-breaker")
+	}
+}
+
+// TestLoad_SubsystemLogLevels tests the per-subsystem log level overrides and their validation
+func TestLoad_SubsystemLogLevels(t *testing.T) {
+	_ = os.Unsetenv("TADO_LOG_LEVEL_COLLECTOR")
+	_ = os.Unsetenv("TADO_LOG_LEVEL_AUTH")
+	cfg := LoadWithArgs([]string{})
+	assert.Equal(t, "", cfg.LogLevelCollector, "subsystems inherit log-level by default")
+	assert.Equal(t, "", cfg.LogLevelAuth)
+
+	_ = os.Setenv("TADO_LOG_LEVEL_COLLECTOR", "debug")
+	_ = os.Setenv("TADO_LOG_LEVEL_AUTH", "warn")
+	defer func() {
+		_ = os.Unsetenv("TADO_LOG_LEVEL_COLLECTOR")
+		_ = os.Unsetenv("TADO_LOG_LEVEL_AUTH")
+	}()
+	cfg = LoadWithArgs([]string{})
+	assert.Equal(t, "debug", cfg.LogLevelCollector)
+	assert.Equal(t, "warn", cfg.LogLevelAuth)
+
+	// CLI flag overrides environment variable
+	assert.Equal(t, "error", LoadWithArgs([]string{"-log-level-server=error"}).LogLevelServer)
+
+	invalid := &Config{Port: 9100, ScrapeTimeout: 10, LogLevel: "info", TokenPassphrase: "test", LogLevelAuth: "verbose"}
+	err := invalid.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "log-level-auth")
+}
+
+// TestLoad_Accounts tests parsing of the accounts list and its validation
+func TestLoad_Accounts(t *testing.T) {
+	_ = os.Unsetenv("TADO_ACCOUNTS")
+	assert.Empty(t, LoadWithArgs([]string{}).Accounts)
+
+	_ = os.Setenv("TADO_ACCOUNTS", "main=/data/main.json:secret1, cabin=/data/cabin.json")
+	defer func() { _ = os.Unsetenv("TADO_ACCOUNTS") }()
+	cfg := LoadWithArgs([]string{"-token-passphrase=shared"})
+	assert.Equal(t, []Account{
+		{Name: "main", TokenPath: "/data/main.json", TokenPassphrase: "secret1"},
+		{Name: "cabin", TokenPath: "/data/cabin.json", TokenPassphrase: "shared"},
+	}, cfg.Accounts)
+
+	// Every account has its own passphrase, so no top-level passphrase is needed
+	valid := &Config{Port: 9100, ScrapeTimeout: 10, LogLevel: "info", Accounts: []Account{
+		{Name: "main", TokenPath: "/data/main.json", TokenPassphrase: "secret1"},
+		{Name: "cabin", TokenPath: "/data/cabin.json", TokenPassphrase: "secret2"},
+	}}
+	assert.NoError(t, valid.Validate())
+
+	tests := []struct {
+		name     string
+		accounts []Account
+		modify   func(*Config)
+		errMsg   string
+	}{
+		{name: "missing token path", accounts: []Account{{Name: "main", TokenPassphrase: "secret"}}, errMsg: "name and token path are required"},
+		{name: "duplicate name", accounts: []Account{{Name: "main", TokenPath: "/a", TokenPassphrase: "secret"}, {Name: "main", TokenPath: "/b", TokenPassphrase: "secret"}}, errMsg: "more than once"},
+		{name: "missing passphrase", accounts: []Account{{Name: "main", TokenPath: "/a"}}, errMsg: "no passphrase"},
+		{name: "per-home metrics", accounts: []Account{{Name: "main", TokenPath: "/a", TokenPassphrase: "secret"}}, modify: func(c *Config) { c.PerHomeMetrics = true }, errMsg: "accounts and per-home-metrics"},
+		{name: "snapshot", accounts: []Account{{Name: "main", TokenPath: "/a", TokenPassphrase: "secret"}}, modify: func(c *Config) { c.SnapshotPath = "/data/snapshot.json" }, errMsg: "accounts and snapshot-path"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invalid := &Config{Port: 9100, ScrapeTimeout: 10, LogLevel: "info", Accounts: tt.accounts}
+			if tt.modify != nil {
+				tt.modify(invalid)
+			}
+			err := invalid.Validate()
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+// TestLoad_ExposeAccountEmail tests the expose account email flag and environment variable
+func TestLoad_ExposeAccountEmail(t *testing.T) {
+	_ = os.Unsetenv("TADO_EXPOSE_ACCOUNT_EMAIL")
+	assert.False(t, LoadWithArgs([]string{}).ExposeEmail)
+
+	_ = os.Setenv("TADO_EXPOSE_ACCOUNT_EMAIL", "true")
+	defer func() { _ = os.Unsetenv("TADO_EXPOSE_ACCOUNT_EMAIL") }()
+	assert.True(t, LoadWithArgs([]string{}).ExposeEmail)
+
+	// CLI flag overrides environment variable
+	assert.False(t, LoadWithArgs([]string{"-expose-account-email=false"}).ExposeEmail)
+}
+
+// TestLoad_MaxRequestsPerMinute tests the rate limit option and its validation
+func TestLoad_MaxRequestsPerMinute(t *testing.T) {
+	_ = os.Unsetenv("TADO_MAX_REQUESTS_PER_MINUTE")
+	assert.Equal(t, 0, LoadWithArgs([]string{}).MaxRequestsPerMinute, "rate limiting is disabled by default")
+
+	_ = os.Setenv("TADO_MAX_REQUESTS_PER_MINUTE", "30")
+	defer func() { _ = os.Unsetenv("TADO_MAX_REQUESTS_PER_MINUTE") }()
+	assert.Equal(t, 30, LoadWithArgs([]string{}).MaxRequestsPerMinute)
+
+	// CLI flag overrides environment variable
+	assert.Equal(t, 10, LoadWithArgs([]string{"-max-requests-per-minute=10"}).MaxRequestsPerMinute)
+
+	invalid := &Config{Port: 9100, ScrapeTimeout: 10, LogLevel: "info", TokenPassphrase: "test", MaxRequestsPerMinute: -1}
+	err := invalid.Validate()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "max-requests-per-minute")
+	}
+}
+
+func TestLoad_ConstantLabels(t *testing.T) {
+	_ = os.Unsetenv("TADO_CONSTANT_LABELS")
+	assert.Nil(t, LoadWithArgs([]string{}).ConstantLabels, "no constant labels by default")
+
+	_ = os.Setenv("TADO_CONSTANT_LABELS", "site=london, env=prod")
+	defer func() { _ = os.Unsetenv("TADO_CONSTANT_LABELS") }()
+	assert.Equal(t, map[string]string{"site": "london", "env": "prod"}, LoadWithArgs([]string{}).ConstantLabels)
+
+	// CLI flag overrides environment variable
+	assert.Equal(t, map[string]string{"site": "paris"}, LoadWithArgs([]string{"-constant-labels=site=paris"}).ConstantLabels)
+
+	for _, labels := range []map[string]string{
+		{"1site": "london"},
+		{"site-name": "london"},
+		{"__site": "london"},
+		{"site": ""},
+	} {
+		invalid := &Config{Port: 9100, ScrapeTimeout: 10, LogLevel: "info", TokenPassphrase: "test", ConstantLabels: labels}
+		err := invalid.Validate()
+		if assert.Error(t, err, "labels %v", labels) {
+			assert.Contains(t, err.Error(), "constant-labels")
+		}
+	}
+}
+
+// TestConfig_LogFields tests that the effective config logs each setting as its own field, without secrets
+func TestConfig_LogFields(t *testing.T) {
+	cfg := LoadWithArgs([]string{"-port=9200", "-log-level=debug", "-token-passphrase=super-secret", "-admin-token=admin-secret"})
+
+	var output bytes.Buffer
+	log, err := logger.NewWithWriter("info", "json", &output)
+	if !assert.NoError(t, err) {
+		return
+	}
+	log.Info("tado-prometheus-exporter starting", cfg.LogFields()...)
+
+	var entry map[string]interface{}
+	if !assert.NoError(t, json.Unmarshal(output.Bytes(), &entry)) {
+		return
+	}
+	assert.Equal(t, float64(9200), entry["port"])
+	assert.Equal(t, "debug", entry["log_level"])
+	assert.Equal(t, true, entry["token_passphrase_set"])
+	assert.Equal(t, true, entry["admin_endpoints"])
+	assert.NotContains(t, output.String(), "super-secret")
+	assert.NotContains(t, output.String(), "admin-secret")
+}
+
+// TestValidate_MaxScrapeTimeout tests that the X-Scrape-Timeout cap is disabled or at least the scrape timeout
+func TestValidate_MaxScrapeTimeout(t *testing.T) {
+	tests := []struct {
+		name             string
+		maxScrapeTimeout int
+		valid            bool
+	}{
+		{"disabled", 0, true},
+		{"equal to scrape timeout", 10, true},
+		{"above scrape timeout", 60, true},
+		{"below scrape timeout", 5, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				TokenPath:        "/tmp/token.json",
+				TokenPassphrase:  "test",
+				Port:             9100,
+				ScrapeTimeout:    10,
+				MaxScrapeTimeout: tt.maxScrapeTimeout,
+				LogLevel:         "info",
+			}
+
+			err := cfg.Validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), "invalid max-scrape-timeout")
+			}
+		})
+	}
+
+	_ = os.Setenv("TADO_MAX_SCRAPE_TIMEOUT", "60")
+	defer func() { _ = os.Unsetenv("TADO_MAX_SCRAPE_TIMEOUT") }()
+	assert.Equal(t, 60, LoadWithArgs([]string{}).MaxScrapeTimeout)
+}
+
+// TestValidate_ExclusiveOptions tests the options that can't be combined, alone and together
+func TestValidate_ExclusiveOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		errMsg string // Empty if the combination is valid
+	}{
+		{name: "accounts alone", args: []string{"-accounts=main=/tmp/main.json"}},
+		{name: "per-home metrics alone", args: []string{"-per-home-metrics"}},
+		{name: "snapshot alone", args: []string{"-snapshot-path=/data/snapshot.json"}},
+		{name: "per-home metrics with snapshot", args: []string{"-per-home-metrics", "-snapshot-path=/data/snapshot.json"}},
+		{name: "accounts with per-home metrics", args: []string{"-accounts=main=/tmp/main.json", "-per-home-metrics"}, errMsg: "accounts and per-home-metrics cannot be used together"},
+		{name: "accounts with snapshot", args: []string{"-accounts=main=/tmp/main.json", "-snapshot-path=/data/snapshot.json"}, errMsg: "accounts and snapshot-path cannot be used together"},
+		{name: "home ID excluding other homes", args: []string{"-home-id=1", "-exclude-home-ids=2,3"}},
+		{name: "home ID also excluded", args: []string{"-home-id=1", "-exclude-home-ids=2,1"}, errMsg: "home-id 1 is also listed in exclude-home-ids"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := LoadWithArgs(append([]string{"-token-passphrase=test"}, tt.args...))
+
+			err := cfg.Validate()
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
+
+// TestLoad_TemperatureUnits tests the temperature units flag, its default and validation
+func TestLoad_TemperatureUnits(t *testing.T) {
+	_ = os.Unsetenv("TADO_TEMPERATURE_UNITS")
+	assert.Equal(t, []string{"celsius", "fahrenheit"}, LoadWithArgs([]string{}).TemperatureUnits)
+
+	_ = os.Setenv("TADO_TEMPERATURE_UNITS", "celsius, kelvin")
+	defer func() { _ = os.Unsetenv("TADO_TEMPERATURE_UNITS") }()
+	assert.Equal(t, []string{"celsius", "kelvin"}, LoadWithArgs([]string{}).TemperatureUnits)
+
+	cfg := LoadWithArgs([]string{"-token-passphrase=test", "-temperature-units=celsius,rankine"})
+	if err := cfg.Validate(); assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid temperature-units")
+	}
+}
+
+// TestLoad_OTLPExport tests the OTLP endpoint and interval options and their validation
+func TestLoad_OTLPExport(t *testing.T) {
+	_ = os.Unsetenv("TADO_OTLP_ENDPOINT")
+	_ = os.Unsetenv("TADO_OTLP_INTERVAL")
+	cfg := LoadWithArgs([]string{})
+	assert.Empty(t, cfg.OTLPEndpoint)
+	assert.Equal(t, 60*time.Second, cfg.OTLPInterval)
+
+	_ = os.Setenv("TADO_OTLP_ENDPOINT", "http://otel-collector:4318")
+	defer func() { _ = os.Unsetenv("TADO_OTLP_ENDPOINT") }()
+	cfg = LoadWithArgs([]string{"-token-passphrase=test", "-otlp-interval=15s"})
+	assert.Equal(t, "http://otel-collector:4318", cfg.OTLPEndpoint)
+	assert.Equal(t, 15*time.Second, cfg.OTLPInterval)
+	assert.NoError(t, cfg.Validate())
+
+	cfg = LoadWithArgs([]string{"-token-passphrase=test", "-otlp-endpoint=otel-collector:4318"})
+	if err := cfg.Validate(); assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid otlp-endpoint")
+	}
+
+	cfg = LoadWithArgs([]string{"-token-passphrase=test", "-otlp-interval=0s"})
+	if err := cfg.Validate(); assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid otlp-interval")
+	}
+}
+
+// TestLoad_ZoneDeviceCalls tests the temperature offset and per-zone device call options
+func TestLoad_ZoneDeviceCalls(t *testing.T) {
+	_ = os.Unsetenv("TADO_COLLECT_TEMPERATURE_OFFSETS")
+	_ = os.Unsetenv("TADO_ZONE_DEVICE_WORKERS")
+	_ = os.Unsetenv("TADO_ZONE_DEVICE_TIMEOUT")
+	cfg := LoadWithArgs([]string{})
+	assert.False(t, cfg.TemperatureOffsets)
+	assert.Equal(t, 4, cfg.ZoneDeviceWorkers)
+	assert.Equal(t, 2*time.Second, cfg.ZoneDeviceTimeout)
+
+	_ = os.Setenv("TADO_COLLECT_TEMPERATURE_OFFSETS", "true")
+	defer func() { _ = os.Unsetenv("TADO_COLLECT_TEMPERATURE_OFFSETS") }()
+	cfg = LoadWithArgs([]string{"-token-passphrase=test", "-zone-device-workers=8", "-zone-device-timeout=500ms"})
+	assert.True(t, cfg.TemperatureOffsets)
+	assert.Equal(t, 8, cfg.ZoneDeviceWorkers)
+	assert.Equal(t, 500*time.Millisecond, cfg.ZoneDeviceTimeout)
+	assert.NoError(t, cfg.Validate())
+
+	cfg = LoadWithArgs([]string{"-token-passphrase=test", "-zone-device-workers=0"})
+	if err := cfg.Validate(); assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid zone-device-workers")
+	}
+
+	cfg = LoadWithArgs([]string{"-token-passphrase=test", "-zone-device-timeout=0s"})
+	if err := cfg.Validate(); assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid zone-device-timeout")
+	}
+}
+
+// TestLoad_Retries tests the retry options and their validation
+func TestLoad_Retries(t *testing.T) {
+	_ = os.Unsetenv("TADO_MAX_RETRIES")
+	_ = os.Unsetenv("TADO_RETRY_BACKOFF")
+	cfg := LoadWithArgs([]string{})
+	assert.Equal(t, 0, cfg.MaxRetries)
+	assert.Equal(t, 500*time.Millisecond, cfg.RetryBackoff)
+
+	_ = os.Setenv("TADO_MAX_RETRIES", "2")
+	defer func() { _ = os.Unsetenv("TADO_MAX_RETRIES") }()
+	cfg = LoadWithArgs([]string{"-token-passphrase=test", "-retry-backoff=1s"})
+	assert.Equal(t, 2, cfg.MaxRetries)
+	assert.Equal(t, time.Second, cfg.RetryBackoff)
+	assert.NoError(t, cfg.Validate())
+
+	cfg = LoadWithArgs([]string{"-token-passphrase=test", "-max-retries=-1"})
+	if err := cfg.Validate(); assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid max-retries")
+	}
+
+	cfg = LoadWithArgs([]string{"-token-passphrase=test", "-retry-backoff=0s"})
+	if err := cfg.Validate(); assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid retry-backoff")
+	}
+}
+
+// TestLoad_TemperaturePrecision tests the temperature precision option and its validation
+func TestLoad_TemperaturePrecision(t *testing.T) {
+	_ = os.Unsetenv("TADO_TEMPERATURE_PRECISION")
+	cfg := LoadWithArgs([]string{})
+	assert.Equal(t, -1, cfg.TemperaturePrecision, "rounding is off by default")
+
+	_ = os.Setenv("TADO_TEMPERATURE_PRECISION", "1")
+	defer func() { _ = os.Unsetenv("TADO_TEMPERATURE_PRECISION") }()
+	cfg = LoadWithArgs([]string{"-token-passphrase=test"})
+	assert.Equal(t, 1, cfg.TemperaturePrecision)
+	assert.NoError(t, cfg.Validate())
+
+	for _, arg := range []string{"-temperature-precision=-2", "-temperature-precision=7"} {
+		cfg = LoadWithArgs([]string{"-token-passphrase=test", arg})
+		if err := cfg.Validate(); assert.Error(t, err, arg) {
+			assert.Contains(t, err.Error(), "invalid temperature-precision")
+		}
+	}
+}
+
+// TestLoad_DeviceMetrics tests the device metrics level option and its validation
+func TestLoad_DeviceMetrics(t *testing.T) {
+	_ = os.Unsetenv("TADO_DEVICE_METRICS")
+	cfg := LoadWithArgs([]string{})
+	assert.Equal(t, "off", cfg.DeviceMetrics, "device metrics are off by default")
+
+	_ = os.Setenv("TADO_DEVICE_METRICS", "basic")
+	defer func() { _ = os.Unsetenv("TADO_DEVICE_METRICS") }()
+	cfg = LoadWithArgs([]string{"-token-passphrase=test"})
+	assert.Equal(t, "basic", cfg.DeviceMetrics)
+	assert.NoError(t, cfg.Validate())
+
+	cfg = LoadWithArgs([]string{"-token-passphrase=test", "-device-metrics=verbose"})
+	if err := cfg.Validate(); assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid device-metrics")
+	}
+}
+
+// TestLoad_HomeFailureSkip tests the failing home options and their validation
+func TestLoad_HomeFailureSkip(t *testing.T) {
+	_ = os.Unsetenv("TADO_HOME_FAILURE_THRESHOLD")
+	_ = os.Unsetenv("TADO_HOME_FAILURE_COOLDOWN")
+	cfg := LoadWithArgs([]string{})
+	assert.Equal(t, 0, cfg.HomeFailureThreshold)
+	assert.Equal(t, 15*time.Minute, cfg.HomeFailureCooldown)
+
+	_ = os.Setenv("TADO_HOME_FAILURE_THRESHOLD", "5")
+	defer func() { _ = os.Unsetenv("TADO_HOME_FAILURE_THRESHOLD") }()
+	cfg = LoadWithArgs([]string{"-token-passphrase=test", "-home-failure-cooldown=1h"})
+	assert.Equal(t, 5, cfg.HomeFailureThreshold)
+	assert.Equal(t, time.Hour, cfg.HomeFailureCooldown)
+	assert.NoError(t, cfg.Validate())
+
+	cfg = LoadWithArgs([]string{"-token-passphrase=test", "-home-failure-threshold=-1"})
+	if err := cfg.Validate(); assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid home-failure-threshold")
+	}
+
+	cfg = LoadWithArgs([]string{"-token-passphrase=test", "-home-failure-cooldown=0s"})
+	if err := cfg.Validate(); assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid home-failure-cooldown")
+	}
+}
@@ -0,0 +1,69 @@
+// Package logger provides request ID propagation through contexts.
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// requestIDKey is the context key under which the request ID is stored
+type requestIDKey struct{}
+
+// NewRequestID returns a short random identifier used to correlate the log lines of one scrape
+func NewRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ContextWithRequestID returns a copy of ctx carrying requestID
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx, or "" if there is none
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// DebugContext logs a debug level message, adding the request ID from ctx when present
+func (l *Logger) DebugContext(ctx context.Context, msg string, fields ...interface{}) {
+	requestID := RequestIDFromContext(ctx)
+	if requestID == "" {
+		l.Debug(msg, fields...)
+		return
+	}
+	l.WithRequestID(requestID).WithFields(toFields(fields)).Debug(msg)
+}
+
+// InfoContext logs an info level message, adding the request ID from ctx when present
+func (l *Logger) InfoContext(ctx context.Context, msg string, fields ...interface{}) {
+	requestID := RequestIDFromContext(ctx)
+	if requestID == "" {
+		l.Info(msg, fields...)
+		return
+	}
+	l.WithRequestID(requestID).WithFields(toFields(fields)).Info(msg)
+}
+
+// WarnContext logs a warning level message, adding the request ID from ctx when present
+func (l *Logger) WarnContext(ctx context.Context, msg string, fields ...interface{}) {
+	requestID := RequestIDFromContext(ctx)
+	if requestID == "" {
+		l.Warn(msg, fields...)
+		return
+	}
+	l.WithRequestID(requestID).WithFields(toFields(fields)).Warn(msg)
+}
+
+// ErrorContext logs an error level message, adding the request ID from ctx when present
+func (l *Logger) ErrorContext(ctx context.Context, msg string, fields ...interface{}) {
+	requestID := RequestIDFromContext(ctx)
+	if requestID == "" {
+		l.Error(msg, fields...)
+		return
+	}
+	l.WithRequestID(requestID).WithFields(toFields(fields)).Error(msg)
+}
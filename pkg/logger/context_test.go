@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRequestIDContext tests storing and retrieving a request ID from a context
+func TestRequestIDContext(t *testing.T) {
+	assert.Equal(t, "", RequestIDFromContext(context.Background()))
+
+	ctx := ContextWithRequestID(context.Background(), "req-12345")
+	assert.Equal(t, "req-12345", RequestIDFromContext(ctx))
+}
+
+// TestNewRequestID tests that request IDs are non-empty and distinct
+func TestNewRequestID(t *testing.T) {
+	first, second := NewRequestID(), NewRequestID()
+
+	assert.Len(t, first, 16)
+	assert.NotEqual(t, first, second)
+}
+
+// TestWarnContext tests that WarnContext adds the request ID from the context
+func TestWarnContext(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log, err := NewWithWriter("warn", "json", buf)
+	require.NoError(t, err)
+
+	ctx := ContextWithRequestID(context.Background(), "req-12345")
+	log.WarnContext(ctx, "test message", "home_id", "123")
+
+	output := buf.String()
+	assert.Contains(t, output, "\"request_id\":\"req-12345\"")
+	assert.Contains(t, output, "\"home_id\":\"123\"")
+	assert.Contains(t, output, "\"msg\":\"test message\"")
+
+	// Without a request ID the message is logged unchanged
+	buf.Reset()
+	log.WarnContext(context.Background(), "test message")
+	assert.NotContains(t, buf.String(), "request_id")
+}
+
+// TestInfoContext tests that info messages carry the request ID from the context
+func TestInfoContext(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log, err := NewWithWriter("info", "json", buf)
+	require.NoError(t, err)
+
+	ctx := ContextWithRequestID(context.Background(), "req-12345")
+	log.InfoContext(ctx, "test message", "key", "value")
+
+	output := buf.String()
+	assert.Contains(t, output, "\"request_id\":\"req-12345\"")
+	assert.Contains(t, output, "\"level\":\"info\"")
+	assert.Contains(t, output, "\"key\":\"value\"")
+}
+
+// TestErrorContext tests that ErrorContext adds the request ID from the context
+func TestErrorContext(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log, err := NewWithWriter("error", "json", buf)
+	require.NoError(t, err)
+
+	ctx := ContextWithRequestID(context.Background(), "req-12345")
+	log.ErrorContext(ctx, "test message")
+
+	output := buf.String()
+	assert.Contains(t, output, "\"request_id\":\"req-12345\"")
+	assert.Contains(t, output, "\"level\":\"error\"")
+}
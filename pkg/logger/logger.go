@@ -2,7 +2,7 @@
 //
 // It wraps logrus to provide:
 //   - Structured logging with JSON and text output
-//   - Configurable log levels (debug, info, warn, error)
+//   - Configurable log levels (debug, info, warn, error), overridable per subsystem
 //   - Convenience methods for adding context fields
 //   - Output routing to files, stdout, or custom writers
 //
@@ -93,6 +93,27 @@ func NewWithWriter(level, format string, out io.Writer) (*Logger, error) {
 	return &Logger{log}, nil
 }
 
+// WithLevel returns a logger sharing l's output and format but logging at level, so a subsystem
+// can be more or less verbose than the rest of the exporter. An empty level returns l unchanged
+func (l *Logger) WithLevel(level string) (*Logger, error) {
+	if level == "" {
+		return l, nil
+	}
+
+	parsedLevel, err := logrus.ParseLevel(level)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log level: %s", level)
+	}
+
+	derived := logrus.New()
+	derived.SetOutput(l.Out)
+	derived.SetFormatter(l.Formatter)
+	derived.ReplaceHooks(l.Hooks)
+	derived.SetLevel(parsedLevel)
+
+	return &Logger{derived}, nil
+}
+
 // WithRequestID returns a logger entry with request ID context
 func (l *Logger) WithRequestID(requestID string) *logrus.Entry {
 	return l.WithField("request_id", requestID)
@@ -296,3 +296,40 @@ func TestErrorLogging(t *testing.T) {
 	assert.Contains(t, output, "Failed to fetch metrics")
 	assert.Contains(t, output, "home_id")
 }
+
+// TestWithLevel_MixedSubsystemLevels tests that a subsystem logger at debug logs debug messages while
+// another subsystem derived from the same base stays quiet
+func TestWithLevel_MixedSubsystemLevels(t *testing.T) {
+	buf := &bytes.Buffer{}
+	base, err := NewWithWriter("info", "json", buf)
+	require.NoError(t, err)
+
+	collectorLog, err := base.WithLevel("debug")
+	require.NoError(t, err)
+	authLog, err := base.WithLevel("warn")
+	require.NoError(t, err)
+
+	collectorLog.Debug("collector debug message")
+	authLog.Debug("auth debug message")
+	authLog.Info("auth info message")
+	base.Debug("base debug message")
+
+	output := buf.String()
+	assert.Contains(t, output, "collector debug message")
+	assert.NotContains(t, output, "auth debug message")
+	assert.NotContains(t, output, "auth info message")
+	assert.NotContains(t, output, "base debug message")
+}
+
+// TestWithLevel_EmptyInheritsBase tests that an unset subsystem level keeps the base logger
+func TestWithLevel_EmptyInheritsBase(t *testing.T) {
+	base, err := NewWithWriter("warn", "text", &bytes.Buffer{})
+	require.NoError(t, err)
+
+	derived, err := base.WithLevel("")
+	require.NoError(t, err)
+	assert.Same(t, base, derived)
+
+	_, err = base.WithLevel("verbose")
+	assert.Error(t, err)
+}
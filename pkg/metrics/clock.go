@@ -0,0 +1,19 @@
+package metrics
+
+import "time"
+
+// Clock tells the current time to the time-dependent metrics, so tests can replace the system clock
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock is the Clock reading the system time, used unless another Clock is injected
+var RealClock Clock = realClock{}
+
+// realClock implements Clock with time.Now
+type realClock struct{}
+
+// Now implements Clock
+func (realClock) Now() time.Time {
+	return time.Now()
+}
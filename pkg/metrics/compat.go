@@ -0,0 +1,52 @@
+package metrics
+
+import "fmt"
+
+// MetricCompat selects the naming scheme of the Tado metrics
+type MetricCompat string
+
+const (
+	// MetricCompatV1 keeps the legacy metric names existing dashboards are built on (default)
+	MetricCompatV1 MetricCompat = "v1"
+
+	// MetricCompatV2 uses names aligned with the Prometheus naming conventions:
+	// no "is_" prefix on boolean gauges and the "_percent" unit suffix for percentages
+	MetricCompatV2 MetricCompat = "v2"
+)
+
+// v2MetricNames maps legacy metric names to their MetricCompatV2 names
+// Metrics whose legacy name already follows the conventions are not listed
+var v2MetricNames = map[string]string{
+	"tado_is_resident_present":          "tado_resident_present",
+	"tado_solar_intensity_percentage":   "tado_solar_intensity_percent",
+	"tado_weather_is_daytime":           "tado_weather_daytime",
+	"tado_home_devices_total":           "tado_home_devices",
+	"tado_home_devices_at_home_total":   "tado_home_devices_at_home",
+	"tado_zone_devices_total":           "tado_zone_devices",
+	"tado_humidity_measured_percentage": "tado_humidity_measured_percent",
+	"tado_heating_power_percentage":     "tado_heating_power_percent",
+	"tado_is_window_open":               "tado_window_open",
+	"tado_is_zone_powered":              "tado_zone_powered",
+}
+
+// ParseMetricCompat parses a metric naming scheme; an empty value selects MetricCompatV1
+func ParseMetricCompat(value string) (MetricCompat, error) {
+	switch MetricCompat(value) {
+	case "", MetricCompatV1:
+		return MetricCompatV1, nil
+	case MetricCompatV2:
+		return MetricCompatV2, nil
+	default:
+		return "", fmt.Errorf("unknown metric compat %q (must be one of: v1, v2)", value)
+	}
+}
+
+// metricName returns the name under which the metric with the given legacy name is exposed
+func (c MetricCompat) metricName(legacyName string) string {
+	if c == MetricCompatV2 {
+		if name, ok := v2MetricNames[legacyName]; ok {
+			return name
+		}
+	}
+	return legacyName
+}
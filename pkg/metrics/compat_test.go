@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gatheredNames returns the names of all metric families gathered from md
+func gatheredNames(t *testing.T, md *MetricDescriptors) map[string]bool {
+	t.Helper()
+
+	registry := prometheus.NewRegistry()
+	require.NoError(t, md.RegisterWith(registry))
+
+	// Vectors are only gathered once they have a series
+	labels := []string{"1", "1", "Living Room", "HEATING"}
+	for _, vec := range md.snapshotGaugeVecs() {
+		vec.WithLabelValues(labels...).Set(1)
+	}
+	for _, vec := range md.snapshotHomeGaugeVecs() {
+		vec.WithLabelValues("1").Set(1)
+	}
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	names := make(map[string]bool, len(families))
+	for _, family := range families {
+		names[family.GetName()] = true
+	}
+	return names
+}
+
+// TestMetricCompatV2Names tests that v2 mode exposes the convention-aligned names instead of the legacy ones
+func TestMetricCompatV2Names(t *testing.T) {
+	md, err := NewMetricDescriptorsUnregisteredWithCompat(MetricCompatV2)
+	require.NoError(t, err)
+	assert.Equal(t, MetricCompatV2, md.Compat())
+
+	names := gatheredNames(t, md)
+	for legacyName, v2Name := range v2MetricNames {
+		assert.True(t, names[v2Name], "v2 name %s should be exposed", v2Name)
+		assert.False(t, names[legacyName], "legacy name %s should not be exposed in v2 mode", legacyName)
+	}
+
+	// Names that already follow the conventions are unchanged
+	assert.True(t, names["tado_temperature_measured_celsius"])
+	assert.True(t, names["tado_zone_data_present"])
+}
+
+// TestMetricCompatV1Names tests that the default naming scheme keeps the legacy names
+func TestMetricCompatV1Names(t *testing.T) {
+	md, err := NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	assert.Equal(t, MetricCompatV1, md.Compat())
+
+	names := gatheredNames(t, md)
+	for legacyName, v2Name := range v2MetricNames {
+		assert.True(t, names[legacyName], "legacy name %s should be exposed", legacyName)
+		assert.False(t, names[v2Name], "v2 name %s should not be exposed by default", v2Name)
+	}
+}
+
+// TestParseMetricCompat tests parsing of the metric naming scheme
+func TestParseMetricCompat(t *testing.T) {
+	for value, expected := range map[string]MetricCompat{"": MetricCompatV1, "v1": MetricCompatV1, "v2": MetricCompatV2} {
+		compat, err := ParseMetricCompat(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, compat, value)
+	}
+
+	_, err := ParseMetricCompat("v3")
+	assert.Error(t, err)
+}
+
+// TestSnapshotRoundTrip_V2 tests that snapshots saved in v2 mode are restored under the v2 names
+func TestSnapshotRoundTrip_V2(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+
+	original, err := NewMetricDescriptorsUnregisteredWithCompat(MetricCompatV2)
+	require.NoError(t, err)
+	original.IsResidentPresent.Set(1)
+	require.NoError(t, original.SaveSnapshot(path))
+
+	restored, err := NewMetricDescriptorsUnregisteredWithCompat(MetricCompatV2)
+	require.NoError(t, err)
+	_, err = restored.LoadSnapshot(path, time.Hour)
+	require.NoError(t, err)
+
+	registry := prometheus.NewRegistry()
+	require.NoError(t, restored.RegisterWith(registry))
+	assert.Equal(t, 1.0, testGaugeValue(t, registry, "tado_resident_present"))
+}
@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DeviceMetricsLevel selects how much per-device detail is collected, as every device adds its own series
+type DeviceMetricsLevel string
+
+const (
+	// DeviceMetricsOff collects no device-level metrics
+	DeviceMetricsOff DeviceMetricsLevel = "off"
+
+	// DeviceMetricsBasic collects tado_device_battery_low and tado_device_connected
+	DeviceMetricsBasic DeviceMetricsLevel = "basic"
+
+	// DeviceMetricsFull collects every device-level metric, adding tado_device_firmware_info
+	DeviceMetricsFull DeviceMetricsLevel = "full"
+)
+
+// DefaultDeviceMetricsLevel is the level used when none is configured
+const DefaultDeviceMetricsLevel = DeviceMetricsOff
+
+// ParseDeviceMetricsLevel parses a device metrics level name; an empty value selects DefaultDeviceMetricsLevel
+func ParseDeviceMetricsLevel(value string) (DeviceMetricsLevel, error) {
+	switch level := DeviceMetricsLevel(strings.ToLower(value)); level {
+	case "":
+		return DefaultDeviceMetricsLevel, nil
+	case DeviceMetricsOff, DeviceMetricsBasic, DeviceMetricsFull:
+		return level, nil
+	default:
+		return "", fmt.Errorf("unknown device metrics level %q (must be one of: off, basic, full)", value)
+	}
+}
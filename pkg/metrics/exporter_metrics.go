@@ -11,12 +11,46 @@
 // 3. SetAuthenticationValid(valid) - on GetMe success (true) or failure (false)
 // 4. IncrementAuthenticationErrors() - when GetMe fails or no homes found
 // 5. RecordAuthenticationSuccess() - when GetMe succeeds with homes
+// 6. RecordScrapeBudgetUsed(duration, timeout) - in Collect() after metrics fetch
+// 7. RecordZonesObserved(count) - in fetchAndCollectMetrics() after iterating homes
+// 8. SetClockSkew(skew) - in fetchAndCollectMetrics() when any sensor timestamp was seen
+// 9. IncrementTokenRefreshes() - by auth.TokenTracker when a new access token is observed
+// 10. TrackConnState(conn, state) - as the HTTP server's ConnState callback in StartServer()
+// 11. RecordHomeCollectionDuration(homeID, duration) - in fetchAndCollectMetrics() after each home is collected
+// 12. SetTokenValidity(remaining) - in Collect() after metrics fetch, when the token expiry is known
+// 13. RecordScrapeRequest(userAgentClass) - by the /metrics handler middleware in StartServer()
+// 14. SetCircuitBreakerOpen(open) - from the circuit breaker's OnStateChange callback in main.go
+// 15. SetTokenFileError(unreadable) - in main.go initializeCollector() after authenticating
+// 16. SetObservedScrapeInterval(interval) - in Collect() from the second call on
+// 17. IncrementResponseParseErrors() - in fetchAndCollectMetrics() when GetMe returns a user without a homes field
+// 18. IncrementCollectorPanics() - in Collect() when a panic during collection is recovered
+// 19. SetScrapeSuccess(success) - in Collect() after metrics fetch
+// 20. SetAccountInfo(accountHash, email) - in fetchAndCollectMetrics() after GetMe succeeds
+// 21. IncrementThrottledRequests() - from the rate limiter's OnThrottle callback in main.go
+// 22. RecordHomeDataFetched(homeID) - in fetchAndCollectMetrics() after a home is collected without error
+// 23. IncrementAPIUnavailable() - from the Tado client adapter's OnUnavailable callback in main.go
+// 24. SetZoneTimeBudget(homeID, budget) - in collectZoneMetrics() after GetZones returns a home's zones
+// 25. SetLastError(reason) / ClearLastError() - at the end of fetchAndCollectMetrics(), depending on whether any error occurred
+// 26. SetHomesTotal(count) - in fetchAndCollectMetrics() after the account's homes are listed
+// 27. SetTrackedSeries(count) - in collectZoneMetrics() after stale zones are evicted
+// 28. SetOldestZoneReading(t) - in fetchAndCollectMetrics() when any sensor timestamp was seen
+// 29. SetZonesHeating(count) - in fetchAndCollectMetrics() after iterating homes
+// 30. IncrementRetries(endpoint) - from the retrying wrapper's OnRetry callback in main.go
+// 31. SetHomeSkipped(homeID, skipped) - in fetchAndCollectMetrics() when a home is skipped or collected
+// 32. SetFeatureEnabled(feature, enabled) - in main.go at startup, for each of the configuration's features
+//
+// Build info and metrics 10, 13, 15 and 32 describe the whole process; the others describe the collection
+// of one Tado account, so with several accounts each account records into its own ExporterMetrics
 //
 // If adding new metrics, ensure they're called in the appropriate places
 // in collector.go and covered by tests.
 package metrics
 
 import (
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -41,86 +75,498 @@ type ExporterMetrics struct {
 
 	// Last successful authentication timestamp (unix seconds)
 	LastAuthenticationSuccessUnix prometheus.Gauge
+
+	// Fraction of the scrape timeout consumed by the last scrape
+	ScrapeBudgetUsedRatio prometheus.Gauge
+
+	// Number of zones observed across all homes per scrape
+	ZonesObserved prometheus.Histogram
+
+	// Newest Tado sensor timestamp minus the local clock (seconds)
+	ClockSkewSeconds prometheus.Gauge
+
+	// OAuth2 access token refresh counter
+	TokenRefreshesTotal prometheus.Counter
+
+	// Open HTTP server connections gauge
+	HTTPConnectionsActive prometheus.Gauge
+
+	// Per-home collection duration histogram (in seconds, labelled by home_id)
+	HomeCollectionDurationSeconds *prometheus.HistogramVec
+
+	// Seconds until the current access token expires (negative once expired)
+	TokenValidSeconds prometheus.Gauge
+
+	// Metrics endpoint request counter (labelled by user_agent_class)
+	ScrapeRequestsTotal *prometheus.CounterVec
+
+	// Token file status gauge (1 = token file could not be decrypted or parsed)
+	TokenFileError prometheus.Gauge
+
+	// Time between the two most recent scrapes (seconds)
+	ObservedScrapeIntervalSeconds prometheus.Gauge
+
+	// Malformed Tado API response counter
+	ResponseParseErrorsTotal prometheus.Counter
+
+	// Recovered collection panic counter
+	CollectorPanicsTotal prometheus.Counter
+
+	// Last scrape status gauge (1 = collected without error, 0 = failed)
+	ScrapeSuccess prometheus.Gauge
+
+	// Authenticated Tado account info gauge (labelled by account_hash and, when opted in, email)
+	AccountInfo *prometheus.GaugeVec
+
+	// Tado API calls delayed or rejected by the rate limiter
+	ThrottledRequestsTotal prometheus.Counter
+
+	// Counter of 503 Service Unavailable responses from the Tado API, e.g. during maintenance
+	APIUnavailableTotal prometheus.Counter
+
+	// Remaining scrape time per zone when each home's zones were listed (in seconds)
+	ZoneTimeBudgetSeconds *prometheus.GaugeVec
+
+	// Category of the most recent scrape's last error (value is always 1); no series after an error-free scrape
+	LastErrorInfo *prometheus.GaugeVec
+
+	// Number of homes collected by the most recent scrape, after the home ID filter and exclude list
+	HomesTotal prometheus.Gauge
+
+	// Number of (home_id, zone_id) combinations currently exported by the zone metrics
+	TrackedSeriesTotal prometheus.Gauge
+
+	// Unix time of the oldest zone sensor reading seen by the most recent scrape, across all zones
+	OldestZoneReadingUnix prometheus.Gauge
+
+	// Number of zones with heating power above 0% in the most recent scrape, across all homes
+	ZonesHeatingTotal prometheus.Gauge
+
+	// Tado API calls retried after a transient error, by endpoint
+	RetriesTotal *prometheus.CounterVec
+
+	// Whether each home is being skipped after failing too many scrapes in a row (1 = skipped)
+	HomeSkipped *prometheus.GaugeVec
+
+	// Whether each optional feature is enabled by the configuration (1 = enabled), set at startup
+	FeatureEnabled *prometheus.GaugeVec
+
+	// Seconds since the circuit breaker opened (0 while closed), computed at scrape time
+	CircuitBreakerOpenSeconds prometheus.GaugeFunc
+
+	// Seconds since each home's data was last fetched successfully, computed at scrape time
+	DataAgeSeconds prometheus.Collector
+
+	circuitMu       sync.Mutex
+	circuitOpenedAt time.Time // Zero while the circuit breaker is closed
+
+	dataMu        sync.Mutex
+	dataFetchedAt map[string]time.Time // Last successful fetch per home ID
+
+	clock Clock // Tells the time for the time-dependent metrics; RealClock unless replaced by WithClock
 }
 
 // NewExporterMetrics creates and registers exporter health metrics
 func NewExporterMetrics() (*ExporterMetrics, error) {
+	em := NewExporterMetricsUnregistered()
+
+	// Register metrics
+	if err := em.Register(); err != nil {
+		return nil, err
+	}
+
+	return em, nil
+}
+
+// NewExporterMetricsUnregistered creates exporter health metrics without registering them
+// This is useful for testing where each test needs isolated registries
+func NewExporterMetricsUnregistered() *ExporterMetrics {
+	return NewExporterMetricsUnregisteredWithLabels(nil)
+}
+
+// NewExporterMetricsUnregisteredWithLabels creates exporter health metrics, adding constLabels
+// to every metric, without registering them
+func NewExporterMetricsUnregisteredWithLabels(constLabels prometheus.Labels) *ExporterMetrics {
 	em := &ExporterMetrics{
 		// Scrape duration histogram with buckets: 100ms, 500ms, 1s, 2s, 5s, 10s
 		ScrapeDurationSeconds: prometheus.NewHistogram(prometheus.HistogramOpts{
-			Name:    "tado_exporter_scrape_duration_seconds",
-			Help:    "Time taken to collect metrics from Tado API in seconds",
-			Buckets: prometheus.ExponentialBuckets(0.1, 2, 6), // 0.1, 0.2, 0.4, 0.8, 1.6, 3.2
+			Name:        "tado_exporter_scrape_duration_seconds",
+			ConstLabels: constLabels,
+			Help:        "Time taken to collect metrics from Tado API in seconds",
+			Buckets:     prometheus.ExponentialBuckets(0.1, 2, 6), // 0.1, 0.2, 0.4, 0.8, 1.6, 3.2
 		}),
 
 		// Scrape error counter
 		ScrapeErrorsTotal: prometheus.NewCounter(prometheus.CounterOpts{
-			Name: "tado_exporter_scrape_errors_total",
-			Help: "Total number of errors while collecting metrics from Tado API",
+			Name:        "tado_exporter_scrape_errors_total",
+			ConstLabels: constLabels,
+			Help:        "Total number of errors while collecting metrics from Tado API",
 		}),
 
 		// Build info gauge
 		BuildInfo: prometheus.NewGauge(prometheus.GaugeOpts{
-			Name: "tado_exporter_build_info",
-			Help: "Build information for the exporter (value is always 1)",
+			Name:        "tado_exporter_build_info",
+			ConstLabels: constLabels,
+			Help:        "Build information for the exporter (value is always 1)",
 		}),
 
 		// Authentication status gauge (1 = valid, 0 = invalid/expired)
 		AuthenticationValid: prometheus.NewGauge(prometheus.GaugeOpts{
-			Name: "tado_exporter_authentication_valid",
-			Help: "Set to 1 if Tado authentication is valid and metrics are being collected, 0 if authentication failed or no homes found",
+			Name:        "tado_exporter_authentication_valid",
+			ConstLabels: constLabels,
+			Help:        "Set to 1 if Tado authentication is valid and metrics are being collected, 0 if authentication failed or no homes found",
 		}),
 
 		// Authentication error counter
 		AuthenticationErrorsTotal: prometheus.NewCounter(prometheus.CounterOpts{
-			Name: "tado_exporter_authentication_errors_total",
-			Help: "Total number of authentication failures or token refresh attempts",
+			Name:        "tado_exporter_authentication_errors_total",
+			ConstLabels: constLabels,
+			Help:        "Total number of authentication failures or token refresh attempts",
 		}),
 
 		// Last successful authentication timestamp
 		LastAuthenticationSuccessUnix: prometheus.NewGauge(prometheus.GaugeOpts{
-			Name: "tado_exporter_last_authentication_success_unix",
-			Help: "Unix timestamp of the last successful authentication",
+			Name:        "tado_exporter_last_authentication_success_unix",
+			ConstLabels: constLabels,
+			Help:        "Unix timestamp of the last successful authentication",
+		}),
+
+		// Scrape budget usage (last scrape duration / scrape timeout)
+		ScrapeBudgetUsedRatio: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "tado_exporter_scrape_budget_used_ratio",
+			ConstLabels: constLabels,
+			Help:        "Ratio of the last scrape duration to the configured scrape timeout (values near 1 indicate scrapes are close to timing out)",
+		}),
+
+		// Zones observed per scrape histogram with buckets: 1, 2, 4, 8, 16, 32, 64
+		ZonesObserved: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:        "tado_exporter_zones_observed",
+			ConstLabels: constLabels,
+			Help:        "Total number of zones observed across all homes per scrape",
+			Buckets:     prometheus.ExponentialBuckets(1, 2, 7), // 1, 2, 4, 8, 16, 32, 64
+		}),
+
+		// Clock skew between Tado sensor timestamps and the local clock
+		ClockSkewSeconds: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "tado_exporter_clock_skew_seconds",
+			ConstLabels: constLabels,
+			Help:        "Newest Tado sensor reading timestamp minus the exporter's clock in seconds (positive = Tado ahead; readings are normally a few minutes old)",
+		}),
+
+		// Access token refresh counter
+		TokenRefreshesTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        "tado_exporter_token_refreshes_total",
+			ConstLabels: constLabels,
+			Help:        "Total number of times a new Tado OAuth2 access token was observed",
+		}),
+
+		// Open HTTP connections to the exporter
+		HTTPConnectionsActive: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "tado_exporter_http_connections_active",
+			ConstLabels: constLabels,
+			Help:        "Number of currently open HTTP connections to the exporter (new, active or idle)",
+		}),
+
+		// Per-home collection duration histogram
+		HomeCollectionDurationSeconds: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:        "tado_exporter_home_collection_duration_seconds",
+			ConstLabels: constLabels,
+			Help:        "Time taken to collect a single home's metrics from Tado API in seconds",
+			Buckets:     prometheus.ExponentialBuckets(0.1, 2, 6), // 0.1, 0.2, 0.4, 0.8, 1.6, 3.2
+		}, []string{"home_id"}),
+
+		// Remaining access token lifetime
+		TokenValidSeconds: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "tado_exporter_token_valid_seconds",
+			ConstLabels: constLabels,
+			Help:        "Seconds until the current Tado OAuth2 access token expires, as of the last scrape (negative once expired)",
+		}),
+
+		// Metrics endpoint requests by scraper type
+		ScrapeRequestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "tado_exporter_scrape_requests_total",
+			ConstLabels: constLabels,
+			Help:        "Total number of metrics endpoint requests by scraper type (prometheus, grafana_agent, curl, browser, other)",
+		}, []string{"user_agent_class"}),
+
+		// Token file status gauge (1 = unreadable)
+		TokenFileError: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "tado_exporter_token_file_error",
+			ConstLabels: constLabels,
+			Help:        "Set to 1 if the token file could not be decrypted or parsed (corrupted file or wrong passphrase), 0 otherwise",
+		}),
+
+		// Time between the two most recent scrapes
+		ObservedScrapeIntervalSeconds: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "tado_exporter_observed_scrape_interval_seconds",
+			ConstLabels: constLabels,
+			Help:        "Seconds between the starts of the two most recent scrapes (0 until the second scrape)",
 		}),
+
+		// Malformed Tado API response counter
+		ResponseParseErrorsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        "tado_exporter_response_parse_errors_total",
+			ConstLabels: constLabels,
+			Help:        "Total number of Tado API responses that were missing required fields",
+		}),
+
+		// Recovered collection panic counter
+		CollectorPanicsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        "tado_exporter_collector_panics_total",
+			ConstLabels: constLabels,
+			Help:        "Total number of panics recovered while collecting metrics from Tado API (last known values were served instead)",
+		}),
+
+		// Last scrape status gauge
+		ScrapeSuccess: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "tado_exporter_scrape_success",
+			ConstLabels: constLabels,
+			Help:        "Set to 1 if the last scrape collected Tado metrics without error, 0 if it failed and last known values were served",
+		}),
+
+		// Authenticated Tado account info
+		AccountInfo: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "tado_exporter_account_info",
+			ConstLabels: constLabels,
+			Help:        "Authenticated Tado account (value is always 1); account_hash is a hash of the account email, email is only set when exposing it is enabled",
+		}, []string{"account_hash", "email"}),
+
+		// Rate-limited Tado API call counter
+		ThrottledRequestsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        "tado_exporter_throttled_requests_total",
+			ConstLabels: constLabels,
+			Help:        "Total number of Tado API calls that had to wait for, or were rejected by, the request rate limit",
+		}),
+		APIUnavailableTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        "tado_exporter_api_unavailable_total",
+			ConstLabels: constLabels,
+			Help:        "Total number of Tado API calls answered with 503 Service Unavailable, e.g. during maintenance",
+		}),
+		ZoneTimeBudgetSeconds: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "tado_exporter_zone_time_budget_seconds",
+			ConstLabels: constLabels,
+			Help:        "Remaining scrape time divided by the number of zones when a home's zones were listed, in seconds",
+		}, []string{"home_id"}),
+		LastErrorInfo: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "tado_exporter_last_error_info",
+			ConstLabels: constLabels,
+			Help:        "Category of the last error of the most recent scrape (auth, timeout, rate_limit or api; value is always 1), absent after an error-free scrape",
+		}, []string{"reason"}),
+		HomesTotal: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "tado_exporter_homes_total",
+			ConstLabels: constLabels,
+			Help:        "Number of homes collected by the most recent scrape, after the home ID filter and exclude list",
+		}),
+		TrackedSeriesTotal: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "tado_exporter_tracked_series_total",
+			ConstLabels: constLabels,
+			Help:        "Number of (home_id, zone_id) combinations currently exported by the zone metrics",
+		}),
+		OldestZoneReadingUnix: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "tado_exporter_oldest_zone_reading_unix",
+			ConstLabels: constLabels,
+			Help:        "Unix time of the oldest zone sensor reading seen by the most recent scrape, across all zones",
+		}),
+		ZonesHeatingTotal: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "tado_exporter_zones_heating_total",
+			ConstLabels: constLabels,
+			Help:        "Number of zones with heating power above 0% in the most recent scrape, across all homes",
+		}),
+		RetriesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "tado_exporter_retries_total",
+			ConstLabels: constLabels,
+			Help:        "Total number of Tado API calls retried after a transient error, by endpoint",
+		}, []string{"endpoint"}),
+		HomeSkipped: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "tado_exporter_home_skipped",
+			ConstLabels: constLabels,
+			Help:        "Whether the home is skipped after failing too many scrapes in a row (1 = skipped until its cooldown ends, 0 = collected)",
+		}, []string{"home_id"}),
+		FeatureEnabled: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "tado_exporter_feature_enabled",
+			ConstLabels: constLabels,
+			Help:        "Whether the optional feature is enabled by the exporter's configuration (1 = enabled, 0 = disabled)",
+		}, []string{"feature"}),
 	}
 
-	// Register metrics
-	if err := em.Register(); err != nil {
-		return nil, err
+	em.clock = RealClock
+	em.dataFetchedAt = make(map[string]time.Time)
+
+	// Age of each home's data, computed at scrape time
+	em.DataAgeSeconds = &dataAgeCollector{
+		em: em,
+		desc: prometheus.NewDesc(
+			"tado_exporter_data_age_seconds",
+			"Seconds since the home's Tado data was last fetched successfully; grows while failed scrapes serve last known values",
+			[]string{"home_id"}, constLabels,
+		),
 	}
 
+	// Open duration of the circuit breaker
+	em.CircuitBreakerOpenSeconds = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name:        "tado_exporter_circuit_breaker_open_seconds",
+		ConstLabels: constLabels,
+		Help:        "Seconds since the Tado API circuit breaker opened, including half-open trials (0 while closed)",
+	}, em.circuitBreakerOpenSeconds)
+
 	// Set build info to 1
 	em.BuildInfo.Set(1)
 
 	// Initialize authentication status to invalid (will be set to 1 once authentication succeeds during first scrape)
 	em.AuthenticationValid.Set(0)
 
-	return em, nil
+	return em
 }
 
-// Register registers exporter metrics with Prometheus
-func (em *ExporterMetrics) Register() error {
-	if err := prometheus.DefaultRegisterer.Register(em.ScrapeDurationSeconds); err != nil {
+// WithClock replaces the clock telling the time for the authentication timestamp, the data age and the
+// circuit breaker open time, so tests can control it
+func (em *ExporterMetrics) WithClock(clock Clock) *ExporterMetrics {
+	em.clock = clock
+	return em
+}
+
+// now returns the current time from the clock, falling back to RealClock for ExporterMetrics built as literals
+func (em *ExporterMetrics) now() time.Time {
+	if em.clock == nil {
+		return RealClock.Now()
+	}
+	return em.clock.Now()
+}
+
+// RegisterWith registers exporter metrics with the provided Prometheus registry
+func (em *ExporterMetrics) RegisterWith(registerer prometheus.Registerer) error {
+	if err := em.RegisterAccountMetricsWith(registerer); err != nil {
+		return err
+	}
+	return em.RegisterProcessMetricsWith(registerer)
+}
+
+// RegisterAccountMetricsWith registers the metrics describing the collection of one Tado account:
+// scrapes, authentication and Tado API calls. With several accounts each has its own, registered
+// with an account label
+func (em *ExporterMetrics) RegisterAccountMetricsWith(registerer prometheus.Registerer) error {
+	if err := register(registerer, em.ScrapeDurationSeconds); err != nil {
+		return err
+	}
+	if err := register(registerer, em.ScrapeErrorsTotal); err != nil {
+		return err
+	}
+	if err := register(registerer, em.AuthenticationValid); err != nil {
+		return err
+	}
+	if err := register(registerer, em.AuthenticationErrorsTotal); err != nil {
+		return err
+	}
+	if err := register(registerer, em.LastAuthenticationSuccessUnix); err != nil {
+		return err
+	}
+	if err := register(registerer, em.ScrapeBudgetUsedRatio); err != nil {
+		return err
+	}
+	if err := register(registerer, em.ZonesObserved); err != nil {
+		return err
+	}
+	if err := register(registerer, em.ClockSkewSeconds); err != nil {
+		return err
+	}
+	if err := register(registerer, em.TokenRefreshesTotal); err != nil {
+		return err
+	}
+	if err := register(registerer, em.HomeCollectionDurationSeconds); err != nil {
+		return err
+	}
+	if err := register(registerer, em.TokenValidSeconds); err != nil {
+		return err
+	}
+	if err := register(registerer, em.CircuitBreakerOpenSeconds); err != nil {
+		return err
+	}
+	if err := register(registerer, em.ObservedScrapeIntervalSeconds); err != nil {
+		return err
+	}
+	if err := register(registerer, em.ResponseParseErrorsTotal); err != nil {
+		return err
+	}
+	if err := register(registerer, em.CollectorPanicsTotal); err != nil {
+		return err
+	}
+	if err := register(registerer, em.ScrapeSuccess); err != nil {
+		return err
+	}
+	if err := register(registerer, em.AccountInfo); err != nil {
+		return err
+	}
+	if err := register(registerer, em.ThrottledRequestsTotal); err != nil {
+		return err
+	}
+	if err := register(registerer, em.APIUnavailableTotal); err != nil {
+		return err
+	}
+	if err := register(registerer, em.ZoneTimeBudgetSeconds); err != nil {
+		return err
+	}
+	if err := register(registerer, em.LastErrorInfo); err != nil {
+		return err
+	}
+	if err := register(registerer, em.HomesTotal); err != nil {
+		return err
+	}
+	if err := register(registerer, em.TrackedSeriesTotal); err != nil {
 		return err
 	}
-	if err := prometheus.DefaultRegisterer.Register(em.ScrapeErrorsTotal); err != nil {
+	if err := register(registerer, em.OldestZoneReadingUnix); err != nil {
 		return err
 	}
-	if err := prometheus.DefaultRegisterer.Register(em.BuildInfo); err != nil {
+	if err := register(registerer, em.ZonesHeatingTotal); err != nil {
 		return err
 	}
-	if err := prometheus.DefaultRegisterer.Register(em.AuthenticationValid); err != nil {
+	if err := register(registerer, em.RetriesTotal); err != nil {
 		return err
 	}
-	if err := prometheus.DefaultRegisterer.Register(em.AuthenticationErrorsTotal); err != nil {
+	if err := register(registerer, em.HomeSkipped); err != nil {
 		return err
 	}
-	if err := prometheus.DefaultRegisterer.Register(em.LastAuthenticationSuccessUnix); err != nil {
+	if err := register(registerer, em.DataAgeSeconds); err != nil {
 		return err
 	}
 	return nil
 }
 
+// RegisterProcessMetricsWith registers the metrics describing the exporter process as a whole:
+// build, configuration, token file and HTTP server. They are registered once however many accounts are collected
+func (em *ExporterMetrics) RegisterProcessMetricsWith(registerer prometheus.Registerer) error {
+	if err := register(registerer, em.BuildInfo); err != nil {
+		return err
+	}
+	if err := register(registerer, em.HTTPConnectionsActive); err != nil {
+		return err
+	}
+	if err := register(registerer, em.ScrapeRequestsTotal); err != nil {
+		return err
+	}
+	if err := register(registerer, em.TokenFileError); err != nil {
+		return err
+	}
+	if err := register(registerer, em.FeatureEnabled); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Validate registers the exporter metrics with a throwaway registry, returning any naming or duplicate registration error
+// Call it on startup so descriptor mistakes fail fast instead of on the first scrape
+func (em *ExporterMetrics) Validate() error {
+	if err := em.RegisterWith(prometheus.NewRegistry()); err != nil {
+		return fmt.Errorf("invalid exporter metrics: %w", err)
+	}
+	return nil
+}
+
+// Register registers exporter metrics with the Prometheus default registry
+func (em *ExporterMetrics) Register() error {
+	return em.RegisterWith(prometheus.DefaultRegisterer)
+}
+
 // RecordScrapeDuration records the duration of a metrics collection attempt
 func (em *ExporterMetrics) RecordScrapeDuration(duration float64) {
 	em.ScrapeDurationSeconds.Observe(duration)
@@ -147,5 +593,228 @@ func (em *ExporterMetrics) IncrementAuthenticationErrors() {
 
 // RecordAuthenticationSuccess records a successful authentication by setting the timestamp
 func (em *ExporterMetrics) RecordAuthenticationSuccess() {
-	em.LastAuthenticationSuccessUnix.Set(float64(time.Now().Unix()))
+	em.LastAuthenticationSuccessUnix.Set(float64(em.now().Unix()))
+}
+
+// RecordScrapeBudgetUsed records the fraction of the scrape timeout consumed by a scrape
+func (em *ExporterMetrics) RecordScrapeBudgetUsed(duration, timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+	em.ScrapeBudgetUsedRatio.Set(duration.Seconds() / timeout.Seconds())
+}
+
+// RecordZonesObserved records the total number of zones seen during a scrape
+func (em *ExporterMetrics) RecordZonesObserved(count int) {
+	em.ZonesObserved.Observe(float64(count))
+}
+
+// SetClockSkew records the difference between the newest Tado sensor timestamp and the local clock
+func (em *ExporterMetrics) SetClockSkew(skew time.Duration) {
+	em.ClockSkewSeconds.Set(skew.Seconds())
+}
+
+// IncrementTokenRefreshes increments the access token refresh counter
+func (em *ExporterMetrics) IncrementTokenRefreshes() {
+	em.TokenRefreshesTotal.Inc()
+}
+
+// TrackConnState updates the open connections gauge; use it as http.Server.ConnState
+// Connections are counted when accepted and released when closed or hijacked
+func (em *ExporterMetrics) TrackConnState(_ net.Conn, state http.ConnState) {
+	switch state {
+	case http.StateNew:
+		em.HTTPConnectionsActive.Inc()
+	case http.StateClosed, http.StateHijacked:
+		em.HTTPConnectionsActive.Dec()
+	}
+}
+
+// RecordHomeCollectionDuration records how long collecting a single home's metrics took
+func (em *ExporterMetrics) RecordHomeCollectionDuration(homeID string, duration time.Duration) {
+	em.HomeCollectionDurationSeconds.WithLabelValues(homeID).Observe(duration.Seconds())
+}
+
+// SetTokenValidity records how long the current access token remains valid
+func (em *ExporterMetrics) SetTokenValidity(remaining time.Duration) {
+	em.TokenValidSeconds.Set(remaining.Seconds())
+}
+
+// RecordScrapeRequest counts a metrics endpoint request from a scraper of the given class
+func (em *ExporterMetrics) RecordScrapeRequest(userAgentClass string) {
+	em.ScrapeRequestsTotal.WithLabelValues(userAgentClass).Inc()
+}
+
+// SetTokenFileError sets the token file status gauge
+func (em *ExporterMetrics) SetTokenFileError(unreadable bool) {
+	if unreadable {
+		em.TokenFileError.Set(1)
+	} else {
+		em.TokenFileError.Set(0)
+	}
+}
+
+// SetObservedScrapeInterval records the time between the starts of the two most recent scrapes
+func (em *ExporterMetrics) SetObservedScrapeInterval(interval time.Duration) {
+	em.ObservedScrapeIntervalSeconds.Set(interval.Seconds())
+}
+
+// IncrementResponseParseErrors increments the malformed response counter
+func (em *ExporterMetrics) IncrementResponseParseErrors() {
+	em.ResponseParseErrorsTotal.Inc()
+}
+
+// SetScrapeSuccess sets the last scrape status gauge
+func (em *ExporterMetrics) SetScrapeSuccess(success bool) {
+	if success {
+		em.ScrapeSuccess.Set(1)
+	} else {
+		em.ScrapeSuccess.Set(0)
+	}
+}
+
+// SetAccountInfo records an authenticated Tado account; email is empty unless exposing it is enabled
+func (em *ExporterMetrics) SetAccountInfo(accountHash, email string) {
+	em.AccountInfo.WithLabelValues(accountHash, email).Set(1)
+}
+
+// IncrementCollectorPanics increments the recovered collection panic counter
+func (em *ExporterMetrics) IncrementCollectorPanics() {
+	em.CollectorPanicsTotal.Inc()
+}
+
+// IncrementThrottledRequests increments the rate-limited call counter; use it from the rate limiter's OnThrottle
+func (em *ExporterMetrics) IncrementThrottledRequests() {
+	em.ThrottledRequestsTotal.Inc()
+}
+
+// IncrementAPIUnavailable increments the 503 response counter; use it from the adapter's OnUnavailable
+func (em *ExporterMetrics) IncrementAPIUnavailable() {
+	em.APIUnavailableTotal.Inc()
+}
+
+// SetZoneTimeBudget records the remaining scrape time per zone of a home
+func (em *ExporterMetrics) SetZoneTimeBudget(homeID string, budget time.Duration) {
+	em.ZoneTimeBudgetSeconds.WithLabelValues(homeID).Set(budget.Seconds())
+}
+
+// SetLastError records the category of the most recent scrape's last error, replacing any previous one
+func (em *ExporterMetrics) SetLastError(reason string) {
+	em.LastErrorInfo.Reset()
+	em.LastErrorInfo.WithLabelValues(reason).Set(1)
+}
+
+// ClearLastError removes the last error after an error-free scrape
+func (em *ExporterMetrics) ClearLastError() {
+	em.LastErrorInfo.Reset()
+}
+
+// SetHomesTotal records the number of homes collected by the most recent scrape
+func (em *ExporterMetrics) SetHomesTotal(count int) {
+	em.HomesTotal.Set(float64(count))
+}
+
+// SetTrackedSeries records the number of (home_id, zone_id) combinations currently exported
+func (em *ExporterMetrics) SetTrackedSeries(count int) {
+	em.TrackedSeriesTotal.Set(float64(count))
+}
+
+// SetOldestZoneReading records the timestamp of the oldest zone sensor reading across all zones
+func (em *ExporterMetrics) SetOldestZoneReading(t time.Time) {
+	em.OldestZoneReadingUnix.Set(float64(t.Unix()))
+}
+
+// SetZonesHeating records the number of zones currently calling for heat
+func (em *ExporterMetrics) SetZonesHeating(count int) {
+	em.ZonesHeatingTotal.Set(float64(count))
+}
+
+// IncrementRetries counts a retry of a Tado API call to endpoint; use it from the retrying wrapper's OnRetry
+func (em *ExporterMetrics) IncrementRetries(endpoint string) {
+	em.RetriesTotal.WithLabelValues(endpoint).Inc()
+}
+
+// SetHomeSkipped records whether a home is skipped after failing too many scrapes in a row
+func (em *ExporterMetrics) SetHomeSkipped(homeID string, skipped bool) {
+	value := 0.0
+	if skipped {
+		value = 1.0
+	}
+	em.HomeSkipped.WithLabelValues(homeID).Set(value)
+}
+
+// SetFeatureEnabled records whether an optional feature is enabled by the configuration
+func (em *ExporterMetrics) SetFeatureEnabled(feature string, enabled bool) {
+	value := 0.0
+	if enabled {
+		value = 1.0
+	}
+	em.FeatureEnabled.WithLabelValues(feature).Set(value)
+}
+
+// RecordHomeDataFetched records that a home's data was just fetched successfully
+func (em *ExporterMetrics) RecordHomeDataFetched(homeID string) {
+	em.dataMu.Lock()
+	defer em.dataMu.Unlock()
+
+	em.dataFetchedAt[homeID] = em.now()
+}
+
+// dataAgeCollector exposes tado_exporter_data_age_seconds, computing each home's age at scrape time
+type dataAgeCollector struct {
+	em   *ExporterMetrics
+	desc *prometheus.Desc
+}
+
+// Describe implements prometheus.Collector
+func (c *dataAgeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector
+func (c *dataAgeCollector) Collect(ch chan<- prometheus.Metric) {
+	c.em.dataMu.Lock()
+	defer c.em.dataMu.Unlock()
+
+	now := c.em.now()
+	for homeID, fetchedAt := range c.em.dataFetchedAt {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, now.Sub(fetchedAt).Seconds(), homeID)
+	}
+}
+
+// metricType implements typedCollector, as no home may have been fetched yet to infer it from
+func (c *dataAgeCollector) metricType() string {
+	return "gauge"
+}
+
+// SetCircuitBreakerOpen records whether the circuit breaker is open; use it from the breaker's OnStateChange
+// The open time is kept while the breaker stays open or half-open, so the metric covers the whole outage
+func (em *ExporterMetrics) SetCircuitBreakerOpen(open bool) {
+	em.circuitMu.Lock()
+	defer em.circuitMu.Unlock()
+
+	if !open {
+		em.circuitOpenedAt = time.Time{}
+	} else if em.circuitOpenedAt.IsZero() {
+		em.circuitOpenedAt = em.now()
+	}
+}
+
+// CircuitBreakerOpen reports whether the circuit breaker is currently open or half-open
+func (em *ExporterMetrics) CircuitBreakerOpen() bool {
+	em.circuitMu.Lock()
+	defer em.circuitMu.Unlock()
+
+	return !em.circuitOpenedAt.IsZero()
+}
+
+// circuitBreakerOpenSeconds returns the seconds since the circuit breaker opened, or 0 while it is closed
+func (em *ExporterMetrics) circuitBreakerOpenSeconds() float64 {
+	em.circuitMu.Lock()
+	defer em.circuitMu.Unlock()
+
+	if em.circuitOpenedAt.IsZero() {
+		return 0
+	}
+	return em.now().Sub(em.circuitOpenedAt).Seconds()
 }
@@ -1,7 +1,9 @@
 package metrics
 
 import (
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
@@ -411,3 +413,150 @@ func TestLastAuthenticationSuccess(t *testing.T) {
 	}
 	assert.True(t, successFound, "auth success timestamp metric not found")
 }
+
+// TestRecordAuthenticationSuccessUsesClock tests that the authentication timestamp comes from the injected clock
+func TestRecordAuthenticationSuccessUsesClock(t *testing.T) {
+	em := NewExporterMetricsUnregistered()
+	registry := prometheus.NewRegistry()
+	require.NoError(t, em.RegisterWith(registry))
+
+	authenticatedAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	em.WithClock(&fakeClock{now: authenticatedAt})
+
+	em.RecordAuthenticationSuccess()
+	assert.Equal(t, float64(authenticatedAt.Unix()), testGaugeValue(t, registry, "tado_exporter_last_authentication_success_unix"))
+}
+
+// TestRecordScrapeBudgetUsed tests recording the scrape budget ratio
+func TestRecordScrapeBudgetUsed(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	em := NewExporterMetricsUnregistered()
+	require.NoError(t, em.RegisterWith(registry))
+
+	em.RecordScrapeBudgetUsed(2500*time.Millisecond, 10*time.Second)
+	assert.Equal(t, 0.25, testGaugeValue(t, registry, "tado_exporter_scrape_budget_used_ratio"))
+
+	// A zero timeout leaves the previous value untouched
+	em.RecordScrapeBudgetUsed(time.Second, 0)
+	assert.Equal(t, 0.25, testGaugeValue(t, registry, "tado_exporter_scrape_budget_used_ratio"))
+}
+
+// testGaugeValue gathers the registry and returns the value of the named unlabeled gauge
+func testGaugeValue(t *testing.T, registry *prometheus.Registry, name string) float64 {
+	t.Helper()
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() == name {
+			require.Len(t, family.Metric, 1)
+			return family.Metric[0].GetGauge().GetValue()
+		}
+	}
+	t.Fatalf("metric %s not found", name)
+	return 0
+}
+
+// fakeClock is a Clock that only moves when advanced, for asserting time-dependent metrics exactly
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// Now implements Clock
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// TestSetCircuitBreakerOpen tests that the open duration runs from the first open until the breaker closes
+func TestSetCircuitBreakerOpen(t *testing.T) {
+	em := NewExporterMetricsUnregistered()
+	registry := prometheus.NewRegistry()
+	require.NoError(t, em.RegisterWith(registry))
+
+	assert.Equal(t, 0.0, testGaugeValue(t, registry, "tado_exporter_circuit_breaker_open_seconds"))
+
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	em.WithClock(clock)
+
+	em.SetCircuitBreakerOpen(true)
+	clock.Advance(20 * time.Second)
+
+	// Moving between open and half-open keeps the original open time
+	em.SetCircuitBreakerOpen(true)
+	assert.Equal(t, 20.0, testGaugeValue(t, registry, "tado_exporter_circuit_breaker_open_seconds"))
+
+	em.SetCircuitBreakerOpen(false)
+	assert.Equal(t, 0.0, testGaugeValue(t, registry, "tado_exporter_circuit_breaker_open_seconds"))
+}
+
+// TestSetTokenFileError tests the token file status gauge
+func TestSetTokenFileError(t *testing.T) {
+	em := NewExporterMetricsUnregistered()
+	registry := prometheus.NewRegistry()
+	require.NoError(t, em.RegisterWith(registry))
+
+	em.SetTokenFileError(true)
+	assert.Equal(t, 1.0, testGaugeValue(t, registry, "tado_exporter_token_file_error"))
+
+	em.SetTokenFileError(false)
+	assert.Equal(t, 0.0, testGaugeValue(t, registry, "tado_exporter_token_file_error"))
+}
+
+// TestExporterMetricsValidate tests that the exporter metrics pass validation and a duplicate metric name is reported
+func TestExporterMetricsValidate(t *testing.T) {
+	assert.NoError(t, NewExporterMetricsUnregistered().Validate())
+
+	em := NewExporterMetricsUnregistered()
+	em.ResponseParseErrorsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "tado_exporter_scrape_errors_total",
+		Help: "Duplicate of tado_exporter_scrape_errors_total",
+	})
+
+	err := em.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid exporter metrics")
+	assert.Contains(t, err.Error(), "tado_exporter_scrape_errors_total")
+}
+
+// TestRecordHomeDataFetched tests that the data age is zero right after a fetch and grows with time
+func TestRecordHomeDataFetched(t *testing.T) {
+	em := NewExporterMetricsUnregistered()
+	registry := prometheus.NewRegistry()
+	require.NoError(t, em.RegisterWith(registry))
+
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	em.WithClock(clock)
+
+	em.RecordHomeDataFetched("1")
+	assert.Equal(t, 0.0, testGaugeValue(t, registry, "tado_exporter_data_age_seconds"))
+
+	clock.Advance(90 * time.Second)
+	assert.Equal(t, 90.0, testGaugeValue(t, registry, "tado_exporter_data_age_seconds"))
+
+	// A new fetch resets the age
+	em.RecordHomeDataFetched("1")
+	assert.Equal(t, 0.0, testGaugeValue(t, registry, "tado_exporter_data_age_seconds"))
+}
+
+// TestExporterMetricsRegisterWithTwiceNamesMetric tests that registering the exporter metrics twice reports which metric was already registered
+func TestExporterMetricsRegisterWithTwiceNamesMetric(t *testing.T) {
+	em := NewExporterMetricsUnregistered()
+	registry := prometheus.NewRegistry()
+	require.NoError(t, em.RegisterWith(registry))
+
+	err := em.RegisterWith(registry)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "metric tado_exporter_scrape_duration_seconds is already registered")
+}
@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// MetricInfo describes one exported metric, for documentation and dashboard generation
+type MetricInfo struct {
+	Name   string   `json:"name"`
+	Type   string   `json:"type"`
+	Help   string   `json:"help"`
+	Labels []string `json:"labels"` // Variable labels; constant labels are not included
+}
+
+// descHelpPattern and descLabelsPattern extract the help text and variable labels from a prometheus.Desc's String()
+var (
+	descHelpPattern   = regexp.MustCompile(`help: ("(?:[^"\\]|\\.)*")`)
+	descLabelsPattern = regexp.MustCompile(`variableLabels: \{([^}]*)\}`)
+)
+
+// typedCollector is implemented by custom collectors whose metric type can't be inferred from their Go type
+type typedCollector interface {
+	metricType() string
+}
+
+// ListMetrics describes every metric of md and em, sorted by name
+// Either may be nil to leave its metrics out
+func ListMetrics(md *MetricDescriptors, em *ExporterMetrics) ([]MetricInfo, error) {
+	recorder := &recordingRegisterer{}
+	if md != nil {
+		if err := md.RegisterWith(recorder); err != nil {
+			return nil, err
+		}
+	}
+	if em != nil {
+		if err := em.RegisterWith(recorder); err != nil {
+			return nil, err
+		}
+	}
+
+	var infos []MetricInfo
+	for _, collector := range recorder.collectors {
+		metricType := collectorType(collector)
+
+		ch := make(chan *prometheus.Desc)
+		go func() {
+			collector.Describe(ch)
+			close(ch)
+		}()
+		for desc := range ch {
+			infos = append(infos, describeMetric(desc.String(), metricType))
+		}
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
+
+// describeMetric builds the MetricInfo of a metric from its prometheus.Desc's String()
+func describeMetric(desc string, metricType string) MetricInfo {
+	info := MetricInfo{Type: metricType, Labels: []string{}}
+	if match := descNamePattern.FindStringSubmatch(desc); match != nil {
+		info.Name = match[1]
+	}
+	if match := descHelpPattern.FindStringSubmatch(desc); match != nil {
+		info.Help, _ = strconv.Unquote(match[1])
+	}
+	if match := descLabelsPattern.FindStringSubmatch(desc); match != nil && match[1] != "" {
+		info.Labels = strings.Split(match[1], ",")
+	}
+	return info
+}
+
+// collectorType returns the Prometheus metric type of collector's metrics
+func collectorType(collector prometheus.Collector) string {
+	switch c := collector.(type) {
+	case typedCollector:
+		return c.metricType()
+	case *prometheus.GaugeVec:
+		return "gauge"
+	case *prometheus.CounterVec:
+		return "counter"
+	case *prometheus.HistogramVec:
+		return "histogram"
+	case prometheus.Gauge: // Checked before Counter, which a Gauge also satisfies
+		return "gauge"
+	case prometheus.Histogram:
+		return "histogram"
+	case prometheus.Counter:
+		return "counter"
+	}
+
+	// Function-backed metrics such as GaugeFuncs always yield one metric to inspect
+	ch := make(chan prometheus.Metric, 1)
+	go func() {
+		collector.Collect(ch)
+		close(ch)
+	}()
+	metricType := "untyped"
+	for metric := range ch {
+		var m dto.Metric
+		if err := metric.Write(&m); err == nil && metricType == "untyped" {
+			switch {
+			case m.Gauge != nil:
+				metricType = "gauge"
+			case m.Counter != nil:
+				metricType = "counter"
+			case m.Histogram != nil:
+				metricType = "histogram"
+			}
+		}
+	}
+	return metricType
+}
+
+// recordingRegisterer is a prometheus.Registerer that only records the collectors registered with it
+type recordingRegisterer struct {
+	collectors []prometheus.Collector
+}
+
+func (r *recordingRegisterer) Register(collector prometheus.Collector) error {
+	r.collectors = append(r.collectors, collector)
+	return nil
+}
+
+func (r *recordingRegisterer) MustRegister(collectors ...prometheus.Collector) {
+	r.collectors = append(r.collectors, collectors...)
+}
+
+func (r *recordingRegisterer) Unregister(prometheus.Collector) bool {
+	return false
+}
@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestListMetrics tests that the listing includes the name, type, help and labels of Tado and exporter metrics
+func TestListMetrics(t *testing.T) {
+	md, err := NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+
+	infos, err := ListMetrics(md, NewExporterMetricsUnregistered())
+	require.NoError(t, err)
+
+	byName := make(map[string]MetricInfo, len(infos))
+	for _, info := range infos {
+		byName[info.Name] = info
+	}
+
+	temperature, ok := byName["tado_temperature_measured_celsius"]
+	require.True(t, ok)
+	assert.Equal(t, "gauge", temperature.Type)
+	assert.Equal(t, ZoneLabelNames, temperature.Labels)
+	assert.NotEmpty(t, temperature.Help)
+
+	tests := map[string]string{
+		"tado_is_resident_present":                   "gauge",
+		"tado_exporter_scrape_errors_total":          "counter",
+		"tado_exporter_scrape_duration_seconds":      "histogram",
+		"tado_exporter_circuit_breaker_open_seconds": "gauge",
+		"tado_exporter_data_age_seconds":             "gauge",
+	}
+	for name, metricType := range tests {
+		assert.Equal(t, metricType, byName[name].Type, name)
+	}
+	assert.Empty(t, byName["tado_is_resident_present"].Labels)
+	assert.Equal(t, []string{"home_id"}, byName["tado_exporter_data_age_seconds"].Labels)
+}
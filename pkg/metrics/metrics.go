@@ -6,8 +6,9 @@
 //   - Metric registration with Prometheus
 //
 // The package creates metrics for:
-//   - Home-level data: resident presence, weather (solar intensity, outside temperature)
-//   - Zone-level data: measured/set temperature, humidity, heating power, window/power status
+//   - Home-level data: resident presence, mobile devices at home, bridge connectivity, weather (solar intensity, outside temperature)
+//   - Zone-level data: measured/set temperature, indoor-outdoor delta, humidity, heating power, window/power status, AC mode and fan speed
+//   - Device-level data: battery state, connectivity and firmware version, depending on the DeviceMetricsLevel
 //   - Exporter health: collection performance, error tracking, authentication status
 //
 // Example usage:
@@ -23,214 +24,401 @@
 package metrics
 
 import (
+	"fmt"
+
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+// ZoneLabelNames are the labels of every zone-level metric, in declaration order
+// Label values must be passed in this same order; the collector builds them with zoneLabelValues
+var ZoneLabelNames = []string{"home_id", "zone_id", "zone_name", "zone_type"}
+
+// HomeLabelNames are the labels of the home-level metrics keyed by home, for accounts with several homes
+var HomeLabelNames = []string{"home_id"}
+
+// DeviceLabelNames are the labels of every device-level metric, in declaration order
+var DeviceLabelNames = []string{"home_id", "device_id", "device_type"}
+
 // MetricDescriptors holds all Prometheus metric descriptors for Tado
 type MetricDescriptors struct {
 	// Home-level metrics
 	IsResidentPresent            prometheus.Gauge
+	HomePresenceLocked           prometheus.GaugeVec // With label: home_id
+	HomePresenceState            prometheus.GaugeVec // Info gauge with labels: home_id, state
+	HomeBridgeConnected          prometheus.GaugeVec // With label: home_id
+	HomeDevicesTotal             prometheus.GaugeVec // With label: home_id
+	HomeDevicesAtHomeTotal       prometheus.GaugeVec // With label: home_id
 	SolarIntensityPercentage     prometheus.Gauge
+	WeatherIsDaytime             prometheus.GaugeVec // With label: home_id
 	TemperatureOutsideCelsius    prometheus.Gauge
 	TemperatureOutsideFahrenheit prometheus.Gauge
 
+	// Device-level metrics (with labels: device_id, device_type), collected depending on the DeviceMetricsLevel
+	DeviceBatteryLow   prometheus.GaugeVec
+	DeviceConnected    prometheus.GaugeVec
+	DeviceFirmwareInfo prometheus.GaugeVec // Info gauge with the additional label: firmware_version
+
 	// Zone-level metrics (with labels: zone_id, zone_name, zone_type)
 	TemperatureMeasuredCelsius    prometheus.GaugeVec
 	TemperatureMeasuredFahrenheit prometheus.GaugeVec
+	TemperatureMeasuredKelvin     prometheus.GaugeVec
 	HumidityMeasuredPercentage    prometheus.GaugeVec
 	TemperatureSetCelsius         prometheus.GaugeVec
 	TemperatureSetFahrenheit      prometheus.GaugeVec
 	HeatingPowerPercentage        prometheus.GaugeVec
 	IsWindowOpen                  prometheus.GaugeVec
 	IsZonePowered                 prometheus.GaugeVec
+	ZoneDataPresent               prometheus.GaugeVec
+	ZoneACMode                    prometheus.GaugeVec
+	ZoneACPower                   prometheus.GaugeVec
+	ZoneACFanSpeed                prometheus.GaugeVec
+	ZoneIndoorOutdoorDelta        prometheus.GaugeVec
+	ZoneChildLockEnabled          prometheus.GaugeVec
+	ZoneDazzleModeEnabled         prometheus.GaugeVec
+	HotWaterOverlayActive         prometheus.GaugeVec
+	ZoneDevicesTotal              prometheus.GaugeVec
+	ZoneTemperatureOffsetCelsius  prometheus.GaugeVec
+	ZoneFrostProtectionActive     prometheus.GaugeVec
+	ZoneOverlayRemainingSeconds   prometheus.GaugeVec
+
+	compat      MetricCompat      // Naming scheme the metrics were created with
+	constLabels prometheus.Labels // Labels added to every metric, nil for none
 }
 
 // NewMetricDescriptors creates and registers all Prometheus metrics
 func NewMetricDescriptors() (*MetricDescriptors, error) {
+	return NewMetricDescriptorsWithCompat(MetricCompatV1)
+}
+
+// NewMetricDescriptorsWithCompat creates and registers all Prometheus metrics using the given naming scheme
+func NewMetricDescriptorsWithCompat(compat MetricCompat) (*MetricDescriptors, error) {
+	md, err := NewMetricDescriptorsUnregisteredWithCompat(compat)
+	if err != nil {
+		return nil, err
+	}
+
+	// Register all metrics with Prometheus default registry
+	if err := md.Register(); err != nil {
+		return nil, err
+	}
+
+	return md, nil
+}
+
+// NewMetricDescriptorsUnregistered creates metric descriptors without registering them
+// This is useful for testing where each test needs isolated registries
+func NewMetricDescriptorsUnregistered() (*MetricDescriptors, error) {
+	return NewMetricDescriptorsUnregisteredWithCompat(MetricCompatV1)
+}
+
+// NewMetricDescriptorsUnregisteredWithCompat creates metric descriptors using the given naming scheme
+// without registering them
+func NewMetricDescriptorsUnregisteredWithCompat(compat MetricCompat) (*MetricDescriptors, error) {
+	return NewMetricDescriptorsUnregisteredWithLabels(compat, nil)
+}
+
+// NewMetricDescriptorsUnregisteredWithLabels creates metric descriptors using the given naming scheme,
+// adding constLabels to every metric, without registering them
+func NewMetricDescriptorsUnregisteredWithLabels(compat MetricCompat, constLabels prometheus.Labels) (*MetricDescriptors, error) {
 	md := &MetricDescriptors{
+		compat:      compat,
+		constLabels: constLabels,
+
 		// Home-level metrics (no labels)
 		IsResidentPresent: prometheus.NewGauge(prometheus.GaugeOpts{
-			Name: "tado_is_resident_present",
-			Help: "Whether anyone is home (1 = home, 0 = away)",
+			Name:        compat.metricName("tado_is_resident_present"),
+			ConstLabels: constLabels,
+			Help:        "Whether anyone is home (1 = home, 0 = away)",
 		}),
 
+		HomePresenceLocked: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        compat.metricName("tado_home_presence_locked"),
+				ConstLabels: constLabels,
+				Help:        "Whether home presence is manually locked, overriding geofencing (1 = locked, 0 = auto)",
+			},
+			HomeLabelNames,
+		),
+
+		HomePresenceState: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        compat.metricName("tado_home_presence_state"),
+				ConstLabels: constLabels,
+				Help:        "Home presence as reported by Tado (e.g. HOME, AWAY), always 1 with the value in the state label",
+			},
+			[]string{"home_id", "state"},
+		),
+
+		HomeBridgeConnected: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        compat.metricName("tado_home_bridge_connected"),
+				ConstLabels: constLabels,
+				Help:        "Whether the Tado internet bridge is connected to the Tado cloud (1 = connected, 0 = disconnected)",
+			},
+			HomeLabelNames,
+		),
+
+		HomeDevicesTotal: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        compat.metricName("tado_home_devices_total"),
+				ConstLabels: constLabels,
+				Help:        "Number of Tado devices in the home (thermostats, sensors, bridge)",
+			},
+			HomeLabelNames,
+		),
+
+		HomeDevicesAtHomeTotal: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        compat.metricName("tado_home_devices_at_home_total"),
+				ConstLabels: constLabels,
+				Help:        "Number of the home's geofencing mobile devices currently at home",
+			},
+			HomeLabelNames,
+		),
+
 		SolarIntensityPercentage: prometheus.NewGauge(prometheus.GaugeOpts{
-			Name: "tado_solar_intensity_percentage",
-			Help: "Solar radiation intensity as a percentage (0-100%)",
+			Name:        compat.metricName("tado_solar_intensity_percentage"),
+			ConstLabels: constLabels,
+			Help:        "Solar radiation intensity as a percentage (0-100%)",
 		}),
 
+		WeatherIsDaytime: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        compat.metricName("tado_weather_is_daytime"),
+				ConstLabels: constLabels,
+				Help:        "Whether it is daytime at the home, derived from solar intensity above 0% (1 = day, 0 = night)",
+			},
+			HomeLabelNames,
+		),
+
 		TemperatureOutsideCelsius: prometheus.NewGauge(prometheus.GaugeOpts{
-			Name: "tado_temperature_outside_celsius",
-			Help: "Outside temperature in Celsius",
+			Name:        compat.metricName("tado_temperature_outside_celsius"),
+			ConstLabels: constLabels,
+			Help:        "Outside temperature in Celsius",
 		}),
 
 		TemperatureOutsideFahrenheit: prometheus.NewGauge(prometheus.GaugeOpts{
-			Name: "tado_temperature_outside_fahrenheit",
-			Help: "Outside temperature in Fahrenheit",
+			Name:        compat.metricName("tado_temperature_outside_fahrenheit"),
+			ConstLabels: constLabels,
+			Help:        "Outside temperature in Fahrenheit",
 		}),
 
+		// Device-level metrics (with labels: home_id, device_id, device_type)
+		DeviceBatteryLow: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        compat.metricName("tado_device_battery_low"),
+				ConstLabels: constLabels,
+				Help:        "Whether the battery of a battery-powered Tado device is low (1 = low, 0 = normal)",
+			},
+			DeviceLabelNames,
+		),
+
+		DeviceConnected: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        compat.metricName("tado_device_connected"),
+				ConstLabels: constLabels,
+				Help:        "Whether the Tado device is connected (1 = connected, 0 = disconnected)",
+			},
+			DeviceLabelNames,
+		),
+
+		DeviceFirmwareInfo: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        compat.metricName("tado_device_firmware_info"),
+				ConstLabels: constLabels,
+				Help:        "Firmware version of the Tado device, always 1 with the version in the firmware_version label",
+			},
+			[]string{"home_id", "device_id", "device_type", "firmware_version"},
+		),
+
 		// Zone-level metrics (with labels: zone_id, zone_name, zone_type)
 		TemperatureMeasuredCelsius: *prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
-				Name: "tado_temperature_measured_celsius",
-				Help: "Measured temperature in Celsius",
+				Name:        compat.metricName("tado_temperature_measured_celsius"),
+				ConstLabels: constLabels,
+				Help:        "Measured temperature in Celsius",
 			},
-			[]string{"home_id", "zone_id", "zone_name", "zone_type"},
+			ZoneLabelNames,
 		),
 
 		TemperatureMeasuredFahrenheit: *prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
-				Name: "tado_temperature_measured_fahrenheit",
-				Help: "Measured temperature in Fahrenheit",
+				Name:        compat.metricName("tado_temperature_measured_fahrenheit"),
+				ConstLabels: constLabels,
+				Help:        "Measured temperature in Fahrenheit",
 			},
-			[]string{"home_id", "zone_id", "zone_name", "zone_type"},
+			ZoneLabelNames,
+		),
+
+		TemperatureMeasuredKelvin: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        compat.metricName("tado_temperature_measured_kelvin"),
+				ConstLabels: constLabels,
+				Help:        "Measured temperature in Kelvin",
+			},
+			ZoneLabelNames,
 		),
 
 		HumidityMeasuredPercentage: *prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
-				Name: "tado_humidity_measured_percentage",
-				Help: "Measured relative humidity as a percentage (0-100%)",
+				Name:        compat.metricName("tado_humidity_measured_percentage"),
+				ConstLabels: constLabels,
+				Help:        "Measured relative humidity as a percentage (0-100%)",
 			},
-			[]string{"home_id", "zone_id", "zone_name", "zone_type"},
+			ZoneLabelNames,
 		),
 
 		TemperatureSetCelsius: *prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
-				Name: "tado_temperature_set_celsius",
-				Help: "Set/target temperature in Celsius",
+				Name:        compat.metricName("tado_temperature_set_celsius"),
+				ConstLabels: constLabels,
+				Help:        "Set/target temperature in Celsius",
 			},
-			[]string{"home_id", "zone_id", "zone_name", "zone_type"},
+			ZoneLabelNames,
 		),
 
 		TemperatureSetFahrenheit: *prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
-				Name: "tado_temperature_set_fahrenheit",
-				Help: "Set/target temperature in Fahrenheit",
+				Name:        compat.metricName("tado_temperature_set_fahrenheit"),
+				ConstLabels: constLabels,
+				Help:        "Set/target temperature in Fahrenheit",
 			},
-			[]string{"home_id", "zone_id", "zone_name", "zone_type"},
+			ZoneLabelNames,
 		),
 
 		HeatingPowerPercentage: *prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
-				Name: "tado_heating_power_percentage",
-				Help: "Heating power as a percentage (0-100%)",
+				Name:        compat.metricName("tado_heating_power_percentage"),
+				ConstLabels: constLabels,
+				Help:        "Heating power as a percentage (0-100%)",
 			},
-			[]string{"home_id", "zone_id", "zone_name", "zone_type"},
+			ZoneLabelNames,
 		),
 
 		IsWindowOpen: *prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
-				Name: "tado_is_window_open",
-				Help: "Whether the window is open (1 = open, 0 = closed)",
+				Name:        compat.metricName("tado_is_window_open"),
+				ConstLabels: constLabels,
+				Help:        "Whether the window is open (1 = open, 0 = closed)",
 			},
-			[]string{"home_id", "zone_id", "zone_name", "zone_type"},
+			ZoneLabelNames,
 		),
 
 		IsZonePowered: *prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
-				Name: "tado_is_zone_powered",
-				Help: "Whether the zone is powered (1 = on, 0 = off)",
+				Name:        compat.metricName("tado_is_zone_powered"),
+				ConstLabels: constLabels,
+				Help:        "Whether the zone is powered (1 = on, 0 = off)",
 			},
-			[]string{"home_id", "zone_id", "zone_name", "zone_type"},
+			ZoneLabelNames,
 		),
-	}
 
-	// Register all metrics with Prometheus default registry
-	if err := md.Register(); err != nil {
-		return nil, err
-	}
-
-	return md, nil
-}
-
-// NewMetricDescriptorsUnregistered creates metric descriptors without registering them
-// This is useful for testing where each test needs isolated registries
-func NewMetricDescriptorsUnregistered() (*MetricDescriptors, error) {
-	md := &MetricDescriptors{
-		// Home-level metrics (no labels)
-		IsResidentPresent: prometheus.NewGauge(prometheus.GaugeOpts{
-			Name: "tado_is_resident_present",
-			Help: "Whether anyone is home (1 = home, 0 = away)",
-		}),
+		ZoneDataPresent: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        compat.metricName("tado_zone_data_present"),
+				ConstLabels: constLabels,
+				Help:        "Whether the zone reported a measured temperature in the last scrape (1 = present, 0 = missing, other zone series keep their last value)",
+			},
+			ZoneLabelNames,
+		),
 
-		SolarIntensityPercentage: prometheus.NewGauge(prometheus.GaugeOpts{
-			Name: "tado_solar_intensity_percentage",
-			Help: "Solar radiation intensity as a percentage (0-100%)",
-		}),
+		ZoneACMode: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        compat.metricName("tado_zone_ac_mode"),
+				ConstLabels: constLabels,
+				Help:        "Air conditioning mode of AC zones (1 = cool, 2 = heat, 3 = dry, 4 = fan, 5 = auto)",
+			},
+			ZoneLabelNames,
+		),
 
-		TemperatureOutsideCelsius: prometheus.NewGauge(prometheus.GaugeOpts{
-			Name: "tado_temperature_outside_celsius",
-			Help: "Outside temperature in Celsius",
-		}),
+		ZoneACPower: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        compat.metricName("tado_zone_ac_power"),
+				ConstLabels: constLabels,
+				Help:        "Whether the air conditioning unit of AC zones is on (1 = on, 0 = off)",
+			},
+			ZoneLabelNames,
+		),
 
-		TemperatureOutsideFahrenheit: prometheus.NewGauge(prometheus.GaugeOpts{
-			Name: "tado_temperature_outside_fahrenheit",
-			Help: "Outside temperature in Fahrenheit",
-		}),
+		ZoneACFanSpeed: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        compat.metricName("tado_zone_ac_fan_speed"),
+				ConstLabels: constLabels,
+				Help:        "Fan level of AC zones (1 = silent, 2-6 = level 1-5, 7 = auto)",
+			},
+			ZoneLabelNames,
+		),
 
-		// Zone-level metrics (with labels: zone_id, zone_name, zone_type)
-		TemperatureMeasuredCelsius: *prometheus.NewGaugeVec(
+		ZoneIndoorOutdoorDelta: *prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
-				Name: "tado_temperature_measured_celsius",
-				Help: "Measured temperature in Celsius",
+				Name:        compat.metricName("tado_zone_indoor_outdoor_delta_celsius"),
+				ConstLabels: constLabels,
+				Help:        "Measured zone temperature minus the home's outside temperature in Celsius",
 			},
-			[]string{"home_id", "zone_id", "zone_name", "zone_type"},
+			ZoneLabelNames,
 		),
 
-		TemperatureMeasuredFahrenheit: *prometheus.NewGaugeVec(
+		ZoneChildLockEnabled: *prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
-				Name: "tado_temperature_measured_fahrenheit",
-				Help: "Measured temperature in Fahrenheit",
+				Name:        compat.metricName("tado_zone_child_lock_enabled"),
+				ConstLabels: constLabels,
+				Help:        "Whether child lock is enabled on any of the zone's devices (1 = enabled, 0 = disabled)",
 			},
-			[]string{"home_id", "zone_id", "zone_name", "zone_type"},
+			ZoneLabelNames,
 		),
 
-		HumidityMeasuredPercentage: *prometheus.NewGaugeVec(
+		ZoneDazzleModeEnabled: *prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
-				Name: "tado_humidity_measured_percentage",
-				Help: "Measured relative humidity as a percentage (0-100%)",
+				Name:        compat.metricName("tado_zone_dazzle_mode_enabled"),
+				ConstLabels: constLabels,
+				Help:        "Whether dazzle mode, which animates the device display on setting changes, is enabled (1 = enabled, 0 = disabled)",
 			},
-			[]string{"home_id", "zone_id", "zone_name", "zone_type"},
+			ZoneLabelNames,
 		),
 
-		TemperatureSetCelsius: *prometheus.NewGaugeVec(
+		HotWaterOverlayActive: *prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
-				Name: "tado_temperature_set_celsius",
-				Help: "Set/target temperature in Celsius",
+				Name:        compat.metricName("tado_hot_water_overlay_active"),
+				ConstLabels: constLabels,
+				Help:        "Whether a manual overlay overrides the hot water schedule (1 = overridden, 0 = following the schedule)",
 			},
-			[]string{"home_id", "zone_id", "zone_name", "zone_type"},
+			ZoneLabelNames,
 		),
 
-		TemperatureSetFahrenheit: *prometheus.NewGaugeVec(
+		ZoneDevicesTotal: *prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
-				Name: "tado_temperature_set_fahrenheit",
-				Help: "Set/target temperature in Fahrenheit",
+				Name:        compat.metricName("tado_zone_devices_total"),
+				ConstLabels: constLabels,
+				Help:        "Number of devices (e.g. radiator valves) assigned to the zone",
 			},
-			[]string{"home_id", "zone_id", "zone_name", "zone_type"},
+			ZoneLabelNames,
 		),
 
-		HeatingPowerPercentage: *prometheus.NewGaugeVec(
+		ZoneTemperatureOffsetCelsius: *prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
-				Name: "tado_heating_power_percentage",
-				Help: "Heating power as a percentage (0-100%)",
+				Name:        compat.metricName("tado_zone_temperature_offset_celsius"),
+				ConstLabels: constLabels,
+				Help:        "Temperature offset configured on the zone's leading device in Celsius",
 			},
-			[]string{"home_id", "zone_id", "zone_name", "zone_type"},
+			ZoneLabelNames,
 		),
 
-		IsWindowOpen: *prometheus.NewGaugeVec(
+		ZoneFrostProtectionActive: *prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
-				Name: "tado_is_window_open",
-				Help: "Whether the window is open (1 = open, 0 = closed)",
+				Name:        compat.metricName("tado_zone_frost_protection_active"),
+				ConstLabels: constLabels,
+				Help:        "Whether a heating zone is powered on at the frost protection minimum setpoint of 5°C (1 = active, 0 = inactive)",
 			},
-			[]string{"home_id", "zone_id", "zone_name", "zone_type"},
+			ZoneLabelNames,
 		),
 
-		IsZonePowered: *prometheus.NewGaugeVec(
+		ZoneOverlayRemainingSeconds: *prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
-				Name: "tado_is_zone_powered",
-				Help: "Whether the zone is powered (1 = on, 0 = off)",
+				Name:        compat.metricName("tado_zone_overlay_remaining_seconds"),
+				ConstLabels: constLabels,
+				Help:        "Seconds until the zone's timer overlay expires and the schedule resumes; absent without a timer overlay",
 			},
-			[]string{"home_id", "zone_id", "zone_name", "zone_type"},
+			ZoneLabelNames,
 		),
 	}
 
@@ -241,48 +429,135 @@ func NewMetricDescriptorsUnregistered() (*MetricDescriptors, error) {
 // RegisterWith registers all metrics with the provided Prometheus registry
 func (md *MetricDescriptors) RegisterWith(registerer prometheus.Registerer) error {
 	// Home-level metrics
-	if err := registerer.Register(md.IsResidentPresent); err != nil {
+	if err := register(registerer, md.IsResidentPresent); err != nil {
+		return err
+	}
+	if err := register(registerer, &md.HomePresenceLocked); err != nil {
+		return err
+	}
+	if err := register(registerer, &md.HomePresenceState); err != nil {
+		return err
+	}
+	if err := register(registerer, &md.HomeBridgeConnected); err != nil {
+		return err
+	}
+	if err := register(registerer, &md.HomeDevicesTotal); err != nil {
+		return err
+	}
+	if err := register(registerer, &md.HomeDevicesAtHomeTotal); err != nil {
 		return err
 	}
-	if err := registerer.Register(md.SolarIntensityPercentage); err != nil {
+	if err := register(registerer, md.SolarIntensityPercentage); err != nil {
 		return err
 	}
-	if err := registerer.Register(md.TemperatureOutsideCelsius); err != nil {
+	if err := register(registerer, &md.WeatherIsDaytime); err != nil {
 		return err
 	}
-	if err := registerer.Register(md.TemperatureOutsideFahrenheit); err != nil {
+	if err := register(registerer, md.TemperatureOutsideCelsius); err != nil {
+		return err
+	}
+	if err := register(registerer, md.TemperatureOutsideFahrenheit); err != nil {
+		return err
+	}
+
+	// Device-level metrics
+	if err := register(registerer, &md.DeviceBatteryLow); err != nil {
+		return err
+	}
+	if err := register(registerer, &md.DeviceConnected); err != nil {
+		return err
+	}
+	if err := register(registerer, &md.DeviceFirmwareInfo); err != nil {
 		return err
 	}
 
 	// Zone-level metrics
-	if err := registerer.Register(&md.TemperatureMeasuredCelsius); err != nil {
+	if err := register(registerer, &md.TemperatureMeasuredCelsius); err != nil {
+		return err
+	}
+	if err := register(registerer, &md.TemperatureMeasuredFahrenheit); err != nil {
+		return err
+	}
+	if err := register(registerer, &md.TemperatureMeasuredKelvin); err != nil {
+		return err
+	}
+	if err := register(registerer, &md.HumidityMeasuredPercentage); err != nil {
+		return err
+	}
+	if err := register(registerer, &md.TemperatureSetCelsius); err != nil {
+		return err
+	}
+	if err := register(registerer, &md.TemperatureSetFahrenheit); err != nil {
+		return err
+	}
+	if err := register(registerer, &md.HeatingPowerPercentage); err != nil {
 		return err
 	}
-	if err := registerer.Register(&md.TemperatureMeasuredFahrenheit); err != nil {
+	if err := register(registerer, &md.IsWindowOpen); err != nil {
 		return err
 	}
-	if err := registerer.Register(&md.HumidityMeasuredPercentage); err != nil {
+	if err := register(registerer, &md.IsZonePowered); err != nil {
 		return err
 	}
-	if err := registerer.Register(&md.TemperatureSetCelsius); err != nil {
+	if err := register(registerer, &md.ZoneDataPresent); err != nil {
 		return err
 	}
-	if err := registerer.Register(&md.TemperatureSetFahrenheit); err != nil {
+	if err := register(registerer, &md.ZoneACMode); err != nil {
 		return err
 	}
-	if err := registerer.Register(&md.HeatingPowerPercentage); err != nil {
+	if err := register(registerer, &md.ZoneACPower); err != nil {
 		return err
 	}
-	if err := registerer.Register(&md.IsWindowOpen); err != nil {
+	if err := register(registerer, &md.ZoneACFanSpeed); err != nil {
 		return err
 	}
-	if err := registerer.Register(&md.IsZonePowered); err != nil {
+	if err := register(registerer, &md.ZoneIndoorOutdoorDelta); err != nil {
+		return err
+	}
+	if err := register(registerer, &md.ZoneChildLockEnabled); err != nil {
+		return err
+	}
+	if err := register(registerer, &md.ZoneDazzleModeEnabled); err != nil {
+		return err
+	}
+	if err := register(registerer, &md.HotWaterOverlayActive); err != nil {
+		return err
+	}
+	if err := register(registerer, &md.ZoneDevicesTotal); err != nil {
+		return err
+	}
+	if err := register(registerer, &md.ZoneTemperatureOffsetCelsius); err != nil {
+		return err
+	}
+	if err := register(registerer, &md.ZoneFrostProtectionActive); err != nil {
+		return err
+	}
+	if err := register(registerer, &md.ZoneOverlayRemainingSeconds); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// Validate registers all metrics with a throwaway registry, returning any naming or duplicate registration error
+// Call it on startup so descriptor mistakes fail fast instead of on the first scrape
+func (md *MetricDescriptors) Validate() error {
+	if err := md.RegisterWith(prometheus.NewRegistry()); err != nil {
+		return fmt.Errorf("invalid Tado metric descriptors: %w", err)
+	}
+	return nil
+}
+
+// Compat returns the naming scheme the metrics were created with
+func (md *MetricDescriptors) Compat() MetricCompat {
+	return md.compat
+}
+
+// ConstLabels returns the labels added to every metric
+func (md *MetricDescriptors) ConstLabels() prometheus.Labels {
+	return md.constLabels
+}
+
 // Register registers all metrics with the Prometheus default registry
 // Deprecated: Use RegisterWith instead for custom registries
 func (md *MetricDescriptors) Register() error {
@@ -292,21 +567,69 @@ func (md *MetricDescriptors) Register() error {
 // Reset clears all metric values (useful for testing)
 func (md *MetricDescriptors) Reset() {
 	md.IsResidentPresent.Set(0)
+	md.HomePresenceLocked.Reset()
+	md.HomePresenceState.Reset()
+	md.HomeBridgeConnected.Reset()
+	md.HomeDevicesTotal.Reset()
+	md.HomeDevicesAtHomeTotal.Reset()
 	md.SolarIntensityPercentage.Set(0)
+	md.WeatherIsDaytime.Reset()
 	md.TemperatureOutsideCelsius.Set(0)
 	md.TemperatureOutsideFahrenheit.Set(0)
+	md.DeviceBatteryLow.Reset()
+	md.DeviceConnected.Reset()
+	md.DeviceFirmwareInfo.Reset()
 
 	md.TemperatureMeasuredCelsius.Reset()
 	md.TemperatureMeasuredFahrenheit.Reset()
+	md.TemperatureMeasuredKelvin.Reset()
 	md.HumidityMeasuredPercentage.Reset()
 	md.TemperatureSetCelsius.Reset()
 	md.TemperatureSetFahrenheit.Reset()
 	md.HeatingPowerPercentage.Reset()
 	md.IsWindowOpen.Reset()
 	md.IsZonePowered.Reset()
+	md.ZoneDataPresent.Reset()
+	md.ZoneACMode.Reset()
+	md.ZoneACPower.Reset()
+	md.ZoneACFanSpeed.Reset()
+	md.ZoneIndoorOutdoorDelta.Reset()
+	md.ZoneChildLockEnabled.Reset()
+	md.ZoneDazzleModeEnabled.Reset()
+	md.HotWaterOverlayActive.Reset()
+	md.ZoneDevicesTotal.Reset()
+	md.ZoneTemperatureOffsetCelsius.Reset()
+	md.ZoneFrostProtectionActive.Reset()
+	md.ZoneOverlayRemainingSeconds.Reset()
+}
+
+// DeleteZone removes every series of a zone, e.g. once the zone no longer exists in its home
+func (md *MetricDescriptors) DeleteZone(homeID, zoneID string) {
+	labels := prometheus.Labels{"home_id": homeID, "zone_id": zoneID}
+	for _, vec := range md.snapshotGaugeVecs() {
+		vec.DeletePartialMatch(labels)
+	}
+}
+
+// DeleteZoneSeries removes the series with exactly labelValues, given in ZoneLabelNames order,
+// e.g. a zone's old series after it was renamed or changed type
+func (md *MetricDescriptors) DeleteZoneSeries(labelValues ...string) {
+	for _, vec := range md.snapshotGaugeVecs() {
+		vec.DeleteLabelValues(labelValues...)
+	}
 }
 
 // CelsiusToFahrenheit converts Celsius to Fahrenheit
 func CelsiusToFahrenheit(celsius float64) float64 {
 	return celsius*9/5 + 32
 }
+
+// FahrenheitToCelsius converts Fahrenheit to Celsius
+func FahrenheitToCelsius(fahrenheit float64) float64 {
+	return (fahrenheit - 32) * 5 / 9
+}
+
+// CelsiusToKelvin converts Celsius to Kelvin
+func CelsiusToKelvin(celsius float64) float64 {
+	return celsius + 273.15
+}
@@ -0,0 +1,129 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMetricDescriptorsValidate tests that valid descriptors pass and a duplicate metric name is reported
+func TestMetricDescriptorsValidate(t *testing.T) {
+	for _, compat := range []MetricCompat{MetricCompatV1, MetricCompatV2} {
+		md, err := NewMetricDescriptorsUnregisteredWithCompat(compat)
+		require.NoError(t, err)
+		assert.NoError(t, md.Validate(), "compat %s", compat)
+	}
+
+	md, err := NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+
+	// Reuse the name of another zone metric
+	md.ZoneACFanSpeed = *prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "tado_zone_ac_mode",
+		Help: "Duplicate of tado_zone_ac_mode",
+	}, ZoneLabelNames)
+
+	err = md.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid Tado metric descriptors")
+	assert.Contains(t, err.Error(), "tado_zone_ac_mode")
+}
+
+// TestRegisterWithTwiceNamesMetric tests that registering the metrics twice reports which metric was already registered
+func TestRegisterWithTwiceNamesMetric(t *testing.T) {
+	md, err := NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+
+	registry := prometheus.NewRegistry()
+	require.NoError(t, md.RegisterWith(registry))
+
+	err = md.RegisterWith(registry)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "metric tado_is_resident_present is already registered")
+
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	assert.ErrorAs(t, err, &alreadyRegistered, "the client library error stays available to callers")
+}
+
+// TestConstantLabels tests that constant labels appear on gathered Tado and exporter metrics
+func TestConstantLabels(t *testing.T) {
+	constLabels := prometheus.Labels{"site": "london", "env": "prod"}
+
+	md, err := NewMetricDescriptorsUnregisteredWithLabels(MetricCompatV1, constLabels)
+	require.NoError(t, err)
+	require.NoError(t, md.Validate())
+	exporterMetrics := NewExporterMetricsUnregisteredWithLabels(constLabels)
+
+	registry := prometheus.NewRegistry()
+	require.NoError(t, md.RegisterWith(registry))
+	require.NoError(t, exporterMetrics.RegisterWith(registry))
+
+	md.IsResidentPresent.Set(1)
+	md.TemperatureMeasuredCelsius.WithLabelValues("123", "1", "Living Room", "HEATING").Set(20.5)
+	exporterMetrics.IncrementScrapeErrors()
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	checked := map[string]bool{}
+	for _, family := range families {
+		switch family.GetName() {
+		case "tado_is_resident_present", "tado_temperature_measured_celsius", "tado_exporter_scrape_errors_total":
+		default:
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			assert.Equal(t, "london", labels["site"], family.GetName())
+			assert.Equal(t, "prod", labels["env"], family.GetName())
+		}
+		checked[family.GetName()] = true
+	}
+	assert.Len(t, checked, 3, "all checked metrics should be gathered")
+}
+
+// TestFahrenheitToCelsius tests the Fahrenheit to Celsius conversion
+func TestFahrenheitToCelsius(t *testing.T) {
+	assert.InDelta(t, 20, FahrenheitToCelsius(68), 1e-9)
+	assert.InDelta(t, -40, FahrenheitToCelsius(-40), 1e-9)
+	assert.InDelta(t, 68, CelsiusToFahrenheit(FahrenheitToCelsius(68)), 1e-9)
+}
+
+// TestCelsiusToKelvin tests the Celsius to Kelvin conversion
+func TestCelsiusToKelvin(t *testing.T) {
+	assert.InDelta(t, 293.15, CelsiusToKelvin(20), 1e-9)
+	assert.InDelta(t, 0, CelsiusToKelvin(-273.15), 1e-9)
+}
+
+// TestParseTemperatureUnits tests parsing temperature units, defaulting to Celsius and Fahrenheit
+func TestParseTemperatureUnits(t *testing.T) {
+	units, err := ParseTemperatureUnits(nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultTemperatureUnits, units)
+
+	units, err = ParseTemperatureUnits([]string{"Celsius", "kelvin"})
+	require.NoError(t, err)
+	assert.Equal(t, []TemperatureUnit{TemperatureUnitCelsius, TemperatureUnitKelvin}, units)
+
+	_, err = ParseTemperatureUnits([]string{"rankine"})
+	assert.ErrorContains(t, err, `unknown temperature unit "rankine"`)
+}
+
+// TestParseDeviceMetricsLevel tests parsing the device metrics level, defaulting to off
+func TestParseDeviceMetricsLevel(t *testing.T) {
+	level, err := ParseDeviceMetricsLevel("")
+	require.NoError(t, err)
+	assert.Equal(t, DeviceMetricsOff, level)
+
+	level, err = ParseDeviceMetricsLevel("Basic")
+	require.NoError(t, err)
+	assert.Equal(t, DeviceMetricsBasic, level)
+
+	_, err = ParseDeviceMetricsLevel("verbose")
+	assert.ErrorContains(t, err, `unknown device metrics level "verbose"`)
+}
@@ -0,0 +1,342 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// otlpMetricsPath is the OTLP/HTTP path metrics are pushed to when the endpoint has no path
+const otlpMetricsPath = "/v1/metrics"
+
+// otlpServiceName is the service.name resource attribute of pushed metrics
+const otlpServiceName = "tado-prometheus-exporter"
+
+// otlpScopeName is the instrumentation scope of pushed metrics
+const otlpScopeName = "github.com/andreweacott/tado-prometheus-exporter"
+
+// otlpCumulative is OTLP's AGGREGATION_TEMPORALITY_CUMULATIVE, matching Prometheus counters and histograms
+const otlpCumulative = 2
+
+// OTLPExporter pushes the metrics of a Prometheus gatherer to an OTLP/HTTP receiver as JSON
+// Each push gathers the metrics; metric names, help and labels are kept, with labels becoming data point attributes
+type OTLPExporter struct {
+	url       string
+	gatherer  prometheus.Gatherer
+	client    *http.Client
+	startTime time.Time // Start of the cumulative counters and histograms
+	now       func() time.Time
+	resource  []otlpAttribute // Resource attributes identifying the exporter, starting with service.name
+}
+
+// NewOTLPExporter creates an exporter pushing gatherer's metrics to endpoint
+// endpoint is the receiver's base URL, e.g. http://otel-collector:4318; /v1/metrics is added
+// unless it already has a path
+func NewOTLPExporter(endpoint string, gatherer prometheus.Gatherer) *OTLPExporter {
+	pushURL := endpoint
+	if parsed, err := url.Parse(endpoint); err == nil && (parsed.Path == "" || parsed.Path == "/") {
+		parsed.Path = otlpMetricsPath
+		pushURL = parsed.String()
+	}
+	return &OTLPExporter{
+		url:       pushURL,
+		gatherer:  gatherer,
+		client:    &http.Client{Timeout: 30 * time.Second},
+		startTime: time.Now(),
+		now:       time.Now,
+		resource:  []otlpAttribute{otlpStringAttribute("service.name", otlpServiceName)},
+	}
+}
+
+// WithResource adds the service.version and home_id resource attributes, so downstream systems can tell
+// which exporter build and home the metrics came from; empty values are left out
+func (e *OTLPExporter) WithResource(serviceVersion, homeID string) *OTLPExporter {
+	if serviceVersion != "" {
+		e.resource = append(e.resource, otlpStringAttribute("service.version", serviceVersion))
+	}
+	if homeID != "" {
+		e.resource = append(e.resource, otlpStringAttribute("home_id", homeID))
+	}
+	return e
+}
+
+// Push gathers the metrics and sends them to the receiver
+// Metrics that were gathered are pushed even if gathering also reported an error
+func (e *OTLPExporter) Push(ctx context.Context) error {
+	families, gatherErr := e.gatherer.Gather()
+
+	body, err := json.Marshal(e.request(families))
+	if err != nil {
+		return fmt.Errorf("failed to encode OTLP metrics: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create OTLP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push OTLP metrics: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("OTLP receiver returned status %d", resp.StatusCode)
+	}
+	if gatherErr != nil {
+		return fmt.Errorf("pushed partial metrics: %w", gatherErr)
+	}
+	return nil
+}
+
+// OTLP/HTTP JSON request, following the opentelemetry-proto JSON mapping
+// 64-bit integers are encoded as strings, and NaN and infinite doubles as "NaN", "Infinity" and "-Infinity",
+// as the mapping requires
+type (
+	otlpRequest struct {
+		ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+	}
+	otlpResourceMetrics struct {
+		Resource     otlpResource       `json:"resource"`
+		ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+	}
+	otlpResource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	}
+	otlpScopeMetrics struct {
+		Scope   otlpScope    `json:"scope"`
+		Metrics []otlpMetric `json:"metrics"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpMetric struct {
+		Name        string         `json:"name"`
+		Description string         `json:"description,omitempty"`
+		Gauge       *otlpGauge     `json:"gauge,omitempty"`
+		Sum         *otlpSum       `json:"sum,omitempty"`
+		Histogram   *otlpHistogram `json:"histogram,omitempty"`
+		Summary     *otlpSummary   `json:"summary,omitempty"`
+	}
+	otlpGauge struct {
+		DataPoints []otlpNumberDataPoint `json:"dataPoints"`
+	}
+	otlpSum struct {
+		DataPoints             []otlpNumberDataPoint `json:"dataPoints"`
+		AggregationTemporality int                   `json:"aggregationTemporality"`
+		IsMonotonic            bool                  `json:"isMonotonic"`
+	}
+	otlpHistogram struct {
+		DataPoints             []otlpHistogramDataPoint `json:"dataPoints"`
+		AggregationTemporality int                      `json:"aggregationTemporality"`
+	}
+	otlpSummary struct {
+		DataPoints []otlpSummaryDataPoint `json:"dataPoints"`
+	}
+	otlpNumberDataPoint struct {
+		Attributes        []otlpAttribute `json:"attributes,omitempty"`
+		StartTimeUnixNano string          `json:"startTimeUnixNano,omitempty"`
+		TimeUnixNano      string          `json:"timeUnixNano"`
+		AsDouble          otlpDouble      `json:"asDouble"`
+	}
+	otlpHistogramDataPoint struct {
+		Attributes        []otlpAttribute `json:"attributes,omitempty"`
+		StartTimeUnixNano string          `json:"startTimeUnixNano"`
+		TimeUnixNano      string          `json:"timeUnixNano"`
+		Count             string          `json:"count"`
+		Sum               otlpDouble      `json:"sum"`
+		BucketCounts      []string        `json:"bucketCounts"`
+		ExplicitBounds    []float64       `json:"explicitBounds"`
+	}
+	otlpSummaryDataPoint struct {
+		Attributes     []otlpAttribute     `json:"attributes,omitempty"`
+		TimeUnixNano   string              `json:"timeUnixNano"`
+		Count          string              `json:"count"`
+		Sum            otlpDouble          `json:"sum"`
+		QuantileValues []otlpQuantileValue `json:"quantileValues,omitempty"`
+	}
+	otlpQuantileValue struct {
+		Quantile float64    `json:"quantile"`
+		Value    otlpDouble `json:"value"`
+	}
+	otlpAttribute struct {
+		Key   string       `json:"key"`
+		Value otlpAnyValue `json:"value"`
+	}
+	otlpAnyValue struct {
+		StringValue string `json:"stringValue"`
+	}
+)
+
+// otlpDouble is a double encoded per the OTLP JSON mapping, which json.Marshal would reject when NaN or infinite
+type otlpDouble float64
+
+// MarshalJSON encodes NaN and infinities as the strings the mapping uses, and other values as numbers
+func (d otlpDouble) MarshalJSON() ([]byte, error) {
+	value := float64(d)
+	switch {
+	case math.IsNaN(value):
+		return []byte(`"NaN"`), nil
+	case math.IsInf(value, 1):
+		return []byte(`"Infinity"`), nil
+	case math.IsInf(value, -1):
+		return []byte(`"-Infinity"`), nil
+	}
+	return json.Marshal(value)
+}
+
+// UnmarshalJSON decodes a number, or one of the strings MarshalJSON uses for NaN and infinities
+func (d *otlpDouble) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		var value float64
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		*d = otlpDouble(value)
+		return nil
+	}
+	switch text {
+	case "NaN":
+		*d = otlpDouble(math.NaN())
+	case "Infinity":
+		*d = otlpDouble(math.Inf(1))
+	case "-Infinity":
+		*d = otlpDouble(math.Inf(-1))
+	default:
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return fmt.Errorf("invalid OTLP double %q: %w", text, err)
+		}
+		*d = otlpDouble(value)
+	}
+	return nil
+}
+
+// request converts gathered metric families to an OTLP request
+func (e *OTLPExporter) request(families []*dto.MetricFamily) otlpRequest {
+	now := unixNano(e.now())
+	start := unixNano(e.startTime)
+
+	metrics := make([]otlpMetric, 0, len(families))
+	for _, family := range families {
+		metric := otlpMetric{Name: family.GetName(), Description: family.GetHelp()}
+		switch family.GetType() {
+		case dto.MetricType_COUNTER:
+			sum := &otlpSum{AggregationTemporality: otlpCumulative, IsMonotonic: true}
+			for _, m := range family.Metric {
+				sum.DataPoints = append(sum.DataPoints, otlpNumberDataPoint{
+					Attributes:        otlpAttributes(m.Label),
+					StartTimeUnixNano: start,
+					TimeUnixNano:      now,
+					AsDouble:          otlpDouble(m.GetCounter().GetValue()),
+				})
+			}
+			metric.Sum = sum
+		case dto.MetricType_HISTOGRAM:
+			histogram := &otlpHistogram{AggregationTemporality: otlpCumulative}
+			for _, m := range family.Metric {
+				histogram.DataPoints = append(histogram.DataPoints, otlpHistogramPoint(m, start, now))
+			}
+			metric.Histogram = histogram
+		case dto.MetricType_SUMMARY:
+			summary := &otlpSummary{}
+			for _, m := range family.Metric {
+				point := otlpSummaryDataPoint{
+					Attributes:   otlpAttributes(m.Label),
+					TimeUnixNano: now,
+					Count:        strconv.FormatUint(m.GetSummary().GetSampleCount(), 10),
+					Sum:          otlpDouble(m.GetSummary().GetSampleSum()),
+				}
+				for _, q := range m.GetSummary().GetQuantile() {
+					point.QuantileValues = append(point.QuantileValues, otlpQuantileValue{Quantile: q.GetQuantile(), Value: otlpDouble(q.GetValue())})
+				}
+				summary.DataPoints = append(summary.DataPoints, point)
+			}
+			metric.Summary = summary
+		default:
+			// Gauges, and untyped metrics which carry no more than a gauge
+			gauge := &otlpGauge{}
+			for _, m := range family.Metric {
+				value := m.GetGauge().GetValue()
+				if m.Untyped != nil {
+					value = m.GetUntyped().GetValue()
+				}
+				gauge.DataPoints = append(gauge.DataPoints, otlpNumberDataPoint{
+					Attributes:   otlpAttributes(m.Label),
+					TimeUnixNano: now,
+					AsDouble:     otlpDouble(value),
+				})
+			}
+			metric.Gauge = gauge
+		}
+		metrics = append(metrics, metric)
+	}
+
+	return otlpRequest{ResourceMetrics: []otlpResourceMetrics{{
+		Resource: otlpResource{Attributes: e.resource},
+		ScopeMetrics: []otlpScopeMetrics{{
+			Scope:   otlpScope{Name: otlpScopeName},
+			Metrics: metrics,
+		}},
+	}}}
+}
+
+// otlpHistogramPoint converts a Prometheus histogram, whose buckets are cumulative, to an OTLP
+// data point with a count per bucket and a final overflow bucket
+func otlpHistogramPoint(m *dto.Metric, start, now string) otlpHistogramDataPoint {
+	h := m.GetHistogram()
+	point := otlpHistogramDataPoint{
+		Attributes:        otlpAttributes(m.Label),
+		StartTimeUnixNano: start,
+		TimeUnixNano:      now,
+		Count:             strconv.FormatUint(h.GetSampleCount(), 10),
+		Sum:               otlpDouble(h.GetSampleSum()),
+		BucketCounts:      []string{},
+		ExplicitBounds:    []float64{},
+	}
+
+	var previous uint64
+	for _, bucket := range h.GetBucket() {
+		if math.IsInf(bucket.GetUpperBound(), 1) {
+			continue
+		}
+		point.ExplicitBounds = append(point.ExplicitBounds, bucket.GetUpperBound())
+		point.BucketCounts = append(point.BucketCounts, strconv.FormatUint(bucket.GetCumulativeCount()-previous, 10))
+		previous = bucket.GetCumulativeCount()
+	}
+	point.BucketCounts = append(point.BucketCounts, strconv.FormatUint(h.GetSampleCount()-previous, 10))
+	return point
+}
+
+// otlpAttributes converts Prometheus labels to OTLP string attributes
+func otlpAttributes(labels []*dto.LabelPair) []otlpAttribute {
+	attributes := make([]otlpAttribute, 0, len(labels))
+	for _, label := range labels {
+		attributes = append(attributes, otlpStringAttribute(label.GetName(), label.GetValue()))
+	}
+	return attributes
+}
+
+// otlpStringAttribute returns a string-valued attribute
+func otlpStringAttribute(key, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpAnyValue{StringValue: value}}
+}
+
+// unixNano formats t as OTLP's string-encoded nanoseconds since the Unix epoch
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
@@ -0,0 +1,146 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOTLPExporterPush tests that pushed metrics arrive at an OTLP receiver with their names, types and labels
+func TestOTLPExporterPush(t *testing.T) {
+	var received otlpRequest
+	var path, contentType string
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, contentType = r.URL.Path, r.Header.Get("Content-Type")
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &received))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	md, err := NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+	em := NewExporterMetricsUnregistered()
+	registry := prometheus.NewRegistry()
+	require.NoError(t, md.RegisterWith(registry))
+	require.NoError(t, em.RegisterWith(registry))
+
+	md.TemperatureMeasuredCelsius.WithLabelValues("1", "2", "Living Room", "HEATING").Set(20.5)
+	em.IncrementScrapeErrors()
+	em.RecordZonesObserved(3)
+
+	require.NoError(t, NewOTLPExporter(receiver.URL, registry).Push(context.Background()))
+
+	assert.Equal(t, "/v1/metrics", path)
+	assert.Equal(t, "application/json", contentType)
+	require.Len(t, received.ResourceMetrics, 1)
+	require.Len(t, received.ResourceMetrics[0].ScopeMetrics, 1)
+
+	byName := map[string]otlpMetric{}
+	for _, metric := range received.ResourceMetrics[0].ScopeMetrics[0].Metrics {
+		byName[metric.Name] = metric
+	}
+
+	temperature, ok := byName["tado_temperature_measured_celsius"]
+	require.True(t, ok)
+	require.NotNil(t, temperature.Gauge)
+	require.Len(t, temperature.Gauge.DataPoints, 1)
+	assert.Equal(t, otlpDouble(20.5), temperature.Gauge.DataPoints[0].AsDouble)
+	assert.Contains(t, temperature.Gauge.DataPoints[0].Attributes, otlpAttribute{Key: "zone_name", Value: otlpAnyValue{StringValue: "Living Room"}})
+
+	scrapeErrors, ok := byName["tado_exporter_scrape_errors_total"]
+	require.True(t, ok)
+	require.NotNil(t, scrapeErrors.Sum)
+	assert.True(t, scrapeErrors.Sum.IsMonotonic)
+	assert.Equal(t, otlpDouble(1), scrapeErrors.Sum.DataPoints[0].AsDouble)
+
+	zones, ok := byName["tado_exporter_zones_observed"]
+	require.True(t, ok)
+	require.NotNil(t, zones.Histogram)
+	point := zones.Histogram.DataPoints[0]
+	assert.Equal(t, "1", point.Count)
+	assert.Len(t, point.BucketCounts, len(point.ExplicitBounds)+1, "OTLP histograms have an overflow bucket")
+}
+
+// TestOTLPExporterResource tests that pushed metrics carry the resource attributes identifying the exporter
+func TestOTLPExporterResource(t *testing.T) {
+	var received otlpRequest
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	require.NoError(t, NewOTLPExporter(receiver.URL, prometheus.NewRegistry()).WithResource("1.2.3", "12345").Push(context.Background()))
+	require.Len(t, received.ResourceMetrics, 1)
+	assert.Equal(t, []otlpAttribute{
+		{Key: "service.name", Value: otlpAnyValue{StringValue: "tado-prometheus-exporter"}},
+		{Key: "service.version", Value: otlpAnyValue{StringValue: "1.2.3"}},
+		{Key: "home_id", Value: otlpAnyValue{StringValue: "12345"}},
+	}, received.ResourceMetrics[0].Resource.Attributes)
+
+	// Without a home filter the metrics span homes, so home_id is left to the data point attributes
+	require.NoError(t, NewOTLPExporter(receiver.URL, prometheus.NewRegistry()).WithResource("1.2.3", "").Push(context.Background()))
+	assert.Equal(t, []otlpAttribute{
+		{Key: "service.name", Value: otlpAnyValue{StringValue: "tado-prometheus-exporter"}},
+		{Key: "service.version", Value: otlpAnyValue{StringValue: "1.2.3"}},
+	}, received.ResourceMetrics[0].Resource.Attributes)
+}
+
+// TestOTLPExporterPushFailure tests that a receiver error is reported
+func TestOTLPExporterPushFailure(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	err := NewOTLPExporter(receiver.URL+"/custom/path", prometheus.NewRegistry()).Push(context.Background())
+	assert.ErrorContains(t, err, "status 503")
+}
+
+// TestOTLPExporterPushNonFinite tests that NaN and infinite samples are pushed as the OTLP JSON mapping's strings
+// instead of failing the whole push
+func TestOTLPExporterPushNonFinite(t *testing.T) {
+	var body []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		body, err = io.ReadAll(r.Body)
+		require.NoError(t, err)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	registry := prometheus.NewRegistry()
+	gauges := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_value", Help: "Test value"}, []string{"kind"})
+	require.NoError(t, registry.Register(gauges))
+	gauges.WithLabelValues("nan").Set(math.NaN())
+	gauges.WithLabelValues("inf").Set(math.Inf(1))
+	gauges.WithLabelValues("-inf").Set(math.Inf(-1))
+	gauges.WithLabelValues("finite").Set(1.5)
+
+	require.NoError(t, NewOTLPExporter(receiver.URL, registry).Push(context.Background()))
+	assert.Contains(t, string(body), `"asDouble":"NaN"`)
+	assert.Contains(t, string(body), `"asDouble":"Infinity"`)
+	assert.Contains(t, string(body), `"asDouble":"-Infinity"`)
+	assert.Contains(t, string(body), `"asDouble":1.5`)
+
+	var received otlpRequest
+	require.NoError(t, json.Unmarshal(body, &received))
+	values := map[string]float64{}
+	for _, point := range received.ResourceMetrics[0].ScopeMetrics[0].Metrics[0].Gauge.DataPoints {
+		values[point.Attributes[0].Value.StringValue] = float64(point.AsDouble)
+	}
+	assert.True(t, math.IsNaN(values["nan"]))
+	assert.True(t, math.IsInf(values["inf"], 1))
+	assert.True(t, math.IsInf(values["-inf"], -1))
+	assert.Equal(t, 1.5, values["finite"])
+}
@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// descNamePattern extracts the metric name from a prometheus.Desc's String()
+var descNamePattern = regexp.MustCompile(`fqName: "([^"]*)"`)
+
+// register registers collector with registerer, turning a repeated registration into an error
+// naming the metric instead of the client library's generic duplicate registration message
+func register(registerer prometheus.Registerer, collector prometheus.Collector) error {
+	err := registerer.Register(collector)
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if errors.As(err, &alreadyRegistered) {
+		return fmt.Errorf("metric %s is already registered: metrics can only be registered once per registry, "+
+			"use the Unregistered constructors with a dedicated registry when creating them more than once: %w",
+			collectorNames(collector), err)
+	}
+	return err
+}
+
+// collectorNames returns the comma-separated names of the metrics described by collector
+func collectorNames(collector prometheus.Collector) string {
+	ch := make(chan *prometheus.Desc)
+	go func() {
+		collector.Describe(ch)
+		close(ch)
+	}()
+
+	var names []string
+	for desc := range ch {
+		if match := descNamePattern.FindStringSubmatch(desc.String()); match != nil {
+			names = append(names, match[1])
+		}
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,205 @@
+// Package metrics provides snapshot persistence for Tado metric values.
+//
+// A snapshot is written on shutdown and loaded on startup so that /metrics
+// serves the last known values immediately after a restart instead of empty
+// series until the first successful scrape.
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// snapshotFile is the on-disk representation of a metrics snapshot
+type snapshotFile struct {
+	Timestamp time.Time        `json:"timestamp"`
+	Samples   []snapshotSample `json:"samples"`
+}
+
+// snapshotSample is a single gauge series in a snapshot
+type snapshotSample struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Value  float64           `json:"value"`
+}
+
+// SaveSnapshot writes the current Tado metric values to path
+// The file is written atomically so a crash mid-write never leaves a truncated snapshot
+func (md *MetricDescriptors) SaveSnapshot(path string) error {
+	// Gather through a private registry so the snapshot contains exactly these metrics,
+	// regardless of which registry they are served from
+	registry := prometheus.NewRegistry()
+	if err := md.RegisterWith(registry); err != nil {
+		return fmt.Errorf("failed to register metrics for snapshot: %w", err)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		return fmt.Errorf("failed to gather metrics for snapshot: %w", err)
+	}
+
+	snapshot := snapshotFile{Timestamp: time.Now()}
+	for _, family := range families {
+		for _, m := range family.Metric {
+			if m.Gauge == nil {
+				continue
+			}
+			sample := snapshotSample{Name: family.GetName(), Value: m.GetGauge().GetValue()}
+			if len(m.Label) > 0 {
+				sample.Labels = make(map[string]string, len(m.Label))
+				for _, pair := range m.Label {
+					sample.Labels[pair.GetName()] = pair.GetValue()
+				}
+			}
+			snapshot.Samples = append(snapshot.Samples, sample)
+		}
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadSnapshot restores metric values from a snapshot written by SaveSnapshot
+// Snapshots older than maxAge are ignored (0 accepts any age). A missing file is not an error.
+// Returns the number of series restored.
+func (md *MetricDescriptors) LoadSnapshot(path string, maxAge time.Duration) (int, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var snapshot snapshotFile
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return 0, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+
+	if maxAge > 0 && time.Since(snapshot.Timestamp) > maxAge {
+		return 0, nil
+	}
+
+	gauges := md.snapshotGauges()
+	gaugeVecs := md.snapshotGaugeVecs()
+	for name, vec := range md.snapshotHomeGaugeVecs() {
+		gaugeVecs[name] = vec
+	}
+
+	restored := 0
+	for _, sample := range snapshot.Samples {
+		if gauge, ok := gauges[sample.Name]; ok {
+			gauge.Set(sample.Value)
+			restored++
+			continue
+		}
+		if vec, ok := gaugeVecs[sample.Name]; ok {
+			// Skip series whose labels no longer match the metric definition
+			gauge, err := vec.GetMetricWith(md.variableLabels(sample.Labels))
+			if err != nil {
+				continue
+			}
+			gauge.Set(sample.Value)
+			restored++
+		}
+	}
+	return restored, nil
+}
+
+// snapshotGauges maps exposed metric names to the unlabelled gauges restored from snapshots
+func (md *MetricDescriptors) snapshotGauges() map[string]prometheus.Gauge {
+	return exposedNames(md.compat, map[string]prometheus.Gauge{
+		"tado_is_resident_present":            md.IsResidentPresent,
+		"tado_solar_intensity_percentage":     md.SolarIntensityPercentage,
+		"tado_temperature_outside_celsius":    md.TemperatureOutsideCelsius,
+		"tado_temperature_outside_fahrenheit": md.TemperatureOutsideFahrenheit,
+	})
+}
+
+// variableLabels returns the labels of a saved series without the constant labels, which the
+// gauge vectors add themselves
+func (md *MetricDescriptors) variableLabels(labels map[string]string) prometheus.Labels {
+	if len(md.constLabels) == 0 {
+		return labels
+	}
+	variable := make(prometheus.Labels, len(labels))
+	for name, value := range labels {
+		if _, ok := md.constLabels[name]; !ok {
+			variable[name] = value
+		}
+	}
+	return variable
+}
+
+// snapshotGaugeVecs maps exposed metric names to the labelled gauges restored from snapshots
+func (md *MetricDescriptors) snapshotGaugeVecs() map[string]*prometheus.GaugeVec {
+	return exposedNames(md.compat, map[string]*prometheus.GaugeVec{
+		"tado_temperature_measured_celsius":      &md.TemperatureMeasuredCelsius,
+		"tado_temperature_measured_fahrenheit":   &md.TemperatureMeasuredFahrenheit,
+		"tado_temperature_measured_kelvin":       &md.TemperatureMeasuredKelvin,
+		"tado_humidity_measured_percentage":      &md.HumidityMeasuredPercentage,
+		"tado_temperature_set_celsius":           &md.TemperatureSetCelsius,
+		"tado_temperature_set_fahrenheit":        &md.TemperatureSetFahrenheit,
+		"tado_heating_power_percentage":          &md.HeatingPowerPercentage,
+		"tado_is_window_open":                    &md.IsWindowOpen,
+		"tado_is_zone_powered":                   &md.IsZonePowered,
+		"tado_zone_data_present":                 &md.ZoneDataPresent,
+		"tado_zone_ac_mode":                      &md.ZoneACMode,
+		"tado_zone_ac_power":                     &md.ZoneACPower,
+		"tado_zone_ac_fan_speed":                 &md.ZoneACFanSpeed,
+		"tado_zone_indoor_outdoor_delta_celsius": &md.ZoneIndoorOutdoorDelta,
+		"tado_zone_child_lock_enabled":           &md.ZoneChildLockEnabled,
+		"tado_zone_dazzle_mode_enabled":          &md.ZoneDazzleModeEnabled,
+		"tado_hot_water_overlay_active":          &md.HotWaterOverlayActive,
+		"tado_zone_devices_total":                &md.ZoneDevicesTotal,
+		"tado_zone_temperature_offset_celsius":   &md.ZoneTemperatureOffsetCelsius,
+		"tado_zone_frost_protection_active":      &md.ZoneFrostProtectionActive,
+		"tado_zone_overlay_remaining_seconds":    &md.ZoneOverlayRemainingSeconds,
+	})
+}
+
+// snapshotHomeGaugeVecs maps exposed metric names to the gauges labelled by home_id restored from snapshots
+// They are kept apart from snapshotGaugeVecs, which lists the zone metrics removed with a zone
+func (md *MetricDescriptors) snapshotHomeGaugeVecs() map[string]*prometheus.GaugeVec {
+	return exposedNames(md.compat, map[string]*prometheus.GaugeVec{
+		"tado_home_presence_locked":       &md.HomePresenceLocked,
+		"tado_home_bridge_connected":      &md.HomeBridgeConnected,
+		"tado_home_devices_total":         &md.HomeDevicesTotal,
+		"tado_home_devices_at_home_total": &md.HomeDevicesAtHomeTotal,
+		"tado_weather_is_daytime":         &md.WeatherIsDaytime,
+	})
+}
+
+// exposedNames re-keys a map from legacy metric names to the names exposed under compat
+func exposedNames[T any](compat MetricCompat, byLegacyName map[string]T) map[string]T {
+	exposed := make(map[string]T, len(byLegacyName))
+	for legacyName, value := range byLegacyName {
+		exposed[compat.metricName(legacyName)] = value
+	}
+	return exposed
+}
@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSnapshotRoundTrip tests that saved metric values are restored into fresh descriptors
+func TestSnapshotRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+
+	original, err := NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+
+	original.IsResidentPresent.Set(1)
+	original.TemperatureOutsideCelsius.Set(12.5)
+	original.HomeDevicesTotal.WithLabelValues("123").Set(7)
+	original.TemperatureMeasuredCelsius.WithLabelValues("123", "1", "Living Room", "HEATING").Set(20.5)
+	original.HumidityMeasuredPercentage.WithLabelValues("123", "2", "Bedroom", "HEATING").Set(45)
+
+	require.NoError(t, original.SaveSnapshot(path))
+
+	restored, err := NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+
+	count, err := restored.LoadSnapshot(path, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 7, count, "4 unlabelled home-level gauges, 1 home series and 2 zone series should be restored")
+
+	registry := prometheus.NewRegistry()
+	require.NoError(t, restored.RegisterWith(registry))
+
+	assert.Equal(t, 1.0, testGaugeValue(t, registry, "tado_is_resident_present"))
+	assert.Equal(t, 12.5, testGaugeValue(t, registry, "tado_temperature_outside_celsius"))
+	assert.Equal(t, 7.0, testGaugeValue(t, registry, "tado_home_devices_total"))
+	assert.Equal(t, 20.5, testGaugeValue(t, registry, "tado_temperature_measured_celsius"))
+	assert.Equal(t, 45.0, testGaugeValue(t, registry, "tado_humidity_measured_percentage"))
+}
+
+// TestSnapshotRoundTrip_ConstantLabels tests that zone series are restored when constant labels are set
+func TestSnapshotRoundTrip_ConstantLabels(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	constLabels := prometheus.Labels{"site": "london"}
+
+	original, err := NewMetricDescriptorsUnregisteredWithLabels(MetricCompatV1, constLabels)
+	require.NoError(t, err)
+	original.TemperatureMeasuredCelsius.WithLabelValues("123", "1", "Living Room", "HEATING").Set(20.5)
+	require.NoError(t, original.SaveSnapshot(path))
+
+	restored, err := NewMetricDescriptorsUnregisteredWithLabels(MetricCompatV1, constLabels)
+	require.NoError(t, err)
+	_, err = restored.LoadSnapshot(path, time.Hour)
+	require.NoError(t, err)
+
+	registry := prometheus.NewRegistry()
+	require.NoError(t, restored.RegisterWith(registry))
+	assert.Equal(t, 20.5, testGaugeValue(t, registry, "tado_temperature_measured_celsius"))
+}
+
+// TestLoadSnapshot_TooOld tests that snapshots older than the maximum age are ignored
+func TestLoadSnapshot_TooOld(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	stale := `{"timestamp":"2020-01-01T00:00:00Z","samples":[{"name":"tado_is_resident_present","value":1}]}`
+	require.NoError(t, os.WriteFile(path, []byte(stale), 0600))
+
+	md, err := NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+
+	count, err := md.LoadSnapshot(path, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	// A max age of 0 accepts any snapshot
+	count, err = md.LoadSnapshot(path, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+// TestLoadSnapshot_Missing tests that a missing snapshot file is not an error
+func TestLoadSnapshot_Missing(t *testing.T) {
+	md, err := NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+
+	count, err := md.LoadSnapshot(filepath.Join(t.TempDir(), "missing.json"), time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+// TestLoadSnapshot_Invalid tests that corrupt snapshots and mismatched labels are handled
+func TestLoadSnapshot_Invalid(t *testing.T) {
+	dir := t.TempDir()
+
+	md, err := NewMetricDescriptorsUnregistered()
+	require.NoError(t, err)
+
+	corrupt := filepath.Join(dir, "corrupt.json")
+	require.NoError(t, os.WriteFile(corrupt, []byte("not json"), 0600))
+	_, err = md.LoadSnapshot(corrupt, 0)
+	assert.Error(t, err)
+
+	// Series whose labels don't match the current definition are skipped
+	mismatched := filepath.Join(dir, "mismatched.json")
+	content := `{"timestamp":"2020-01-01T00:00:00Z","samples":[{"name":"tado_is_window_open","labels":{"zone_id":"1"},"value":1}]}`
+	require.NoError(t, os.WriteFile(mismatched, []byte(content), 0600))
+	count, err := md.LoadSnapshot(mismatched, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
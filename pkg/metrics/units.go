@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TemperatureUnit is a unit the temperature metrics are exported in
+type TemperatureUnit string
+
+const (
+	// TemperatureUnitCelsius exports the *_celsius temperature metrics
+	TemperatureUnitCelsius TemperatureUnit = "celsius"
+
+	// TemperatureUnitFahrenheit exports the *_fahrenheit temperature metrics
+	TemperatureUnitFahrenheit TemperatureUnit = "fahrenheit"
+
+	// TemperatureUnitKelvin exports tado_temperature_measured_kelvin
+	TemperatureUnitKelvin TemperatureUnit = "kelvin"
+)
+
+// DefaultTemperatureUnits are the units exported when none are configured
+var DefaultTemperatureUnits = []TemperatureUnit{TemperatureUnitCelsius, TemperatureUnitFahrenheit}
+
+// ParseTemperatureUnits parses temperature unit names; no values select DefaultTemperatureUnits
+func ParseTemperatureUnits(values []string) ([]TemperatureUnit, error) {
+	if len(values) == 0 {
+		return DefaultTemperatureUnits, nil
+	}
+
+	units := make([]TemperatureUnit, 0, len(values))
+	for _, value := range values {
+		switch unit := TemperatureUnit(strings.ToLower(value)); unit {
+		case TemperatureUnitCelsius, TemperatureUnitFahrenheit, TemperatureUnitKelvin:
+			units = append(units, unit)
+		default:
+			return nil, fmt.Errorf("unknown temperature unit %q (must be one of: celsius, fahrenheit, kelvin)", value)
+		}
+	}
+	return units, nil
+}